@@ -21,17 +21,20 @@ import (
 	"github.com/aws/aws-sdk-go/service/ec2"
 )
 
-type ec2AnsibleEntry struct {
+// Host is a host of any provider to add to an inventory built with BuildHostInventory
+type Host struct {
+	Name      string
+	Region    string
+	PublicIP  string
+	PrivateIP string
+}
+
+type ansibleEntry struct {
 	Name string
 	Host string
 }
 
-// BuildEC2Inventory creates an ansible inventory for EC2 instances
-// Requires a region to []*ec2.Instance Map
-// It can generate the inventory for public (default) or private dns names
-func BuildEC2Inventory(ec2dump map[string][]*ec2.Instance, private bool) (string, error) {
-	var ansibleInv string
-	ansibleTemplate := `
+const ansibleTemplate = `
 	{{- range $key, $value := .}}
 
 [{{ $key }}]
@@ -40,35 +43,97 @@ func BuildEC2Inventory(ec2dump map[string][]*ec2.Instance, private bool) (string
 		{{- end}}
 	{{- end}}
 	`
-	dump := map[string][]ec2AnsibleEntry{}
+
+// BuildEC2Inventory creates an ansible inventory for EC2 instances
+// Requires a region to []*ec2.Instance Map
+// It can generate the inventory for public (default) or private dns names
+func BuildEC2Inventory(ec2dump map[string][]*ec2.Instance, private bool) (string, error) {
+	dump := map[string][]ansibleEntry{}
 	for r, d := range ec2dump {
-		var regionData []ec2AnsibleEntry
+		var regionData []ansibleEntry
 		for _, i := range d {
-			var ansibleHost string
-			if private {
-				ansibleHost = *i.PrivateDnsName
-			} else {
-				ansibleHost = *i.PublicDnsName
+			e, ok := buildEC2Entry(i, private)
+			if !ok {
+				continue
 			}
-			name, err := extractNamefromEC2Tags(i)
-			if err != nil {
-				// Ignore Blank Name instance
+			regionData = append(regionData, e)
+		}
+		dump[r] = regionData
+	}
+	return renderInventory(dump)
+}
+
+// BuildEC2InventoryByASG creates an ansible inventory for EC2 instances grouped by Auto Scaling group
+// Requires a region to []*ec2.Instance Map and an instance ID to group name Map
+// Instances which are not part of any group are left out
+func BuildEC2InventoryByASG(ec2dump map[string][]*ec2.Instance, instanceGroups map[string]string, private bool) (string, error) {
+	dump := map[string][]ansibleEntry{}
+	for _, d := range ec2dump {
+		for _, i := range d {
+			group, ok := instanceGroups[*i.InstanceId]
+			if !ok {
 				continue
 			}
-			e := ec2AnsibleEntry{
-				Name: name,
-				Host: ansibleHost}
-			if e.Host == "" {
+			e, ok := buildEC2Entry(i, private)
+			if !ok {
 				continue
 			}
-			regionData = append(regionData, e)
+			//Make sure group has no spaces
+			group = strings.Replace(group, " ", "", -1)
+			dump[group] = append(dump[group], e)
 		}
-		dump[r] = regionData
-		regionData = []ec2AnsibleEntry{}
 	}
+	return renderInventory(dump)
+}
+
+// BuildHostInventory creates an ansible inventory for hosts of any provider, grouped by region
+// It can generate the inventory for public (default) or private IPs, hosts without one are left out
+func BuildHostInventory(hosts []*Host, private bool) (string, error) {
+	dump := map[string][]ansibleEntry{}
+	for _, h := range hosts {
+		e := ansibleEntry{
+			//Make sure name has no spaces
+			Name: strings.Replace(h.Name, " ", "", -1),
+			Host: h.PublicIP,
+		}
+		if private {
+			e.Host = h.PrivateIP
+		}
+		if e.Name == "" || e.Host == "" {
+			continue
+		}
+		dump[h.Region] = append(dump[h.Region], e)
+	}
+	return renderInventory(dump)
+}
+
+// buildEC2Entry returns the inventory entry of an instance, ok is false for instances
+// without a Name tag or without a DNS name to reach them with
+func buildEC2Entry(i *ec2.Instance, private bool) (ansibleEntry, bool) {
+	var ansibleHost string
+	if private {
+		ansibleHost = *i.PrivateDnsName
+	} else {
+		ansibleHost = *i.PublicDnsName
+	}
+	name, err := extractNamefromEC2Tags(i)
+	if err != nil {
+		// Ignore Blank Name instance
+		return ansibleEntry{}, false
+	}
+	e := ansibleEntry{
+		Name: name,
+		Host: ansibleHost}
+	if e.Host == "" {
+		return e, false
+	}
+	return e, true
+}
+
+func renderInventory(dump map[string][]ansibleEntry) (string, error) {
 	tmpl, err := template.New("ec2").Parse(ansibleTemplate)
 	if err != nil {
-		return ansibleInv, err
+		return "", err
 	}
 	var b bytes.Buffer
 	err = tmpl.Execute(&b, &dump)
@@ -0,0 +1,72 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package ansible
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func testInstance(id, name, dns string) *ec2.Instance {
+	return &ec2.Instance{
+		InstanceId:     aws.String(id),
+		PublicDnsName:  aws.String(dns),
+		PrivateDnsName: aws.String(""),
+		Tags:           []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String(name)}},
+	}
+}
+
+// TestBuildEC2InventoryByASG checks that hosts are grouped by Auto Scaling group and ungrouped hosts are left out
+func TestBuildEC2InventoryByASG(t *testing.T) {
+	ec2dump := map[string][]*ec2.Instance{
+		"us-east-1": {testInstance("i-1", "web 1", "web1.example.com"), testInstance("i-2", "bastion", "bastion.example.com")},
+		"us-west-2": {testInstance("i-3", "web2", "web2.example.com")},
+	}
+	inv, err := BuildEC2InventoryByASG(ec2dump, map[string]string{"i-1": "web asg", "i-3": "web asg"}, false)
+	if err != nil {
+		t.Fatalf("Failed to build inventory: %v", err)
+	}
+	for _, line := range []string{"[webasg]", "web1 ansible_ssh_host=web1.example.com", "web2 ansible_ssh_host=web2.example.com"} {
+		if !strings.Contains(inv, line) {
+			t.Errorf("Inventory is missing %q:\n%s", line, inv)
+		}
+	}
+	if strings.Contains(inv, "bastion") {
+		t.Errorf("Instance outside of any group should not be in the inventory:\n%s", inv)
+	}
+}
+
+// TestBuildHostInventory checks that hosts are grouped by region and hosts without an address are left out
+func TestBuildHostInventory(t *testing.T) {
+	hosts := []*Host{
+		{Name: "web 1", Region: "nyc1", PublicIP: "203.0.113.1", PrivateIP: "10.0.0.1"},
+		{Name: "db", Region: "fsn1", PrivateIP: "10.0.0.2"},
+	}
+	inv, err := BuildHostInventory(hosts, false)
+	if err != nil {
+		t.Fatalf("Failed to build inventory: %v", err)
+	}
+	if !strings.Contains(inv, "[nyc1]\nweb1 ansible_ssh_host=203.0.113.1") || strings.Contains(inv, "db") {
+		t.Errorf("Unexpected public inventory:\n%s", inv)
+	}
+	inv, err = BuildHostInventory(hosts, true)
+	if err != nil {
+		t.Fatalf("Failed to build inventory: %v", err)
+	}
+	if !strings.Contains(inv, "[fsn1]\ndb ansible_ssh_host=10.0.0.2") {
+		t.Errorf("Unexpected private inventory:\n%s", inv)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// GetAllAddresses returns every Elastic IP allocated for a given session
+func GetAllAddresses(sess *session.Session) ([]*ec2.Address, error) {
+	ec2c := ec2.New(sess)
+	var allAddresses []*ec2.Address
+	// DescribeAddresses is not paginated
+	err := withRetry(func() error {
+		result, err := ec2c.DescribeAddresses(&ec2.DescribeAddressesInput{})
+		if err == nil {
+			allAddresses = result.Addresses
+		}
+		return err
+	})
+	return allAddresses, err
+}
+
+// GetAllNetworkInterfaces returns a complete list of network interfaces for a given session
+func GetAllNetworkInterfaces(sess *session.Session) ([]*ec2.NetworkInterface, error) {
+	ec2c := ec2.New(sess)
+	var allInterfaces []*ec2.NetworkInterface
+	err := withRetry(func() error {
+		allInterfaces = nil
+		return ec2c.DescribeNetworkInterfacesPages(&ec2.DescribeNetworkInterfacesInput{}, func(page *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
+			allInterfaces = append(allInterfaces, page.NetworkInterfaces...)
+			return true
+		})
+	})
+	return allInterfaces, err
+}
+
+// GetAllNatGateways returns a complete list of NAT gateways for a given session
+func GetAllNatGateways(sess *session.Session) ([]*ec2.NatGateway, error) {
+	ec2c := ec2.New(sess)
+	var allGateways []*ec2.NatGateway
+	err := withRetry(func() error {
+		allGateways = nil
+		return ec2c.DescribeNatGatewaysPages(&ec2.DescribeNatGatewaysInput{}, func(page *ec2.DescribeNatGatewaysOutput, lastPage bool) bool {
+			allGateways = append(allGateways, page.NatGateways...)
+			return true
+		})
+	})
+	return allGateways, err
+}
+
+// GetIdleAddresses returns the Elastic IPs which are not associated with any instance or network interface
+func GetIdleAddresses(addresses []*ec2.Address) []*ec2.Address {
+	var idle []*ec2.Address
+	for _, a := range addresses {
+		if a.AssociationId == nil && a.InstanceId == nil && a.NetworkInterfaceId == nil {
+			idle = append(idle, a)
+		}
+	}
+	return idle
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// TestGetIdleAddresses checks that only unassociated Elastic IPs are reported as idle
+func TestGetIdleAddresses(t *testing.T) {
+	addresses := []*ec2.Address{
+		{AllocationId: aws.String("eipalloc-idle"), PublicIp: aws.String("198.51.100.1")},
+		{AllocationId: aws.String("eipalloc-instance"), AssociationId: aws.String("eipassoc-1"), InstanceId: aws.String("i-1")},
+		{AllocationId: aws.String("eipalloc-eni"), AssociationId: aws.String("eipassoc-2"), NetworkInterfaceId: aws.String("eni-1")},
+	}
+	idle := GetIdleAddresses(addresses)
+	if len(idle) != 1 || *idle[0].AllocationId != "eipalloc-idle" {
+		t.Errorf("Expected only eipalloc-idle to be idle, got %v", idle)
+	}
+}
+
+// TestAddressIndex checks that addresses resolve to the most specific resource owning them
+func TestAddressIndex(t *testing.T) {
+	index := NewResourceIndex()
+	index.AddNetworkInterfaces("us-east-1", []*ec2.NetworkInterface{
+		{
+			NetworkInterfaceId: aws.String("eni-instance"),
+			Attachment:         &ec2.NetworkInterfaceAttachment{InstanceId: aws.String("i-1")},
+			PrivateIpAddresses: []*ec2.NetworkInterfacePrivateIpAddress{
+				{PrivateIpAddress: aws.String("10.0.0.10")},
+				{PrivateIpAddress: aws.String("10.0.0.11"), Association: &ec2.NetworkInterfaceAssociation{PublicIp: aws.String("198.51.100.11")}},
+			},
+		},
+		{
+			NetworkInterfaceId: aws.String("eni-lambda"),
+			PrivateIpAddresses: []*ec2.NetworkInterfacePrivateIpAddress{{PrivateIpAddress: aws.String("10.0.0.20")}},
+			Ipv6Addresses:      []*ec2.NetworkInterfaceIpv6Address{{Ipv6Address: aws.String("2001:DB8::20")}},
+		},
+		{
+			NetworkInterfaceId: aws.String("eni-nat"),
+			PrivateIpAddresses: []*ec2.NetworkInterfacePrivateIpAddress{{PrivateIpAddress: aws.String("10.0.0.30")}},
+		},
+	})
+	index.AddAddresses("us-east-1", []*ec2.Address{
+		{AllocationId: aws.String("eipalloc-idle"), PublicIp: aws.String("198.51.100.1")},
+		{AllocationId: aws.String("eipalloc-nat"), PublicIp: aws.String("198.51.100.30"), NetworkInterfaceId: aws.String("eni-nat")},
+	})
+	index.AddNatGateways("us-east-1", []*ec2.NatGateway{{
+		NatGatewayId: aws.String("nat-1"),
+		NatGatewayAddresses: []*ec2.NatGatewayAddress{
+			{PublicIp: aws.String("198.51.100.30"), PrivateIp: aws.String("10.0.0.30")},
+		},
+	}})
+	index.AddInstances("us-east-1", []*ec2.Instance{{InstanceId: aws.String("i-1"), PrivateDnsName: aws.String("ip-10-0-0-10.ec2.internal")}})
+
+	for _, testCase := range []struct {
+		address string
+		refType string
+		id      string
+	}{
+		{"10.0.0.10", "ec2:instance", "i-1"},
+		{"198.51.100.11", "ec2:instance", "i-1"},
+		{"10.0.0.20", "ec2:network-interface", "eni-lambda"},
+		{"2001:db8::20", "ec2:network-interface", "eni-lambda"},
+		{"198.51.100.1", "ec2:elastic-ip", "eipalloc-idle"},
+		{"198.51.100.30", "ec2:natgateway", "nat-1"},
+		{"10.0.0.30", "ec2:natgateway", "nat-1"},
+	} {
+		refs := index.Lookup(testCase.address)
+		if len(refs) != 1 {
+			t.Errorf("%s\tWant 1 owner\tHave:%d", testCase.address, len(refs))
+			continue
+		}
+		if ref := refs[0]; ref.Type != testCase.refType || ref.ID != testCase.id {
+			t.Errorf("%s\tWant:%s %s\tHave:%s %s", testCase.address, testCase.refType, testCase.id, ref.Type, ref.ID)
+		}
+	}
+
+	ips := index.IPs()
+	if ips.Lookup("ip-10-0-0-10.ec2.internal") != nil {
+		t.Errorf("DNS names should not be part of the IP index")
+	}
+	if len(ips) != 8 {
+		t.Errorf("Expected 8 indexed IPs, got %d", len(ips))
+	}
+}
+
+// TestAddressIndexSharedIPs checks that a private IP reused across regions and VPCs keeps every owner,
+// while a public IP keeps the last one
+func TestAddressIndexSharedIPs(t *testing.T) {
+	index := NewResourceIndex()
+	index.AddInstances("us-east-1", []*ec2.Instance{
+		{InstanceId: aws.String("i-east"), VpcId: aws.String("vpc-east"), PrivateIpAddress: aws.String("10.0.0.10")},
+		{InstanceId: aws.String("i-east-2"), VpcId: aws.String("vpc-east-2"), PrivateIpAddress: aws.String("10.0.0.10"), PublicIpAddress: aws.String("198.51.100.10")},
+	})
+	index.AddInstances("eu-west-1", []*ec2.Instance{
+		{InstanceId: aws.String("i-west"), VpcId: aws.String("vpc-west"), PrivateIpAddress: aws.String("10.0.0.10")},
+		{InstanceId: aws.String("i-west-2"), VpcId: aws.String("vpc-west"), PublicIpAddress: aws.String("198.51.100.10")},
+	})
+
+	owners := make(map[string]*ResourceRef)
+	for _, ref := range index.Lookup("10.0.0.10") {
+		owners[ref.ID] = ref
+	}
+	if len(owners) != 3 {
+		t.Fatalf("Expected 3 owners of 10.0.0.10, got %d", len(owners))
+	}
+	for id, want := range map[string][2]string{"i-east": {"us-east-1", "vpc-east"}, "i-east-2": {"us-east-1", "vpc-east-2"}, "i-west": {"eu-west-1", "vpc-west"}} {
+		if ref := owners[id]; ref == nil || ref.Region != want[0] || ref.VPC != want[1] {
+			t.Errorf("%s\tWant:%s %s\tHave:%+v", id, want[0], want[1], ref)
+		}
+	}
+	if refs := index.IPs().Lookup("198.51.100.10"); len(refs) != 1 || refs[0].ID != "i-west-2" {
+		t.Errorf("Public IP should only be owned by i-west-2, got %v", refs)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// LaunchTemplate holds an EC2 launch template along with all of its versions
+type LaunchTemplate struct {
+	Template *ec2.LaunchTemplate
+	Versions []*ec2.LaunchTemplateVersion
+}
+
+// GetAllAutoScalingGroups returns a complete list of Auto Scaling groups for a given session
+func GetAllAutoScalingGroups(sess *session.Session) ([]*autoscaling.Group, error) {
+	asc := autoscaling.New(sess)
+	var allGroups []*autoscaling.Group
+	err := withRetry(func() error {
+		allGroups = nil
+		return asc.DescribeAutoScalingGroupsPages(&autoscaling.DescribeAutoScalingGroupsInput{}, func(page *autoscaling.DescribeAutoScalingGroupsOutput, lastPage bool) bool {
+			allGroups = append(allGroups, page.AutoScalingGroups...)
+			return true
+		})
+	})
+	return allGroups, err
+}
+
+// GetAllLaunchConfigurations returns a complete list of launch configurations for a given session
+func GetAllLaunchConfigurations(sess *session.Session) ([]*autoscaling.LaunchConfiguration, error) {
+	asc := autoscaling.New(sess)
+	var allConfigurations []*autoscaling.LaunchConfiguration
+	err := withRetry(func() error {
+		allConfigurations = nil
+		return asc.DescribeLaunchConfigurationsPages(&autoscaling.DescribeLaunchConfigurationsInput{}, func(page *autoscaling.DescribeLaunchConfigurationsOutput, lastPage bool) bool {
+			allConfigurations = append(allConfigurations, page.LaunchConfigurations...)
+			return true
+		})
+	})
+	return allConfigurations, err
+}
+
+// GetAllLaunchTemplates returns a complete list of launch templates and their versions for a given session
+func GetAllLaunchTemplates(sess *session.Session) ([]*LaunchTemplate, error) {
+	ec2c := ec2.New(sess)
+	var templates []*ec2.LaunchTemplate
+	err := withRetry(func() error {
+		templates = nil
+		return ec2c.DescribeLaunchTemplatesPages(&ec2.DescribeLaunchTemplatesInput{}, func(page *ec2.DescribeLaunchTemplatesOutput, lastPage bool) bool {
+			templates = append(templates, page.LaunchTemplates...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	var allTemplates []*LaunchTemplate
+	for _, t := range templates {
+		template := &LaunchTemplate{Template: t}
+		input := ec2.DescribeLaunchTemplateVersionsInput{LaunchTemplateId: t.LaunchTemplateId}
+		err := withRetry(func() error {
+			template.Versions = nil
+			return ec2c.DescribeLaunchTemplateVersionsPages(&input, func(page *ec2.DescribeLaunchTemplateVersionsOutput, lastPage bool) bool {
+				template.Versions = append(template.Versions, page.LaunchTemplateVersions...)
+				return true
+			})
+		})
+		if err != nil {
+			return allTemplates, err
+		}
+		allTemplates = append(allTemplates, template)
+	}
+	return allTemplates, nil
+}
+
+// GetInstanceGroups maps the ID of every instance belonging to one of the given groups to the group name
+func GetInstanceGroups(groups []*autoscaling.Group) map[string]string {
+	instanceGroups := make(map[string]string)
+	for _, g := range groups {
+		for _, i := range g.Instances {
+			instanceGroups[aws.StringValue(i.InstanceId)] = aws.StringValue(g.AutoScalingGroupName)
+		}
+	}
+	return instanceGroups
+}
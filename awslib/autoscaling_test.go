@@ -0,0 +1,64 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+)
+
+// TestGetAllAutoScalingGroups checks if the lib is able to gather all Auto Scaling groups and launch templates.
+// This test REQUIRES a working AWS account and credentials to read from Auto Scaling and EC2
+// This test does NOT fail unless there is an error in the gathering, the gathering itself is not validated.
+func TestGetAllAutoScalingGroups(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	sessions, err := BuildSessions(GetAllRegions())
+	if err != nil {
+		t.Errorf("Unable to get sessions: %v", err)
+	}
+	for r, sess := range sessions {
+		groups, err := GetAllAutoScalingGroups(sess)
+		if err != nil {
+			t.Errorf("Failed to get Auto Scaling Groups for region: %s because %v", r, err)
+		}
+		templates, err := GetAllLaunchTemplates(sess)
+		if err != nil {
+			t.Errorf("Failed to get Launch Templates for region: %s because %v", r, err)
+		}
+		t.Logf("Found %d groups and %d launch templates in %s", len(groups), len(templates), r)
+	}
+}
+
+// TestGetInstanceGroups checks that every group member is mapped to its group
+func TestGetInstanceGroups(t *testing.T) {
+	groups := []*autoscaling.Group{{
+		AutoScalingGroupName: aws.String("web"),
+		Instances: []*autoscaling.Instance{
+			{InstanceId: aws.String("i-1")},
+			{InstanceId: aws.String("i-2")},
+		},
+	}, {
+		AutoScalingGroupName: aws.String("worker"),
+		Instances:            []*autoscaling.Instance{{InstanceId: aws.String("i-3")}},
+	}}
+	instanceGroups := GetInstanceGroups(groups)
+	for instance, group := range map[string]string{"i-1": "web", "i-2": "web", "i-3": "worker"} {
+		if instanceGroups[instance] != group {
+			t.Errorf("%s\tWant:%s\tHave:%s", instance, group, instanceGroups[instance])
+		}
+	}
+}
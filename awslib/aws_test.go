@@ -59,3 +59,92 @@ func TestGetAllDBInstances(t *testing.T) {
 		t.Logf("Found %d instances in %s", len(dbinstances), r)
 	}
 }
+
+// TestGetAllContainerClusters checks if the lib is able to gather all EKS and ECS clusters.
+// This test REQUIRES a working AWS account and credentials to read from EKS and ECS
+// This test does NOT fail unless there is an error in the gathering, the gathering itself is not validated.
+func TestGetAllContainerClusters(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	sessions, err := BuildSessions(GetAllRegions())
+	if err != nil {
+		t.Errorf("Unable to get sessions: %v", err)
+	}
+	for r, sess := range sessions {
+		eksClusters, err := GetAllEKSClusters(sess)
+		if err != nil {
+			t.Errorf("Failed to get EKS Clusters for region: %s because %v", r, err)
+		}
+		ecsClusters, err := GetAllECSClusters(sess)
+		if err != nil {
+			t.Errorf("Failed to get ECS Clusters for region: %s because %v", r, err)
+		}
+		t.Logf("Found %d EKS and %d ECS clusters in %s", len(eksClusters), len(ecsClusters), r)
+	}
+}
+
+// TestGetAllDataStores checks if the lib is able to gather all DynamoDB tables, ElastiCache and Redshift clusters.
+// This test REQUIRES a working AWS account and credentials to read from these services
+// This test does NOT fail unless there is an error in the gathering, the gathering itself is not validated.
+func TestGetAllDataStores(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	sessions, err := BuildSessions(GetAllRegions())
+	if err != nil {
+		t.Errorf("Unable to get sessions: %v", err)
+	}
+	for r, sess := range sessions {
+		tables, err := GetAllDynamoDBTables(sess)
+		if err != nil {
+			t.Errorf("Failed to get DynamoDB Tables for region: %s because %v", r, err)
+		}
+		cacheClusters, err := GetAllCacheClusters(sess)
+		if err != nil {
+			t.Errorf("Failed to get ElastiCache Clusters for region: %s because %v", r, err)
+		}
+		redshiftClusters, err := GetAllRedshiftClusters(sess)
+		if err != nil {
+			t.Errorf("Failed to get Redshift Clusters for region: %s because %v", r, err)
+		}
+		t.Logf("Found %d tables, %d cache clusters and %d redshift clusters in %s", len(tables), len(cacheClusters), len(redshiftClusters), r)
+	}
+}
+
+// TestGetAllMessaging checks if the lib is able to gather all SQS queues, SNS topics, Kinesis streams and event buses.
+// This test REQUIRES a working AWS account and credentials to read from these services
+// This test does NOT fail unless there is an error in the gathering, the gathering itself is not validated.
+func TestGetAllMessaging(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	sessions, err := BuildSessions(GetAllRegions())
+	if err != nil {
+		t.Errorf("Unable to get sessions: %v", err)
+	}
+	for r, sess := range sessions {
+		queues, err := GetAllQueues(sess)
+		if err != nil {
+			t.Errorf("Failed to get SQS Queues for region: %s because %v", r, err)
+		}
+		topics, err := GetAllTopics(sess)
+		if err != nil {
+			t.Errorf("Failed to get SNS Topics for region: %s because %v", r, err)
+		}
+		streams, err := GetAllKinesisStreams(sess)
+		if err != nil {
+			t.Errorf("Failed to get Kinesis Streams for region: %s because %v", r, err)
+		}
+		deliveryStreams, err := GetAllDeliveryStreams(sess)
+		if err != nil {
+			t.Errorf("Failed to get Firehose Delivery Streams for region: %s because %v", r, err)
+		}
+		buses, err := GetAllEventBuses(sess)
+		if err != nil {
+			t.Errorf("Failed to get Event Buses for region: %s because %v", r, err)
+		}
+		t.Logf("Found %d queues, %d topics, %d streams, %d delivery streams and %d event buses in %s",
+			len(queues), len(topics), len(streams), len(deliveryStreams), len(buses), r)
+	}
+}
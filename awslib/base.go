@@ -14,11 +14,14 @@ package awslib
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/jpillora/backoff"
 )
 
 // GetAllRegions returns all regions for AWS except US-Gov and China
@@ -68,3 +71,53 @@ func BuildSessionsWithCredentials(regions []string, creds *credentials.Credentia
 	}
 	return sessions, errMain
 }
+
+// newBackoff returns the backoff used between retries of throttled API calls
+func newBackoff() *backoff.Backoff {
+	return &backoff.Backoff{
+		//These are the defaults
+		Min:    10 * time.Millisecond,
+		Max:    30 * time.Second,
+		Factor: 2,
+		Jitter: false,
+	}
+}
+
+// isThrottled reports whether err is an AWS error asking the caller to slow down
+func isThrottled(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+	switch aerr.Code() {
+	case "RateExceeded", "Throttling", "ThrottlingException", "RequestLimitExceeded", "TooManyRequestsException":
+		return true
+	}
+	return false
+}
+
+// withRetry calls fn until it succeeds or fails with an error other than throttling
+func withRetry(fn func() error) error {
+	b := newBackoff()
+	for {
+		err := fn()
+		if err != nil && isThrottled(err) {
+			time.Sleep(b.Duration())
+			continue
+		}
+		return err
+	}
+}
+
+// chunkStrings splits list into batches of at most size elements, for APIs limiting how many
+// identifiers can be described in a single call
+func chunkStrings(list []*string, size int) [][]*string {
+	var chunks [][]*string
+	for size < len(list) {
+		list, chunks = list[size:], append(chunks, list[:size])
+	}
+	if len(list) > 0 {
+		chunks = append(chunks, list)
+	}
+	return chunks
+}
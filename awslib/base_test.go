@@ -58,3 +58,37 @@ func TestBuildSessions(t *testing.T) {
 
 	}
 }
+
+// TestChunkStrings checks that batches never exceed the requested size and keep every element
+func TestChunkStrings(t *testing.T) {
+	for _, testCase := range []struct {
+		length int
+		size   int
+		chunks int
+	}{
+		{length: 0, size: 10, chunks: 0},
+		{length: 10, size: 10, chunks: 1},
+		{length: 11, size: 10, chunks: 2},
+		{length: 250, size: 100, chunks: 3},
+	} {
+		var list []*string
+		for i := 0; i < testCase.length; i++ {
+			s := string(rune('a' + i%26))
+			list = append(list, &s)
+		}
+		chunks := chunkStrings(list, testCase.size)
+		if len(chunks) != testCase.chunks {
+			t.Errorf("%d/%d\tWant:%d chunks\tHave:%d", testCase.length, testCase.size, testCase.chunks, len(chunks))
+		}
+		total := 0
+		for _, c := range chunks {
+			if len(c) > testCase.size {
+				t.Errorf("Chunk of %d elements exceeds size %d", len(c), testCase.size)
+			}
+			total += len(c)
+		}
+		if total != testCase.length {
+			t.Errorf("Lost elements while chunking: want %d have %d", testCase.length, total)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// Stack holds a CloudFormation stack along with the resources it manages.
+// The stack carries its status, parameters, outputs, tags and last drift detection result
+type Stack struct {
+	Stack     *cloudformation.Stack
+	Resources []*cloudformation.StackResourceSummary
+}
+
+// StackOwner identifies the stack, and the logical resource within it, managing a physical resource
+type StackOwner struct {
+	StackName    string
+	StackID      string
+	LogicalID    string
+	ResourceType string
+}
+
+// GetAllStacks returns a complete list of CloudFormation stacks and their resources for a given session.
+// Deleted stacks are not returned
+func GetAllStacks(sess *session.Session) ([]*Stack, error) {
+	cfc := cloudformation.New(sess)
+	var stacks []*cloudformation.Stack
+	err := withRetry(func() error {
+		stacks = nil
+		return cfc.DescribeStacksPages(&cloudformation.DescribeStacksInput{}, func(page *cloudformation.DescribeStacksOutput, lastPage bool) bool {
+			stacks = append(stacks, page.Stacks...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	var allStacks []*Stack
+	for _, s := range stacks {
+		stack := &Stack{Stack: s}
+		err := withRetry(func() error {
+			stack.Resources = nil
+			return cfc.ListStackResourcesPages(&cloudformation.ListStackResourcesInput{StackName: s.StackId}, func(page *cloudformation.ListStackResourcesOutput, lastPage bool) bool {
+				stack.Resources = append(stack.Resources, page.StackResourceSummaries...)
+				return true
+			})
+		})
+		if err != nil {
+			return allStacks, err
+		}
+		allStacks = append(allStacks, stack)
+	}
+	return allStacks, nil
+}
+
+// GetStackOwnership maps the type, then the physical ID of every resource managed by one of the given stacks
+// (instance IDs, DB identifiers, bucket names, ARNs...) to the stack owning it. Physical IDs such as names
+// are only unique within a type, e.g. an ECS cluster, an EKS cluster and a DynamoDB table may all be named "app"
+func GetStackOwnership(stacks []*Stack) map[string]map[string]*StackOwner {
+	owners := make(map[string]map[string]*StackOwner)
+	for _, s := range stacks {
+		for _, r := range s.Resources {
+			id := aws.StringValue(r.PhysicalResourceId)
+			// Resources which failed to create have no physical ID
+			if id == "" {
+				continue
+			}
+			resourceType := aws.StringValue(r.ResourceType)
+			if owners[resourceType] == nil {
+				owners[resourceType] = make(map[string]*StackOwner)
+			}
+			owners[resourceType][id] = &StackOwner{
+				StackName:    aws.StringValue(s.Stack.StackName),
+				StackID:      aws.StringValue(s.Stack.StackId),
+				LogicalID:    aws.StringValue(r.LogicalResourceId),
+				ResourceType: resourceType,
+			}
+		}
+	}
+	return owners
+}
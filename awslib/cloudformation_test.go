@@ -0,0 +1,74 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// TestGetStackOwnership checks that physical resources are mapped to the stack managing them
+func TestGetStackOwnership(t *testing.T) {
+	stacks := []*Stack{{
+		Stack: &cloudformation.Stack{StackName: aws.String("web"), StackId: aws.String("arn:aws:cloudformation:us-east-1:123456789012:stack/web/1")},
+		Resources: []*cloudformation.StackResourceSummary{
+			{LogicalResourceId: aws.String("Instance"), PhysicalResourceId: aws.String("i-1"), ResourceType: aws.String("AWS::EC2::Instance")},
+			{LogicalResourceId: aws.String("Failed"), ResourceType: aws.String("AWS::EC2::Volume")},
+		},
+	}, {
+		Stack: &cloudformation.Stack{StackName: aws.String("db"), StackId: aws.String("arn:aws:cloudformation:us-east-1:123456789012:stack/db/2")},
+		Resources: []*cloudformation.StackResourceSummary{
+			{LogicalResourceId: aws.String("Database"), PhysicalResourceId: aws.String("db-1"), ResourceType: aws.String("AWS::RDS::DBInstance")},
+		},
+	}}
+	owners := GetStackOwnership(stacks)
+	if len(owners) != 2 {
+		t.Fatalf("Expected 2 owned resource types, got %d", len(owners))
+	}
+	if o := owners["AWS::EC2::Instance"]["i-1"]; o.StackName != "web" || o.LogicalID != "Instance" {
+		t.Errorf("i-1 should be owned by web/Instance: %+v", o)
+	}
+	if o := owners["AWS::RDS::DBInstance"]["db-1"]; o.StackName != "db" || o.ResourceType != "AWS::RDS::DBInstance" {
+		t.Errorf("db-1 should be owned by db: %+v", o)
+	}
+}
+
+// TestGetStackOwnershipSharedIDs checks that resources of different types sharing a physical ID keep their own stack
+func TestGetStackOwnershipSharedIDs(t *testing.T) {
+	stacks := []*Stack{{
+		Stack: &cloudformation.Stack{StackName: aws.String("cluster"), StackId: aws.String("arn:aws:cloudformation:us-east-1:123456789012:stack/cluster/1")},
+		Resources: []*cloudformation.StackResourceSummary{
+			{LogicalResourceId: aws.String("ECS"), PhysicalResourceId: aws.String("app"), ResourceType: aws.String("AWS::ECS::Cluster")},
+			{LogicalResourceId: aws.String("EKS"), PhysicalResourceId: aws.String("app"), ResourceType: aws.String("AWS::EKS::Cluster")},
+		},
+	}, {
+		Stack: &cloudformation.Stack{StackName: aws.String("data"), StackId: aws.String("arn:aws:cloudformation:us-east-1:123456789012:stack/data/2")},
+		Resources: []*cloudformation.StackResourceSummary{
+			{LogicalResourceId: aws.String("Table"), PhysicalResourceId: aws.String("app"), ResourceType: aws.String("AWS::DynamoDB::Table")},
+		},
+	}}
+	owners := GetStackOwnership(stacks)
+	want := map[string]string{
+		"AWS::ECS::Cluster":    "cluster/ECS",
+		"AWS::EKS::Cluster":    "cluster/EKS",
+		"AWS::DynamoDB::Table": "data/Table",
+	}
+	for resourceType, owner := range want {
+		o := owners[resourceType]["app"]
+		if o == nil || o.StackName+"/"+o.LogicalID != owner {
+			t.Errorf("%s app should be owned by %s: %+v", resourceType, owner, o)
+		}
+	}
+}
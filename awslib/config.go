@@ -0,0 +1,139 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/configservice"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// configQuery is the AWS Config advanced query selecting the current configuration of every resource of a type
+const configQuery = "SELECT accountId, awsRegion, configuration WHERE resourceType = '%s' AND configurationItemStatus <> 'ResourceDeleted'"
+
+// configResult is a single result of configQuery
+type configResult struct {
+	AccountID     string          `json:"accountId"`
+	AWSRegion     string          `json:"awsRegion"`
+	Configuration json.RawMessage `json:"configuration"`
+}
+
+// SelectAggregateResources runs an AWS Config advanced query against the given aggregator and returns its JSON results
+func SelectAggregateResources(sess *session.Session, aggregator, expression string) ([]string, error) {
+	cc := configservice.New(sess)
+	var allResults []string
+	input := configservice.SelectAggregateResourceConfigInput{
+		ConfigurationAggregatorName: aws.String(aggregator),
+		Expression:                  aws.String(expression),
+		Limit:                       aws.Int64(100),
+	}
+	for {
+		var result *configservice.SelectAggregateResourceConfigOutput
+		err := withRetry(func() (err error) {
+			result, err = cc.SelectAggregateResourceConfig(&input)
+			return err
+		})
+		if err != nil {
+			return allResults, err
+		}
+		allResults = append(allResults, aws.StringValueSlice(result.Results)...)
+		if result.NextToken == nil {
+			break
+		}
+		input.SetNextToken(*result.NextToken)
+	}
+	return allResults, nil
+}
+
+// GetConfigInstances returns the EC2 instances recorded by the given AWS Config aggregator, keyed by account, then region
+func GetConfigInstances(sess *session.Session, aggregator string) (map[string]map[string][]*ec2.Instance, error) {
+	results, err := SelectAggregateResources(sess, aggregator, fmt.Sprintf(configQuery, "AWS::EC2::Instance"))
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfigInstances(results)
+}
+
+// GetConfigDBInstances returns the RDS DB instances recorded by the given AWS Config aggregator, keyed by account, then region
+func GetConfigDBInstances(sess *session.Session, aggregator string) (map[string]map[string][]*rds.DBInstance, error) {
+	results, err := SelectAggregateResources(sess, aggregator, fmt.Sprintf(configQuery, "AWS::RDS::DBInstance"))
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfigDBInstances(results)
+}
+
+// ParseConfigInstances maps AWS Config EC2 instance configurations to ec2.Instance, keyed by account, then region
+func ParseConfigInstances(results []string) (map[string]map[string][]*ec2.Instance, error) {
+	instances := make(map[string]map[string][]*ec2.Instance)
+	for _, r := range results {
+		var instance ec2.Instance
+		account, region, err := decodeConfigResult(r, &instance)
+		if err != nil {
+			return nil, err
+		}
+		if region == "" {
+			continue
+		}
+		if instances[account] == nil {
+			instances[account] = make(map[string][]*ec2.Instance)
+		}
+		instances[account][region] = append(instances[account][region], &instance)
+	}
+	return instances, nil
+}
+
+// ParseConfigDBInstances maps AWS Config RDS DB instance configurations to rds.DBInstance, keyed by account, then region
+func ParseConfigDBInstances(results []string) (map[string]map[string][]*rds.DBInstance, error) {
+	instances := make(map[string]map[string][]*rds.DBInstance)
+	for _, r := range results {
+		var instance rds.DBInstance
+		account, region, err := decodeConfigResult(r, &instance)
+		if err != nil {
+			return nil, err
+		}
+		if region == "" {
+			continue
+		}
+		if instances[account] == nil {
+			instances[account] = make(map[string][]*rds.DBInstance)
+		}
+		instances[account][region] = append(instances[account][region], &instance)
+	}
+	return instances, nil
+}
+
+// decodeConfigResult decodes the configuration of a configQuery result into v and returns the resource account and region.
+// Config records configurations with camel cased keys, which encoding/json matches case insensitively to the SDK fields.
+// Returns an empty region for results without a configuration
+func decodeConfigResult(result string, v interface{}) (string, string, error) {
+	var r configResult
+	if err := json.Unmarshal([]byte(result), &r); err != nil {
+		return "", "", fmt.Errorf("Invalid AWS Config result: %v", err)
+	}
+	if len(r.Configuration) == 0 || string(r.Configuration) == "null" {
+		return "", "", nil
+	}
+	if err := json.Unmarshal(r.Configuration, v); err != nil {
+		// Fields whose type differs between Config and the SDK are skipped, the rest is still decoded
+		if _, ok := err.(*json.UnmarshalTypeError); !ok {
+			return "", "", fmt.Errorf("Invalid AWS Config configuration: %v", err)
+		}
+	}
+	return r.AccountID, r.AWSRegion, nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// TestParseConfigInstances checks that AWS Config EC2 configurations are mapped onto ec2.Instance
+func TestParseConfigInstances(t *testing.T) {
+	results := []string{
+		`{"accountId":"123456789012","awsRegion":"us-east-1","configuration":{"instanceId":"i-1","instanceType":"t3.micro",` +
+			`"privateIpAddress":"10.0.0.10","launchTime":"2019-01-02T03:04:05.000Z","state":{"code":16,"name":"running"},` +
+			`"tags":[{"key":"Name","value":"web"}],"cpuOptions":"unexpected"}}`,
+		`{"accountId":"123456789012","awsRegion":"eu-west-1","configuration":{"instanceId":"i-2"}}`,
+		`{"accountId":"123456789012","awsRegion":"eu-west-1"}`,
+		`{"accountId":"210987654321","awsRegion":"us-east-1","configuration":{"instanceId":"i-3"}}`,
+	}
+	instances, err := ParseConfigInstances(results)
+	if err != nil {
+		t.Fatalf("Failed to parse Config results: %v", err)
+	}
+	account := instances["123456789012"]
+	if len(instances) != 2 || len(account["us-east-1"]) != 1 || len(account["eu-west-1"]) != 1 {
+		t.Fatalf("Unexpected instances per account and region: %v", instances)
+	}
+	// Accounts sharing a region are kept apart
+	if other := instances["210987654321"]["us-east-1"]; len(other) != 1 || aws.StringValue(other[0].InstanceId) != "i-3" {
+		t.Errorf("Unexpected instances of the second account: %v", other)
+	}
+	i := account["us-east-1"][0]
+	if aws.StringValue(i.InstanceId) != "i-1" || aws.StringValue(i.PrivateIpAddress) != "10.0.0.10" {
+		t.Errorf("Unexpected instance identity %s %s", aws.StringValue(i.InstanceId), aws.StringValue(i.PrivateIpAddress))
+	}
+	if i.State == nil || aws.StringValue(i.State.Name) != "running" {
+		t.Errorf("Instance state was not decoded")
+	}
+	if i.LaunchTime == nil || i.LaunchTime.Year() != 2019 {
+		t.Errorf("Instance launch time was not decoded")
+	}
+	if len(i.Tags) != 1 || aws.StringValue(i.Tags[0].Key) != "Name" || aws.StringValue(i.Tags[0].Value) != "web" {
+		t.Errorf("Instance tags were not decoded")
+	}
+
+	if _, err := ParseConfigInstances([]string{"not json"}); err == nil {
+		t.Errorf("Expected an error for an invalid result")
+	}
+}
+
+// TestParseConfigDBInstances checks that AWS Config RDS configurations are mapped onto rds.DBInstance
+func TestParseConfigDBInstances(t *testing.T) {
+	results := []string{
+		`{"accountId":"123456789012","awsRegion":"us-east-1","configuration":{"dBInstanceIdentifier":"db-1",` +
+			`"engine":"postgres","endpoint":{"address":"db-1.abc.us-east-1.rds.amazonaws.com","port":5432}}}`,
+	}
+	instances, err := ParseConfigDBInstances(results)
+	if err != nil {
+		t.Fatalf("Failed to parse Config results: %v", err)
+	}
+	if len(instances["123456789012"]["us-east-1"]) != 1 {
+		t.Fatalf("Unexpected instances per account and region: %v", instances)
+	}
+	db := instances["123456789012"]["us-east-1"][0]
+	if aws.StringValue(db.DBInstanceIdentifier) != "db-1" || db.Endpoint == nil || aws.Int64Value(db.Endpoint.Port) != 5432 {
+		t.Errorf("Unexpected DB instance %v", db)
+	}
+}
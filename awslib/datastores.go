@@ -0,0 +1,114 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/redshift"
+)
+
+// DynamoDBTable holds a DynamoDB table description along with its backup configuration.
+// The description carries the billing mode, item count, size, GSIs and encryption settings
+type DynamoDBTable struct {
+	Table             *dynamodb.TableDescription
+	ContinuousBackups *dynamodb.ContinuousBackupsDescription
+}
+
+// GetAllDynamoDBTables returns a complete list of DynamoDB tables for a given session
+func GetAllDynamoDBTables(sess *session.Session) ([]*DynamoDBTable, error) {
+	ddbc := dynamodb.New(sess)
+	var names []*string
+	err := withRetry(func() error {
+		names = nil
+		return ddbc.ListTablesPages(&dynamodb.ListTablesInput{}, func(page *dynamodb.ListTablesOutput, lastPage bool) bool {
+			names = append(names, page.TableNames...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	var allTables []*DynamoDBTable
+	for _, name := range names {
+		var table DynamoDBTable
+		err := withRetry(func() error {
+			result, err := ddbc.DescribeTable(&dynamodb.DescribeTableInput{TableName: name})
+			if err == nil {
+				table.Table = result.Table
+			}
+			return err
+		})
+		if err != nil {
+			return allTables, err
+		}
+		// Point in time recovery status is only exposed through the backups API
+		err = withRetry(func() error {
+			result, err := ddbc.DescribeContinuousBackups(&dynamodb.DescribeContinuousBackupsInput{TableName: name})
+			if err == nil {
+				table.ContinuousBackups = result.ContinuousBackupsDescription
+			}
+			return err
+		})
+		if err != nil {
+			return allTables, err
+		}
+		allTables = append(allTables, &table)
+	}
+	return allTables, nil
+}
+
+// GetAllReplicationGroups returns a complete list of ElastiCache replication groups for a given session
+func GetAllReplicationGroups(sess *session.Session) ([]*elasticache.ReplicationGroup, error) {
+	ecc := elasticache.New(sess)
+	var allGroups []*elasticache.ReplicationGroup
+	err := withRetry(func() error {
+		allGroups = nil
+		return ecc.DescribeReplicationGroupsPages(&elasticache.DescribeReplicationGroupsInput{}, func(page *elasticache.DescribeReplicationGroupsOutput, lastPage bool) bool {
+			allGroups = append(allGroups, page.ReplicationGroups...)
+			return true
+		})
+	})
+	return allGroups, err
+}
+
+// GetAllCacheClusters returns a complete list of ElastiCache cache clusters, including their nodes, for a given session
+func GetAllCacheClusters(sess *session.Session) ([]*elasticache.CacheCluster, error) {
+	ecc := elasticache.New(sess)
+	var allClusters []*elasticache.CacheCluster
+	input := elasticache.DescribeCacheClustersInput{ShowCacheNodeInfo: aws.Bool(true)}
+	err := withRetry(func() error {
+		allClusters = nil
+		return ecc.DescribeCacheClustersPages(&input, func(page *elasticache.DescribeCacheClustersOutput, lastPage bool) bool {
+			allClusters = append(allClusters, page.CacheClusters...)
+			return true
+		})
+	})
+	return allClusters, err
+}
+
+// GetAllRedshiftClusters returns a complete list of Redshift clusters for a given session
+func GetAllRedshiftClusters(sess *session.Session) ([]*redshift.Cluster, error) {
+	rsc := redshift.New(sess)
+	var allClusters []*redshift.Cluster
+	err := withRetry(func() error {
+		allClusters = nil
+		return rsc.DescribeClustersPages(&redshift.DescribeClustersInput{}, func(page *redshift.DescribeClustersOutput, lastPage bool) bool {
+			allClusters = append(allClusters, page.Clusters...)
+			return true
+		})
+	})
+	return allClusters, err
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// InstanceStorage links an EC2 instance to the EBS volumes and AMI it was built from
+type InstanceStorage struct {
+	InstanceID string
+	Image      *ec2.Image
+	Volumes    []*ec2.Volume
+}
+
+// GetAllVolumes returns a complete list of EBS volumes for a given session
+func GetAllVolumes(sess *session.Session) ([]*ec2.Volume, error) {
+	ec2c := ec2.New(sess)
+	allVolumesDone := false
+	var allVolumes []*ec2.Volume
+	input := ec2.DescribeVolumesInput{}
+	b := newBackoff()
+	for !allVolumesDone {
+		result, err := ec2c.DescribeVolumes(&input)
+		if err != nil {
+			// Retry with backoff if throttled
+			if isThrottled(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return allVolumes, err
+		}
+		b.Reset()
+		allVolumes = append(allVolumes, result.Volumes...)
+		if result.NextToken == nil {
+			allVolumesDone = true
+			continue
+		}
+		input.SetNextToken(*result.NextToken)
+	}
+	return allVolumes, nil
+}
+
+// GetAllSnapshots returns a complete list of EBS snapshots owned by the account for a given session
+func GetAllSnapshots(sess *session.Session) ([]*ec2.Snapshot, error) {
+	ec2c := ec2.New(sess)
+	allSnapshotsDone := false
+	var allSnapshots []*ec2.Snapshot
+	// Without an owner filter this would also list every public snapshot
+	input := ec2.DescribeSnapshotsInput{
+		OwnerIds: aws.StringSlice([]string{"self"}),
+	}
+	b := newBackoff()
+	for !allSnapshotsDone {
+		result, err := ec2c.DescribeSnapshots(&input)
+		if err != nil {
+			if isThrottled(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return allSnapshots, err
+		}
+		b.Reset()
+		allSnapshots = append(allSnapshots, result.Snapshots...)
+		if result.NextToken == nil {
+			allSnapshotsDone = true
+			continue
+		}
+		input.SetNextToken(*result.NextToken)
+	}
+	return allSnapshots, nil
+}
+
+// GetAllImages returns a complete list of AMIs owned by the account for a given session
+func GetAllImages(sess *session.Session) ([]*ec2.Image, error) {
+	ec2c := ec2.New(sess)
+	input := ec2.DescribeImagesInput{
+		Owners: aws.StringSlice([]string{"self"}),
+	}
+	b := newBackoff()
+	for {
+		// DescribeImages is not paginated, a single call returns every image
+		result, err := ec2c.DescribeImages(&input)
+		if err != nil {
+			if isThrottled(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return nil, err
+		}
+		return result.Images, nil
+	}
+}
+
+// GetUnattachedVolumes returns the volumes which are not attached to any instance
+func GetUnattachedVolumes(volumes []*ec2.Volume) []*ec2.Volume {
+	var unattached []*ec2.Volume
+	for _, v := range volumes {
+		if len(v.Attachments) == 0 {
+			unattached = append(unattached, v)
+		}
+	}
+	return unattached
+}
+
+// GetOrphanedSnapshots returns the snapshots whose source volume no longer exists
+// and which are not backing any of the given AMIs
+func GetOrphanedSnapshots(snapshots []*ec2.Snapshot, volumes []*ec2.Volume, images []*ec2.Image) []*ec2.Snapshot {
+	liveVolumes := make(map[string]bool)
+	for _, v := range volumes {
+		liveVolumes[aws.StringValue(v.VolumeId)] = true
+	}
+	imageSnapshots := make(map[string]bool)
+	for _, i := range images {
+		for _, bdm := range i.BlockDeviceMappings {
+			if bdm.Ebs != nil {
+				imageSnapshots[aws.StringValue(bdm.Ebs.SnapshotId)] = true
+			}
+		}
+	}
+	var orphaned []*ec2.Snapshot
+	for _, s := range snapshots {
+		if liveVolumes[aws.StringValue(s.VolumeId)] || imageSnapshots[aws.StringValue(s.SnapshotId)] {
+			continue
+		}
+		orphaned = append(orphaned, s)
+	}
+	return orphaned
+}
+
+// LinkInstanceStorage resolves the BlockDeviceMappings and ImageId of every instance
+// to the given volume and image records, keyed by instance ID.
+// Volumes or images that were not supplied are left out of the result
+func LinkInstanceStorage(instances []*ec2.Instance, volumes []*ec2.Volume, images []*ec2.Image) map[string]*InstanceStorage {
+	volumesByID := make(map[string]*ec2.Volume)
+	for _, v := range volumes {
+		volumesByID[aws.StringValue(v.VolumeId)] = v
+	}
+	imagesByID := make(map[string]*ec2.Image)
+	for _, i := range images {
+		imagesByID[aws.StringValue(i.ImageId)] = i
+	}
+	links := make(map[string]*InstanceStorage)
+	for _, i := range instances {
+		id := aws.StringValue(i.InstanceId)
+		link := &InstanceStorage{
+			InstanceID: id,
+			Image:      imagesByID[aws.StringValue(i.ImageId)],
+		}
+		for _, bdm := range i.BlockDeviceMappings {
+			if bdm.Ebs == nil {
+				continue
+			}
+			if v, ok := volumesByID[aws.StringValue(bdm.Ebs.VolumeId)]; ok {
+				link.Volumes = append(link.Volumes, v)
+			}
+		}
+		links[id] = link
+	}
+	return links
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// TestGetAllVolumes checks if the lib is able to gather all volumes, snapshots and images.
+// This test REQUIRES a working AWS account and credentials to read from EC2
+// This test does NOT fail unless there is an error in the gathering, the gathering itself is not validated.
+func TestGetAllVolumes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	sessions, err := BuildSessions(GetAllRegions())
+	if err != nil {
+		t.Errorf("Unable to get sessions: %v", err)
+	}
+	for r, sess := range sessions {
+		volumes, err := GetAllVolumes(sess)
+		if err != nil {
+			t.Errorf("Failed to get Volumes for region: %s because %v", r, err)
+		}
+		snapshots, err := GetAllSnapshots(sess)
+		if err != nil {
+			t.Errorf("Failed to get Snapshots for region: %s because %v", r, err)
+		}
+		images, err := GetAllImages(sess)
+		if err != nil {
+			t.Errorf("Failed to get Images for region: %s because %v", r, err)
+		}
+		t.Logf("Found %d volumes, %d snapshots and %d images in %s", len(volumes), len(snapshots), len(images), r)
+	}
+}
+
+func testVolume(id string, instanceIDs ...string) *ec2.Volume {
+	v := &ec2.Volume{VolumeId: aws.String(id)}
+	for _, i := range instanceIDs {
+		v.Attachments = append(v.Attachments, &ec2.VolumeAttachment{InstanceId: aws.String(i), VolumeId: aws.String(id)})
+	}
+	return v
+}
+
+// TestGetUnattachedVolumes checks that only volumes without attachments are returned
+func TestGetUnattachedVolumes(t *testing.T) {
+	volumes := []*ec2.Volume{testVolume("vol-1", "i-1"), testVolume("vol-2"), testVolume("vol-3", "i-2")}
+	unattached := GetUnattachedVolumes(volumes)
+	if len(unattached) != 1 || *unattached[0].VolumeId != "vol-2" {
+		t.Errorf("Expected only vol-2 to be unattached, got %v", unattached)
+	}
+}
+
+// TestGetOrphanedSnapshots checks that snapshots of live volumes or backing AMIs are not reported
+func TestGetOrphanedSnapshots(t *testing.T) {
+	volumes := []*ec2.Volume{testVolume("vol-1", "i-1")}
+	images := []*ec2.Image{{
+		ImageId: aws.String("ami-1"),
+		BlockDeviceMappings: []*ec2.BlockDeviceMapping{
+			{DeviceName: aws.String("/dev/xvda"), Ebs: &ec2.EbsBlockDevice{SnapshotId: aws.String("snap-2")}},
+			{DeviceName: aws.String("/dev/xvdb")},
+		},
+	}}
+	snapshots := []*ec2.Snapshot{
+		{SnapshotId: aws.String("snap-1"), VolumeId: aws.String("vol-1")},
+		{SnapshotId: aws.String("snap-2"), VolumeId: aws.String("vol-gone")},
+		{SnapshotId: aws.String("snap-3"), VolumeId: aws.String("vol-gone")},
+	}
+	orphaned := GetOrphanedSnapshots(snapshots, volumes, images)
+	if len(orphaned) != 1 || *orphaned[0].SnapshotId != "snap-3" {
+		t.Errorf("Expected only snap-3 to be orphaned, got %v", orphaned)
+	}
+}
+
+// TestLinkInstanceStorage checks that block device mappings and image IDs resolve to the supplied records
+func TestLinkInstanceStorage(t *testing.T) {
+	instances := []*ec2.Instance{{
+		InstanceId: aws.String("i-1"),
+		ImageId:    aws.String("ami-1"),
+		BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+			{Ebs: &ec2.EbsInstanceBlockDevice{VolumeId: aws.String("vol-1")}},
+			{Ebs: &ec2.EbsInstanceBlockDevice{VolumeId: aws.String("vol-unknown")}},
+		},
+	}, {
+		InstanceId: aws.String("i-2"),
+		ImageId:    aws.String("ami-public"),
+	}}
+	volumes := []*ec2.Volume{testVolume("vol-1", "i-1"), testVolume("vol-2")}
+	images := []*ec2.Image{{ImageId: aws.String("ami-1")}}
+
+	links := LinkInstanceStorage(instances, volumes, images)
+	if len(links) != 2 {
+		t.Fatalf("Expected 2 links, got %d", len(links))
+	}
+	if l := links["i-1"]; l.Image == nil || len(l.Volumes) != 1 || *l.Volumes[0].VolumeId != "vol-1" {
+		t.Errorf("i-1 not linked to ami-1 and vol-1: %+v", l)
+	}
+	if l := links["i-2"]; l.Image != nil || len(l.Volumes) != 0 {
+		t.Errorf("i-2 should not link to any owned image or volume: %+v", l)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecr"
+)
+
+// ECRRepository holds an ECR repository along with its lifecycle policy and images.
+// Scan-on-push, tag immutability and encryption are part of the repository itself,
+// scan findings summaries are part of each image
+type ECRRepository struct {
+	Repository      *ecr.Repository
+	LifecyclePolicy *string
+	Images          []*ecr.ImageDetail
+}
+
+// GetAllECRRepositories returns a complete list of ECR repositories and their images for a given session.
+// When maxImages is positive only that many of the most recently pushed images are kept per repository
+func GetAllECRRepositories(sess *session.Session, maxImages int) ([]*ECRRepository, error) {
+	ecrc := ecr.New(sess)
+	var repositories []*ecr.Repository
+	err := withRetry(func() error {
+		repositories = nil
+		return ecrc.DescribeRepositoriesPages(&ecr.DescribeRepositoriesInput{}, func(page *ecr.DescribeRepositoriesOutput, lastPage bool) bool {
+			repositories = append(repositories, page.Repositories...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var allRepositories []*ECRRepository
+	for _, r := range repositories {
+		repository := &ECRRepository{Repository: r}
+		err := withRetry(func() error {
+			result, err := ecrc.GetLifecyclePolicy(&ecr.GetLifecyclePolicyInput{RepositoryName: r.RepositoryName})
+			if err != nil {
+				// Repositories without a lifecycle policy are reported through an error
+				if aerr, ok := err.(awserr.Error); ok && aerr.Code() == ecr.ErrCodeLifecyclePolicyNotFoundException {
+					return nil
+				}
+				return err
+			}
+			repository.LifecyclePolicy = result.LifecyclePolicyText
+			return nil
+		})
+		if err != nil {
+			return allRepositories, err
+		}
+		err = withRetry(func() error {
+			repository.Images = nil
+			return ecrc.DescribeImagesPages(&ecr.DescribeImagesInput{RepositoryName: r.RepositoryName}, func(page *ecr.DescribeImagesOutput, lastPage bool) bool {
+				repository.Images = append(repository.Images, page.ImageDetails...)
+				return true
+			})
+		})
+		if err != nil {
+			return allRepositories, err
+		}
+		repository.Images = LatestImages(repository.Images, maxImages)
+		allRepositories = append(allRepositories, repository)
+	}
+	return allRepositories, nil
+}
+
+// LatestImages returns images sorted from the most to the least recently pushed,
+// limited to the first max of them when max is positive
+func LatestImages(images []*ecr.ImageDetail, max int) []*ecr.ImageDetail {
+	sort.SliceStable(images, func(i, j int) bool {
+		return aws.TimeValue(images[i].ImagePushedAt).After(aws.TimeValue(images[j].ImagePushedAt))
+	})
+	if max > 0 && len(images) > max {
+		return images[:max]
+	}
+	return images
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecr"
+)
+
+// TestLatestImages checks that images are ordered by push date and limited when requested
+func TestLatestImages(t *testing.T) {
+	now := time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC)
+	newImages := func() []*ecr.ImageDetail {
+		return []*ecr.ImageDetail{
+			{ImageDigest: aws.String("sha256:old"), ImagePushedAt: aws.Time(now.AddDate(0, -2, 0))},
+			{ImageDigest: aws.String("sha256:new"), ImagePushedAt: aws.Time(now)},
+			{ImageDigest: aws.String("sha256:mid"), ImagePushedAt: aws.Time(now.AddDate(0, -1, 0))},
+		}
+	}
+	for _, testCase := range []struct {
+		max     int
+		digests []string
+	}{
+		{0, []string{"sha256:new", "sha256:mid", "sha256:old"}},
+		{2, []string{"sha256:new", "sha256:mid"}},
+		{5, []string{"sha256:new", "sha256:mid", "sha256:old"}},
+	} {
+		images := LatestImages(newImages(), testCase.max)
+		if len(images) != len(testCase.digests) {
+			t.Errorf("max %d\tWant:%d images\tHave:%d", testCase.max, len(testCase.digests), len(images))
+			continue
+		}
+		for i, digest := range testCase.digests {
+			if have := aws.StringValue(images[i].ImageDigest); have != digest {
+				t.Errorf("max %d, image %d\tWant:%s\tHave:%s", testCase.max, i, digest, have)
+			}
+		}
+	}
+}
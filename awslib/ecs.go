@@ -0,0 +1,168 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+// ECSCluster holds an ECS cluster along with its services and container instances
+type ECSCluster struct {
+	Cluster            *ecs.Cluster
+	Services           []*ecs.Service
+	ContainerInstances []*ecs.ContainerInstance
+}
+
+// GetAllECSClusters returns a complete list of ECS clusters with their services and container instances for a given session
+func GetAllECSClusters(sess *session.Session) ([]*ECSCluster, error) {
+	ecsc := ecs.New(sess)
+	var arns []*string
+	err := withRetry(func() error {
+		arns = nil
+		return ecsc.ListClustersPages(&ecs.ListClustersInput{}, func(page *ecs.ListClustersOutput, lastPage bool) bool {
+			arns = append(arns, page.ClusterArns...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var allClusters []*ECSCluster
+	// DescribeClusters accepts at most 100 clusters per call
+	for _, chunk := range chunkStrings(arns, 100) {
+		var result *ecs.DescribeClustersOutput
+		err := withRetry(func() (err error) {
+			result, err = ecsc.DescribeClusters(&ecs.DescribeClustersInput{
+				Clusters: chunk,
+				Include:  aws.StringSlice([]string{ecs.ClusterFieldSettings, ecs.ClusterFieldTags}),
+			})
+			return err
+		})
+		if err != nil {
+			return allClusters, err
+		}
+		for _, c := range result.Clusters {
+			cluster := &ECSCluster{Cluster: c}
+			if cluster.Services, err = getECSServices(ecsc, c.ClusterArn); err != nil {
+				return allClusters, err
+			}
+			if cluster.ContainerInstances, err = getECSContainerInstances(ecsc, c.ClusterArn); err != nil {
+				return allClusters, err
+			}
+			allClusters = append(allClusters, cluster)
+		}
+	}
+	return allClusters, nil
+}
+
+// getECSServices returns every service running in the given cluster
+func getECSServices(ecsc *ecs.ECS, cluster *string) ([]*ecs.Service, error) {
+	var arns []*string
+	err := withRetry(func() error {
+		arns = nil
+		return ecsc.ListServicesPages(&ecs.ListServicesInput{Cluster: cluster}, func(page *ecs.ListServicesOutput, lastPage bool) bool {
+			arns = append(arns, page.ServiceArns...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	var services []*ecs.Service
+	// DescribeServices accepts at most 10 services per call
+	for _, chunk := range chunkStrings(arns, 10) {
+		err := withRetry(func() error {
+			result, err := ecsc.DescribeServices(&ecs.DescribeServicesInput{
+				Cluster:  cluster,
+				Services: chunk,
+				Include:  aws.StringSlice([]string{ecs.ServiceFieldTags}),
+			})
+			if err == nil {
+				services = append(services, result.Services...)
+			}
+			return err
+		})
+		if err != nil {
+			return services, err
+		}
+	}
+	return services, nil
+}
+
+// getECSContainerInstances returns every container instance registered to the given cluster
+func getECSContainerInstances(ecsc *ecs.ECS, cluster *string) ([]*ecs.ContainerInstance, error) {
+	var arns []*string
+	err := withRetry(func() error {
+		arns = nil
+		return ecsc.ListContainerInstancesPages(&ecs.ListContainerInstancesInput{Cluster: cluster}, func(page *ecs.ListContainerInstancesOutput, lastPage bool) bool {
+			arns = append(arns, page.ContainerInstanceArns...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	var instances []*ecs.ContainerInstance
+	// DescribeContainerInstances accepts at most 100 container instances per call
+	for _, chunk := range chunkStrings(arns, 100) {
+		err := withRetry(func() error {
+			result, err := ecsc.DescribeContainerInstances(&ecs.DescribeContainerInstancesInput{
+				Cluster:            cluster,
+				ContainerInstances: chunk,
+				Include:            aws.StringSlice([]string{ecs.ContainerInstanceFieldTags}),
+			})
+			if err == nil {
+				instances = append(instances, result.ContainerInstances...)
+			}
+			return err
+		})
+		if err != nil {
+			return instances, err
+		}
+	}
+	return instances, nil
+}
+
+// GetAllTaskDefinitions returns every active task definition revision for a given session
+func GetAllTaskDefinitions(sess *session.Session) ([]*ecs.TaskDefinition, error) {
+	ecsc := ecs.New(sess)
+	var arns []*string
+	err := withRetry(func() error {
+		arns = nil
+		input := &ecs.ListTaskDefinitionsInput{Status: aws.String(ecs.TaskDefinitionStatusActive)}
+		return ecsc.ListTaskDefinitionsPages(input, func(page *ecs.ListTaskDefinitionsOutput, lastPage bool) bool {
+			arns = append(arns, page.TaskDefinitionArns...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	var allTaskDefinitions []*ecs.TaskDefinition
+	for _, arn := range arns {
+		err := withRetry(func() error {
+			result, err := ecsc.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{TaskDefinition: arn})
+			if err == nil {
+				allTaskDefinitions = append(allTaskDefinitions, result.TaskDefinition)
+			}
+			return err
+		})
+		if err != nil {
+			return allTaskDefinitions, err
+		}
+	}
+	return allTaskDefinitions, nil
+}
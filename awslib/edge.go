@@ -0,0 +1,226 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/wafv2"
+)
+
+// RestAPI holds an API Gateway REST API along with its stages
+type RestAPI struct {
+	API    *apigateway.RestApi
+	Stages []*apigateway.Stage
+}
+
+// HTTPAPI holds an API Gateway HTTP or WebSocket API along with its stages
+type HTTPAPI struct {
+	API    *apigatewayv2.Api
+	Stages []*apigatewayv2.Stage
+}
+
+// WebACL holds a WAF web ACL along with the ARNs of the regional resources it protects.
+// CloudFront associations are recorded on the distributions instead
+type WebACL struct {
+	ACL       *wafv2.WebACL
+	Resources []*string
+}
+
+// GetAllDistributions returns a complete list of CloudFront distributions.
+// CloudFront is a global service, any session can be used
+func GetAllDistributions(sess *session.Session) ([]*cloudfront.DistributionSummary, error) {
+	cfc := cloudfront.New(sess)
+	var allDistributions []*cloudfront.DistributionSummary
+	err := withRetry(func() error {
+		allDistributions = nil
+		return cfc.ListDistributionsPages(&cloudfront.ListDistributionsInput{}, func(page *cloudfront.ListDistributionsOutput, lastPage bool) bool {
+			if page.DistributionList != nil {
+				allDistributions = append(allDistributions, page.DistributionList.Items...)
+			}
+			return true
+		})
+	})
+	return allDistributions, err
+}
+
+// GetAllRestAPIs returns a complete list of API Gateway REST APIs and their stages for a given session
+func GetAllRestAPIs(sess *session.Session) ([]*RestAPI, error) {
+	agc := apigateway.New(sess)
+	var apis []*apigateway.RestApi
+	err := withRetry(func() error {
+		apis = nil
+		return agc.GetRestApisPages(&apigateway.GetRestApisInput{}, func(page *apigateway.GetRestApisOutput, lastPage bool) bool {
+			apis = append(apis, page.Items...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	var allAPIs []*RestAPI
+	for _, a := range apis {
+		api := &RestAPI{API: a}
+		err := withRetry(func() error {
+			result, err := agc.GetStages(&apigateway.GetStagesInput{RestApiId: a.Id})
+			if err == nil {
+				api.Stages = result.Item
+			}
+			return err
+		})
+		if err != nil {
+			return allAPIs, err
+		}
+		allAPIs = append(allAPIs, api)
+	}
+	return allAPIs, nil
+}
+
+// GetAllRestDomainNames returns a complete list of API Gateway custom domain names for a given session
+func GetAllRestDomainNames(sess *session.Session) ([]*apigateway.DomainName, error) {
+	agc := apigateway.New(sess)
+	var allDomains []*apigateway.DomainName
+	err := withRetry(func() error {
+		allDomains = nil
+		return agc.GetDomainNamesPages(&apigateway.GetDomainNamesInput{}, func(page *apigateway.GetDomainNamesOutput, lastPage bool) bool {
+			allDomains = append(allDomains, page.Items...)
+			return true
+		})
+	})
+	return allDomains, err
+}
+
+// GetAllHTTPAPIs returns a complete list of API Gateway HTTP and WebSocket APIs and their stages for a given session
+func GetAllHTTPAPIs(sess *session.Session) ([]*HTTPAPI, error) {
+	agc := apigatewayv2.New(sess)
+	var allAPIs []*HTTPAPI
+	input := apigatewayv2.GetApisInput{}
+	for {
+		var result *apigatewayv2.GetApisOutput
+		err := withRetry(func() (err error) {
+			result, err = agc.GetApis(&input)
+			return err
+		})
+		if err != nil {
+			return allAPIs, err
+		}
+		for _, a := range result.Items {
+			api := &HTTPAPI{API: a}
+			stagesInput := apigatewayv2.GetStagesInput{ApiId: a.ApiId}
+			for {
+				var stages *apigatewayv2.GetStagesOutput
+				err := withRetry(func() (err error) {
+					stages, err = agc.GetStages(&stagesInput)
+					return err
+				})
+				if err != nil {
+					return allAPIs, err
+				}
+				api.Stages = append(api.Stages, stages.Items...)
+				if stages.NextToken == nil {
+					break
+				}
+				stagesInput.SetNextToken(*stages.NextToken)
+			}
+			allAPIs = append(allAPIs, api)
+		}
+		if result.NextToken == nil {
+			break
+		}
+		input.SetNextToken(*result.NextToken)
+	}
+	return allAPIs, nil
+}
+
+// GetAllHTTPDomainNames returns a complete list of API Gateway v2 custom domain names for a given session
+func GetAllHTTPDomainNames(sess *session.Session) ([]*apigatewayv2.DomainName, error) {
+	agc := apigatewayv2.New(sess)
+	var allDomains []*apigatewayv2.DomainName
+	input := apigatewayv2.GetDomainNamesInput{}
+	for {
+		var result *apigatewayv2.GetDomainNamesOutput
+		err := withRetry(func() (err error) {
+			result, err = agc.GetDomainNames(&input)
+			return err
+		})
+		if err != nil {
+			return allDomains, err
+		}
+		allDomains = append(allDomains, result.Items...)
+		if result.NextToken == nil {
+			break
+		}
+		input.SetNextToken(*result.NextToken)
+	}
+	return allDomains, nil
+}
+
+// GetAllWebACLs returns a complete list of WAF web ACLs of the given scope for a given session.
+// Use wafv2.ScopeRegional for a region's ACLs, and wafv2.ScopeCloudfront with a us-east-1 session for CloudFront ACLs
+func GetAllWebACLs(sess *session.Session, scope string) ([]*WebACL, error) {
+	wafc := wafv2.New(sess)
+	var summaries []*wafv2.WebACLSummary
+	input := wafv2.ListWebACLsInput{Scope: aws.String(scope)}
+	for {
+		var result *wafv2.ListWebACLsOutput
+		err := withRetry(func() (err error) {
+			result, err = wafc.ListWebACLs(&input)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, result.WebACLs...)
+		if result.NextMarker == nil || len(result.WebACLs) == 0 {
+			break
+		}
+		input.NextMarker = result.NextMarker
+	}
+
+	var allACLs []*WebACL
+	for _, s := range summaries {
+		acl := &WebACL{}
+		err := withRetry(func() error {
+			result, err := wafc.GetWebACL(&wafv2.GetWebACLInput{Id: s.Id, Name: s.Name, Scope: aws.String(scope)})
+			if err == nil {
+				acl.ACL = result.WebACL
+			}
+			return err
+		})
+		if err != nil {
+			return allACLs, err
+		}
+		if scope == wafv2.ScopeRegional {
+			for _, resourceType := range []string{wafv2.ResourceTypeApplicationLoadBalancer, wafv2.ResourceTypeApiGateway} {
+				err := withRetry(func() error {
+					result, err := wafc.ListResourcesForWebACL(&wafv2.ListResourcesForWebACLInput{
+						WebACLArn:    s.ARN,
+						ResourceType: aws.String(resourceType),
+					})
+					if err == nil {
+						acl.Resources = append(acl.Resources, result.ResourceArns...)
+					}
+					return err
+				})
+				if err != nil {
+					return allACLs, err
+				}
+			}
+		}
+		allACLs = append(allACLs, acl)
+	}
+	return allACLs, nil
+}
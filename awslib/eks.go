@@ -0,0 +1,146 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/eks"
+)
+
+// EKSCluster holds an EKS cluster along with its managed node groups, Fargate profiles and add-ons
+type EKSCluster struct {
+	Cluster         *eks.Cluster
+	Nodegroups      []*eks.Nodegroup
+	FargateProfiles []*eks.FargateProfile
+	Addons          []*eks.Addon
+}
+
+// GetAllEKSClusters returns a complete list of EKS clusters and their components for a given session
+func GetAllEKSClusters(sess *session.Session) ([]*EKSCluster, error) {
+	eksc := eks.New(sess)
+	var names []*string
+	input := eks.ListClustersInput{}
+	for {
+		var result *eks.ListClustersOutput
+		err := withRetry(func() (err error) {
+			result, err = eksc.ListClusters(&input)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, result.Clusters...)
+		if result.NextToken == nil {
+			break
+		}
+		input.SetNextToken(*result.NextToken)
+	}
+
+	var allClusters []*EKSCluster
+	for _, name := range names {
+		cluster, err := getEKSCluster(eksc, name)
+		if err != nil {
+			return allClusters, err
+		}
+		allClusters = append(allClusters, cluster)
+	}
+	return allClusters, nil
+}
+
+// getEKSCluster describes a single EKS cluster along with its node groups, Fargate profiles and add-ons
+func getEKSCluster(eksc *eks.EKS, name *string) (*EKSCluster, error) {
+	var cluster EKSCluster
+	err := withRetry(func() error {
+		result, err := eksc.DescribeCluster(&eks.DescribeClusterInput{Name: name})
+		if err == nil {
+			cluster.Cluster = result.Cluster
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var nodegroups []*string
+	err = withRetry(func() error {
+		nodegroups = nil
+		return eksc.ListNodegroupsPages(&eks.ListNodegroupsInput{ClusterName: name}, func(page *eks.ListNodegroupsOutput, lastPage bool) bool {
+			nodegroups = append(nodegroups, page.Nodegroups...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, ng := range nodegroups {
+		err = withRetry(func() error {
+			result, err := eksc.DescribeNodegroup(&eks.DescribeNodegroupInput{ClusterName: name, NodegroupName: ng})
+			if err == nil {
+				cluster.Nodegroups = append(cluster.Nodegroups, result.Nodegroup)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var profiles []*string
+	err = withRetry(func() error {
+		profiles = nil
+		return eksc.ListFargateProfilesPages(&eks.ListFargateProfilesInput{ClusterName: name}, func(page *eks.ListFargateProfilesOutput, lastPage bool) bool {
+			profiles = append(profiles, page.FargateProfileNames...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, fp := range profiles {
+		err = withRetry(func() error {
+			result, err := eksc.DescribeFargateProfile(&eks.DescribeFargateProfileInput{ClusterName: name, FargateProfileName: fp})
+			if err == nil {
+				cluster.FargateProfiles = append(cluster.FargateProfiles, result.FargateProfile)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var addons []*string
+	err = withRetry(func() error {
+		addons = nil
+		return eksc.ListAddonsPages(&eks.ListAddonsInput{ClusterName: name}, func(page *eks.ListAddonsOutput, lastPage bool) bool {
+			addons = append(addons, page.Addons...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range addons {
+		err = withRetry(func() error {
+			result, err := eksc.DescribeAddon(&eks.DescribeAddonInput{ClusterName: name, AddonName: a})
+			if err == nil {
+				cluster.Addons = append(cluster.Addons, result.Addon)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &cluster, nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// GetAllLoadBalancers returns a complete list of application, network and gateway load balancers for a given session
+func GetAllLoadBalancers(sess *session.Session) ([]*elbv2.LoadBalancer, error) {
+	elbc := elbv2.New(sess)
+	var allLoadBalancers []*elbv2.LoadBalancer
+	err := withRetry(func() error {
+		allLoadBalancers = nil
+		return elbc.DescribeLoadBalancersPages(&elbv2.DescribeLoadBalancersInput{}, func(page *elbv2.DescribeLoadBalancersOutput, lastPage bool) bool {
+			allLoadBalancers = append(allLoadBalancers, page.LoadBalancers...)
+			return true
+		})
+	})
+	return allLoadBalancers, err
+}
+
+// GetAllClassicLoadBalancers returns a complete list of classic load balancers for a given session
+func GetAllClassicLoadBalancers(sess *session.Session) ([]*elb.LoadBalancerDescription, error) {
+	elbc := elb.New(sess)
+	var allLoadBalancers []*elb.LoadBalancerDescription
+	err := withRetry(func() error {
+		allLoadBalancers = nil
+		return elbc.DescribeLoadBalancersPages(&elb.DescribeLoadBalancersInput{}, func(page *elb.DescribeLoadBalancersOutput, lastPage bool) bool {
+			allLoadBalancers = append(allLoadBalancers, page.LoadBalancerDescriptions...)
+			return true
+		})
+	})
+	return allLoadBalancers, err
+}
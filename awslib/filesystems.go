@@ -0,0 +1,159 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/aws/aws-sdk-go/service/fsx"
+)
+
+// EFSFileSystem holds an EFS file system along with its mount targets
+type EFSFileSystem struct {
+	FileSystem   *efs.FileSystemDescription
+	MountTargets []*EFSMountTarget
+}
+
+// EFSMountTarget holds an EFS mount target along with the IDs of its security groups
+type EFSMountTarget struct {
+	MountTarget    *efs.MountTargetDescription
+	SecurityGroups []*string
+}
+
+// FileSystemNetwork holds the VPC and subnets an EFS or FSx file system is reachable from
+type FileSystemNetwork struct {
+	VpcID     string
+	SubnetIDs []string
+}
+
+// GetAllEFSFileSystems returns a complete list of EFS file systems and their mount targets for a given session
+func GetAllEFSFileSystems(sess *session.Session) ([]*EFSFileSystem, error) {
+	efsc := efs.New(sess)
+	var fileSystems []*efs.FileSystemDescription
+	err := withRetry(func() error {
+		fileSystems = nil
+		return efsc.DescribeFileSystemsPages(&efs.DescribeFileSystemsInput{}, func(page *efs.DescribeFileSystemsOutput, lastPage bool) bool {
+			fileSystems = append(fileSystems, page.FileSystems...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var allFileSystems []*EFSFileSystem
+	for _, f := range fileSystems {
+		fileSystem := &EFSFileSystem{FileSystem: f}
+		var mountTargets []*efs.MountTargetDescription
+		err := withRetry(func() error {
+			mountTargets = nil
+			return efsc.DescribeMountTargetsPages(&efs.DescribeMountTargetsInput{FileSystemId: f.FileSystemId}, func(page *efs.DescribeMountTargetsOutput, lastPage bool) bool {
+				mountTargets = append(mountTargets, page.MountTargets...)
+				return true
+			})
+		})
+		if err != nil {
+			return allFileSystems, err
+		}
+		for _, mt := range mountTargets {
+			mountTarget := &EFSMountTarget{MountTarget: mt}
+			err := withRetry(func() error {
+				result, err := efsc.DescribeMountTargetSecurityGroups(&efs.DescribeMountTargetSecurityGroupsInput{MountTargetId: mt.MountTargetId})
+				if err == nil {
+					mountTarget.SecurityGroups = result.SecurityGroups
+				}
+				return err
+			})
+			if err != nil {
+				return allFileSystems, err
+			}
+			fileSystem.MountTargets = append(fileSystem.MountTargets, mountTarget)
+		}
+		allFileSystems = append(allFileSystems, fileSystem)
+	}
+	return allFileSystems, nil
+}
+
+// GetAllFSxFileSystems returns a complete list of FSx file systems, of every type, for a given session
+func GetAllFSxFileSystems(sess *session.Session) ([]*fsx.FileSystem, error) {
+	fsxc := fsx.New(sess)
+	var allFileSystems []*fsx.FileSystem
+	err := withRetry(func() error {
+		allFileSystems = nil
+		return fsxc.DescribeFileSystemsPages(&fsx.DescribeFileSystemsInput{}, func(page *fsx.DescribeFileSystemsOutput, lastPage bool) bool {
+			allFileSystems = append(allFileSystems, page.FileSystems...)
+			return true
+		})
+	})
+	return allFileSystems, err
+}
+
+// GetFileSystemNetworks maps the ID of every EFS and FSx file system to the VPC and subnets it is reachable from.
+// EFS file systems are only reachable through their mount targets
+func GetFileSystemNetworks(efsFileSystems []*EFSFileSystem, fsxFileSystems []*fsx.FileSystem) map[string]*FileSystemNetwork {
+	networks := make(map[string]*FileSystemNetwork)
+	for _, f := range efsFileSystems {
+		network := &FileSystemNetwork{}
+		for _, mt := range f.MountTargets {
+			network.VpcID = aws.StringValue(mt.MountTarget.VpcId)
+			network.SubnetIDs = append(network.SubnetIDs, aws.StringValue(mt.MountTarget.SubnetId))
+		}
+		sort.Strings(network.SubnetIDs)
+		networks[aws.StringValue(f.FileSystem.FileSystemId)] = network
+	}
+	for _, f := range fsxFileSystems {
+		networks[aws.StringValue(f.FileSystemId)] = &FileSystemNetwork{
+			VpcID:     aws.StringValue(f.VpcId),
+			SubnetIDs: aws.StringValueSlice(f.SubnetIds),
+		}
+	}
+	return networks
+}
+
+// GetVpcs returns the VPCs with the given IDs for a given session
+func GetVpcs(sess *session.Session, ids []string) ([]*ec2.Vpc, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	ec2c := ec2.New(sess)
+	var vpcs []*ec2.Vpc
+	err := withRetry(func() error {
+		vpcs = nil
+		return ec2c.DescribeVpcsPages(&ec2.DescribeVpcsInput{VpcIds: aws.StringSlice(ids)}, func(page *ec2.DescribeVpcsOutput, lastPage bool) bool {
+			vpcs = append(vpcs, page.Vpcs...)
+			return true
+		})
+	})
+	return vpcs, err
+}
+
+// GetSubnets returns the subnets with the given IDs for a given session
+func GetSubnets(sess *session.Session, ids []string) ([]*ec2.Subnet, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	ec2c := ec2.New(sess)
+	var subnets []*ec2.Subnet
+	err := withRetry(func() error {
+		subnets = nil
+		return ec2c.DescribeSubnetsPages(&ec2.DescribeSubnetsInput{SubnetIds: aws.StringSlice(ids)}, func(page *ec2.DescribeSubnetsOutput, lastPage bool) bool {
+			subnets = append(subnets, page.Subnets...)
+			return true
+		})
+	})
+	return subnets, err
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/aws/aws-sdk-go/service/fsx"
+)
+
+// TestGetFileSystemNetworks checks that EFS and FSx file systems are linked to their VPC and subnets
+func TestGetFileSystemNetworks(t *testing.T) {
+	efsFileSystems := []*EFSFileSystem{
+		{
+			FileSystem: &efs.FileSystemDescription{FileSystemId: aws.String("fs-efs")},
+			MountTargets: []*EFSMountTarget{
+				{MountTarget: &efs.MountTargetDescription{VpcId: aws.String("vpc-1"), SubnetId: aws.String("subnet-b")}},
+				{MountTarget: &efs.MountTargetDescription{VpcId: aws.String("vpc-1"), SubnetId: aws.String("subnet-a")}},
+			},
+		},
+		{FileSystem: &efs.FileSystemDescription{FileSystemId: aws.String("fs-unmounted")}},
+	}
+	fsxFileSystems := []*fsx.FileSystem{
+		{FileSystemId: aws.String("fs-fsx"), VpcId: aws.String("vpc-2"), SubnetIds: aws.StringSlice([]string{"subnet-c"})},
+	}
+	want := map[string]*FileSystemNetwork{
+		"fs-efs":       {VpcID: "vpc-1", SubnetIDs: []string{"subnet-a", "subnet-b"}},
+		"fs-unmounted": {},
+		"fs-fsx":       {VpcID: "vpc-2", SubnetIDs: []string{"subnet-c"}},
+	}
+	have := GetFileSystemNetworks(efsFileSystems, fsxFileSystems)
+	if !reflect.DeepEqual(want, have) {
+		for id, network := range have {
+			t.Logf("%s: %+v", id, network)
+		}
+		t.Errorf("Unexpected file system networks")
+	}
+}
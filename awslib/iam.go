@@ -0,0 +1,235 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+// IAMInventory holds the IAM entities of an account.
+// IAM is a global service, the inventory is not tied to any region
+type IAMInventory struct {
+	Users       []*iam.UserDetail
+	Groups      []*iam.GroupDetail
+	Roles       []*iam.RoleDetail
+	Policies    []*iam.ManagedPolicyDetail
+	Credentials []*IAMCredential
+}
+
+// IAMCredential holds the credential status of a single user as reported by the IAM credential report
+type IAMCredential struct {
+	User             string
+	Arn              string
+	PasswordEnabled  bool
+	PasswordLastUsed *time.Time
+	MFAActive        bool
+	AccessKeys       []*IAMAccessKey
+}
+
+// IAMAccessKey holds the age and last usage of one of the (at most two) access keys of a user.
+// The credential report does not expose access key IDs, only their slot
+type IAMAccessKey struct {
+	Slot            int
+	Active          bool
+	LastRotated     *time.Time
+	AgeDays         int
+	LastUsed        *time.Time
+	LastUsedRegion  string
+	LastUsedService string
+}
+
+// GetIAMInventory returns the users, groups, roles, customer managed policies and credential status of the account.
+// Policy documents are returned decoded rather than URL encoded
+func GetIAMInventory(sess *session.Session) (*IAMInventory, error) {
+	var inv IAMInventory
+	iamc := iam.New(sess)
+	// A single paginated call returns every entity along with its inline and attached policies
+	input := iam.GetAccountAuthorizationDetailsInput{
+		Filter: aws.StringSlice([]string{
+			iam.EntityTypeUser,
+			iam.EntityTypeGroup,
+			iam.EntityTypeRole,
+			iam.EntityTypeLocalManagedPolicy,
+		}),
+	}
+	err := withRetry(func() error {
+		inv.Users, inv.Groups, inv.Roles, inv.Policies = nil, nil, nil, nil
+		return iamc.GetAccountAuthorizationDetailsPages(&input, func(page *iam.GetAccountAuthorizationDetailsOutput, lastPage bool) bool {
+			inv.Users = append(inv.Users, page.UserDetailList...)
+			inv.Groups = append(inv.Groups, page.GroupDetailList...)
+			inv.Roles = append(inv.Roles, page.RoleDetailList...)
+			inv.Policies = append(inv.Policies, page.Policies...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	decodeIAMPolicies(&inv)
+
+	inv.Credentials, err = GetCredentialReport(sess)
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// credentialReportTimeout bounds the wait for the credential report to be generated
+var credentialReportTimeout = 5 * time.Minute
+
+// GetCredentialReport generates and returns the IAM credential report of the account.
+// Fails if the report is not generated within credentialReportTimeout
+func GetCredentialReport(sess *session.Session) ([]*IAMCredential, error) {
+	iamc := iam.New(sess)
+	b := newBackoff()
+	b.Min = time.Second
+	deadline := time.Now().Add(credentialReportTimeout)
+	for {
+		result, err := iamc.GenerateCredentialReport(&iam.GenerateCredentialReportInput{})
+		if err != nil && !isThrottled(err) {
+			return nil, err
+		}
+		if err == nil && aws.StringValue(result.State) == iam.ReportStateTypeComplete {
+			break
+		}
+		// Report generation usually completes within a few seconds
+		wait := b.Duration()
+		if time.Now().Add(wait).After(deadline) {
+			return nil, fmt.Errorf("IAM credential report was not generated within %v", credentialReportTimeout)
+		}
+		time.Sleep(wait)
+	}
+	var report *iam.GetCredentialReportOutput
+	err := withRetry(func() (err error) {
+		report, err = iamc.GetCredentialReport(&iam.GetCredentialReportInput{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ParseCredentialReport(report.Content, time.Now())
+}
+
+// ParseCredentialReport parses the CSV content of an IAM credential report.
+// Access key ages are computed relative to now
+func ParseCredentialReport(content []byte, now time.Time) ([]*IAMCredential, error) {
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid credential report: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[name] = i
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	var credentials []*IAMCredential
+	for _, record := range records[1:] {
+		c := &IAMCredential{
+			User:             field(record, "user"),
+			Arn:              field(record, "arn"),
+			PasswordEnabled:  field(record, "password_enabled") == "true",
+			PasswordLastUsed: parseReportTime(field(record, "password_last_used")),
+			MFAActive:        field(record, "mfa_active") == "true",
+		}
+		for slot := 1; slot <= 2; slot++ {
+			prefix := fmt.Sprintf("access_key_%d_", slot)
+			key := &IAMAccessKey{
+				Slot:            slot,
+				Active:          field(record, prefix+"active") == "true",
+				LastRotated:     parseReportTime(field(record, prefix+"last_rotated")),
+				LastUsed:        parseReportTime(field(record, prefix+"last_used_date")),
+				LastUsedRegion:  reportValue(field(record, prefix+"last_used_region")),
+				LastUsedService: reportValue(field(record, prefix+"last_used_service")),
+			}
+			// Slots which never held a key are reported without a rotation date
+			if key.LastRotated == nil {
+				continue
+			}
+			key.AgeDays = int(now.Sub(*key.LastRotated).Hours() / 24)
+			c.AccessKeys = append(c.AccessKeys, key)
+		}
+		credentials = append(credentials, c)
+	}
+	return credentials, nil
+}
+
+// parseReportTime parses a credential report timestamp, returning nil for N/A style placeholders
+func parseReportTime(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// reportValue blanks out the placeholders used by the credential report for missing values
+func reportValue(value string) string {
+	if value == "N/A" || value == "no_information" || value == "not_supported" {
+		return ""
+	}
+	return value
+}
+
+// decodeIAMPolicies replaces the URL encoded policy documents of inv with their JSON form
+func decodeIAMPolicies(inv *IAMInventory) {
+	for _, u := range inv.Users {
+		decodePolicyDetails(u.UserPolicyList)
+	}
+	for _, g := range inv.Groups {
+		decodePolicyDetails(g.GroupPolicyList)
+	}
+	for _, r := range inv.Roles {
+		r.AssumeRolePolicyDocument = decodePolicyDocument(r.AssumeRolePolicyDocument)
+		decodePolicyDetails(r.RolePolicyList)
+	}
+	for _, p := range inv.Policies {
+		for _, v := range p.PolicyVersionList {
+			v.Document = decodePolicyDocument(v.Document)
+		}
+	}
+}
+
+func decodePolicyDetails(policies []*iam.PolicyDetail) {
+	for _, p := range policies {
+		p.PolicyDocument = decodePolicyDocument(p.PolicyDocument)
+	}
+}
+
+func decodePolicyDocument(document *string) *string {
+	if document == nil {
+		return nil
+	}
+	decoded, err := url.PathUnescape(*document)
+	if err != nil {
+		// Leave documents which are not URL encoded untouched
+		return document
+	}
+	return &decoded
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+const testCredentialReport = `user,arn,user_creation_time,password_enabled,password_last_used,password_last_changed,password_next_rotation,mfa_active,access_key_1_active,access_key_1_last_rotated,access_key_1_last_used_date,access_key_1_last_used_region,access_key_1_last_used_service,access_key_2_active,access_key_2_last_rotated,access_key_2_last_used_date,access_key_2_last_used_region,access_key_2_last_used_service,cert_1_active,cert_1_last_rotated,cert_2_active,cert_2_last_rotated
+<root_account>,arn:aws:iam::123456789012:root,2019-01-01T00:00:00+00:00,not_supported,2019-03-01T10:00:00+00:00,not_supported,not_supported,true,false,N/A,N/A,N/A,N/A,false,N/A,N/A,N/A,N/A,false,N/A,false,N/A
+alice,arn:aws:iam::123456789012:user/alice,2019-01-01T00:00:00+00:00,true,2019-03-01T10:00:00+00:00,2019-01-01T00:00:00+00:00,N/A,false,true,2019-01-01T00:00:00+00:00,2019-02-28T00:00:00+00:00,us-east-1,s3,false,2019-02-01T00:00:00+00:00,N/A,N/A,N/A,false,N/A,false,N/A
+`
+
+// TestParseCredentialReport checks that MFA status and access key ages are extracted from the report
+func TestParseCredentialReport(t *testing.T) {
+	now := time.Date(2019, 3, 2, 0, 0, 0, 0, time.UTC)
+	credentials, err := ParseCredentialReport([]byte(testCredentialReport), now)
+	if err != nil {
+		t.Fatalf("Failed to parse report: %v", err)
+	}
+	if len(credentials) != 2 {
+		t.Fatalf("Expected 2 credentials, got %d", len(credentials))
+	}
+	root := credentials[0]
+	if !root.MFAActive || root.PasswordEnabled || len(root.AccessKeys) != 0 {
+		t.Errorf("Unexpected root credential: %+v", root)
+	}
+	alice := credentials[1]
+	if alice.MFAActive || !alice.PasswordEnabled || len(alice.AccessKeys) != 2 {
+		t.Fatalf("Unexpected alice credential: %+v", alice)
+	}
+	if k := alice.AccessKeys[0]; !k.Active || k.AgeDays != 60 || k.LastUsed == nil || k.LastUsedService != "s3" {
+		t.Errorf("Unexpected first access key: %+v", k)
+	}
+	if k := alice.AccessKeys[1]; k.Active || k.Slot != 2 || k.LastUsed != nil || k.LastUsedRegion != "" {
+		t.Errorf("Unexpected second access key: %+v", k)
+	}
+}
+
+// TestGetCredentialReportTimeout checks that waiting for a report which is never generated fails
+func TestGetCredentialReportTimeout(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `<GenerateCredentialReportResponse xmlns="https://iam.amazonaws.com/doc/2010-05-08/">
+			<GenerateCredentialReportResult><State>STARTED</State></GenerateCredentialReportResult>
+			<ResponseMetadata><RequestId>1</RequestId></ResponseMetadata></GenerateCredentialReportResponse>`)
+	}))
+	defer server.Close()
+	defer func(timeout time.Duration) { credentialReportTimeout = timeout }(credentialReportTimeout)
+	credentialReportTimeout = 1500 * time.Millisecond
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(server.URL),
+		Credentials: credentials.NewStaticCredentials("test", "test", ""),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = GetCredentialReport(sess)
+	if err == nil || !strings.Contains(err.Error(), "not generated") {
+		t.Fatalf("Expected the report generation to time out, got %v", err)
+	}
+	// The second wait would end past the deadline
+	if calls != 2 {
+		t.Errorf("Expected two generation requests before giving up, got %d", calls)
+	}
+}
+
+// TestDecodeIAMPolicies checks that trust and inline policies are URL decoded
+func TestDecodeIAMPolicies(t *testing.T) {
+	inv := IAMInventory{
+		Roles: []*iam.RoleDetail{{
+			AssumeRolePolicyDocument: aws.String("%7B%22Version%22%3A%222012-10-17%22%7D"),
+			RolePolicyList:           []*iam.PolicyDetail{{PolicyDocument: aws.String("%7B%7D")}},
+		}},
+	}
+	decodeIAMPolicies(&inv)
+	if doc := *inv.Roles[0].AssumeRolePolicyDocument; doc != `{"Version":"2012-10-17"}` {
+		t.Errorf("Trust policy not decoded: %s", doc)
+	}
+	if doc := *inv.Roles[0].RolePolicyList[0].PolicyDocument; doc != "{}" {
+		t.Errorf("Inline policy not decoded: %s", doc)
+	}
+}
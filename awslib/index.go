@@ -0,0 +1,197 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// ResourceRef identifies an inventoried resource. VPC is empty for resources outside of a VPC
+type ResourceRef struct {
+	Type   string
+	ID     string
+	Region string
+	VPC    string
+}
+
+// ResourceIndex maps the addresses (IPs and DNS names) of inventoried resources to the resources owning them.
+// Private addresses are reused across VPCs and regions, an address maps to one owner per VPC of each region
+type ResourceIndex map[string][]*ResourceRef
+
+// NewResourceIndex returns an empty ResourceIndex
+func NewResourceIndex() ResourceIndex {
+	return make(ResourceIndex)
+}
+
+// Lookup returns the resources owning address, or nil if it is not part of the index
+func (idx ResourceIndex) Lookup(address string) []*ResourceRef {
+	return idx[normalizeAddress(address)]
+}
+
+// add indexes address as owned by ref. An owner of a private address only replaces a previous owner
+// of the same VPC and region, the last owner of a public address replaces any previous one
+func (idx ResourceIndex) add(address *string, ref *ResourceRef) {
+	a := normalizeAddress(aws.StringValue(address))
+	if a == "" {
+		return
+	}
+	if !isPrivateAddress(a) {
+		idx[a] = []*ResourceRef{ref}
+		return
+	}
+	for i, r := range idx[a] {
+		if r.Region == ref.Region && r.VPC == ref.VPC {
+			idx[a][i] = ref
+			return
+		}
+	}
+	idx[a] = append(idx[a], ref)
+}
+
+// AddInstances indexes the public and private IPs and DNS names of EC2 instances
+func (idx ResourceIndex) AddInstances(region string, instances []*ec2.Instance) {
+	for _, i := range instances {
+		ref := &ResourceRef{Type: "ec2:instance", ID: aws.StringValue(i.InstanceId), Region: region, VPC: aws.StringValue(i.VpcId)}
+		idx.add(i.PublicIpAddress, ref)
+		idx.add(i.PublicDnsName, ref)
+		idx.add(i.PrivateIpAddress, ref)
+		idx.add(i.PrivateDnsName, ref)
+		for _, ni := range i.NetworkInterfaces {
+			for _, ip := range ni.Ipv6Addresses {
+				idx.add(ip.Ipv6Address, ref)
+			}
+		}
+	}
+}
+
+// AddDBInstances indexes the endpoints of RDS DB instances
+func (idx ResourceIndex) AddDBInstances(region string, instances []*rds.DBInstance) {
+	for _, i := range instances {
+		if i.Endpoint == nil {
+			continue
+		}
+		ref := &ResourceRef{Type: "rds:db", ID: aws.StringValue(i.DBInstanceIdentifier), Region: region}
+		if i.DBSubnetGroup != nil {
+			ref.VPC = aws.StringValue(i.DBSubnetGroup.VpcId)
+		}
+		idx.add(i.Endpoint.Address, ref)
+	}
+}
+
+// AddDBClusters indexes the writer, reader and custom endpoints of RDS DB clusters
+func (idx ResourceIndex) AddDBClusters(region string, clusters []*rds.DBCluster) {
+	for _, c := range clusters {
+		ref := &ResourceRef{Type: "rds:cluster", ID: aws.StringValue(c.DBClusterIdentifier), Region: region}
+		idx.add(c.Endpoint, ref)
+		idx.add(c.ReaderEndpoint, ref)
+		for _, e := range c.CustomEndpoints {
+			idx.add(e, ref)
+		}
+	}
+}
+
+// AddLoadBalancers indexes the DNS names of application, network and gateway load balancers
+func (idx ResourceIndex) AddLoadBalancers(region string, loadBalancers []*elbv2.LoadBalancer) {
+	for _, lb := range loadBalancers {
+		idx.add(lb.DNSName, &ResourceRef{Type: "elasticloadbalancing:loadbalancer", ID: aws.StringValue(lb.LoadBalancerArn), Region: region, VPC: aws.StringValue(lb.VpcId)})
+	}
+}
+
+// AddClassicLoadBalancers indexes the DNS names of classic load balancers
+func (idx ResourceIndex) AddClassicLoadBalancers(region string, loadBalancers []*elb.LoadBalancerDescription) {
+	for _, lb := range loadBalancers {
+		ref := &ResourceRef{Type: "elasticloadbalancing:classic", ID: aws.StringValue(lb.LoadBalancerName), Region: region, VPC: aws.StringValue(lb.VPCId)}
+		idx.add(lb.DNSName, ref)
+		idx.add(lb.CanonicalHostedZoneName, ref)
+	}
+}
+
+// AddNetworkInterfaces indexes the private, public and IPv6 addresses of network interfaces.
+// Addresses are attributed to the attached instance when there is one, to the interface otherwise
+func (idx ResourceIndex) AddNetworkInterfaces(region string, interfaces []*ec2.NetworkInterface) {
+	for _, ni := range interfaces {
+		vpc := aws.StringValue(ni.VpcId)
+		ref := &ResourceRef{Type: "ec2:network-interface", ID: aws.StringValue(ni.NetworkInterfaceId), Region: region, VPC: vpc}
+		if ni.Attachment != nil && ni.Attachment.InstanceId != nil {
+			ref = &ResourceRef{Type: "ec2:instance", ID: aws.StringValue(ni.Attachment.InstanceId), Region: region, VPC: vpc}
+		}
+		for _, ip := range ni.PrivateIpAddresses {
+			idx.add(ip.PrivateIpAddress, ref)
+			if ip.Association != nil {
+				idx.add(ip.Association.PublicIp, ref)
+			}
+		}
+		for _, ip := range ni.Ipv6Addresses {
+			idx.add(ip.Ipv6Address, ref)
+		}
+	}
+}
+
+// AddAddresses indexes Elastic IPs. Associated addresses are attributed to their instance
+// or network interface, idle ones to the allocation itself
+func (idx ResourceIndex) AddAddresses(region string, addresses []*ec2.Address) {
+	for _, a := range addresses {
+		ref := &ResourceRef{Type: "ec2:elastic-ip", ID: aws.StringValue(a.AllocationId), Region: region}
+		if a.InstanceId != nil {
+			ref = &ResourceRef{Type: "ec2:instance", ID: aws.StringValue(a.InstanceId), Region: region}
+		} else if a.NetworkInterfaceId != nil {
+			ref = &ResourceRef{Type: "ec2:network-interface", ID: aws.StringValue(a.NetworkInterfaceId), Region: region}
+		}
+		idx.add(a.PublicIp, ref)
+	}
+}
+
+// AddNatGateways indexes the public and private addresses of NAT gateways
+func (idx ResourceIndex) AddNatGateways(region string, gateways []*ec2.NatGateway) {
+	for _, g := range gateways {
+		ref := &ResourceRef{Type: "ec2:natgateway", ID: aws.StringValue(g.NatGatewayId), Region: region, VPC: aws.StringValue(g.VpcId)}
+		for _, a := range g.NatGatewayAddresses {
+			idx.add(a.PublicIp, ref)
+			idx.add(a.PrivateIp, ref)
+		}
+	}
+}
+
+// IPs returns the subset of the index keyed by IP addresses, leaving out DNS names
+func (idx ResourceIndex) IPs() ResourceIndex {
+	ips := NewResourceIndex()
+	for address, refs := range idx {
+		if net.ParseIP(address) != nil {
+			ips[address] = refs
+		}
+	}
+	return ips
+}
+
+// isPrivateAddress reports whether address, once normalized, is a private IP or an EC2 private DNS name,
+// which can be reused by several VPCs
+func isPrivateAddress(address string) bool {
+	if ip := net.ParseIP(address); ip != nil {
+		return ip.IsPrivate()
+	}
+	return strings.HasSuffix(address, ".internal")
+}
+
+// normalizeAddress lower cases DNS names and strips the trailing dot and the dualstack
+// prefix Route 53 uses for alias targets
+func normalizeAddress(address string) string {
+	address = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(address)), ".")
+	return strings.TrimPrefix(address, "dualstack.")
+}
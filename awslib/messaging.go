@@ -0,0 +1,264 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+// SQSQueue holds an SQS queue URL along with all of its attributes.
+// RedrivePolicy, KmsMasterKeyId/SqsManagedSseEnabled and MessageRetentionPeriod describe DLQ, encryption and retention
+type SQSQueue struct {
+	URL        string
+	Attributes map[string]*string
+}
+
+// SNSTopic holds an SNS topic along with its attributes and subscriptions
+type SNSTopic struct {
+	Arn           string
+	Attributes    map[string]*string
+	Subscriptions []*sns.Subscription
+}
+
+// EventBus holds an EventBridge event bus along with its rules
+type EventBus struct {
+	Bus   *eventbridge.EventBus
+	Rules []*EventRule
+}
+
+// EventRule holds an EventBridge rule along with its targets
+type EventRule struct {
+	Rule    *eventbridge.Rule
+	Targets []*eventbridge.Target
+}
+
+// GetAllQueues returns a complete list of SQS queues and their attributes for a given session
+func GetAllQueues(sess *session.Session) ([]*SQSQueue, error) {
+	sqsc := sqs.New(sess)
+	var urls []*string
+	err := withRetry(func() error {
+		urls = nil
+		// Without MaxResults, ListQueues returns the first 1000 queues and no NextToken to page through the rest
+		return sqsc.ListQueuesPages(&sqs.ListQueuesInput{MaxResults: aws.Int64(1000)}, func(page *sqs.ListQueuesOutput, lastPage bool) bool {
+			urls = append(urls, page.QueueUrls...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	var allQueues []*SQSQueue
+	for _, url := range urls {
+		queue := &SQSQueue{URL: aws.StringValue(url)}
+		err := withRetry(func() error {
+			result, err := sqsc.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+				QueueUrl:       url,
+				AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameAll}),
+			})
+			if err == nil {
+				queue.Attributes = result.Attributes
+			}
+			return err
+		})
+		if err != nil {
+			return allQueues, err
+		}
+		allQueues = append(allQueues, queue)
+	}
+	return allQueues, nil
+}
+
+// GetAllTopics returns a complete list of SNS topics with their attributes and subscriptions for a given session
+func GetAllTopics(sess *session.Session) ([]*SNSTopic, error) {
+	snsc := sns.New(sess)
+	var topics []*sns.Topic
+	err := withRetry(func() error {
+		topics = nil
+		return snsc.ListTopicsPages(&sns.ListTopicsInput{}, func(page *sns.ListTopicsOutput, lastPage bool) bool {
+			topics = append(topics, page.Topics...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	var allTopics []*SNSTopic
+	for _, t := range topics {
+		topic := &SNSTopic{Arn: aws.StringValue(t.TopicArn)}
+		err := withRetry(func() error {
+			result, err := snsc.GetTopicAttributes(&sns.GetTopicAttributesInput{TopicArn: t.TopicArn})
+			if err == nil {
+				topic.Attributes = result.Attributes
+			}
+			return err
+		})
+		if err != nil {
+			return allTopics, err
+		}
+		err = withRetry(func() error {
+			topic.Subscriptions = nil
+			return snsc.ListSubscriptionsByTopicPages(&sns.ListSubscriptionsByTopicInput{TopicArn: t.TopicArn}, func(page *sns.ListSubscriptionsByTopicOutput, lastPage bool) bool {
+				topic.Subscriptions = append(topic.Subscriptions, page.Subscriptions...)
+				return true
+			})
+		})
+		if err != nil {
+			return allTopics, err
+		}
+		allTopics = append(allTopics, topic)
+	}
+	return allTopics, nil
+}
+
+// GetAllKinesisStreams returns a complete list of Kinesis data streams for a given session
+func GetAllKinesisStreams(sess *session.Session) ([]*kinesis.StreamDescriptionSummary, error) {
+	kc := kinesis.New(sess)
+	var names []*string
+	err := withRetry(func() error {
+		names = nil
+		return kc.ListStreamsPages(&kinesis.ListStreamsInput{}, func(page *kinesis.ListStreamsOutput, lastPage bool) bool {
+			names = append(names, page.StreamNames...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	var allStreams []*kinesis.StreamDescriptionSummary
+	for _, name := range names {
+		err := withRetry(func() error {
+			result, err := kc.DescribeStreamSummary(&kinesis.DescribeStreamSummaryInput{StreamName: name})
+			if err == nil {
+				allStreams = append(allStreams, result.StreamDescriptionSummary)
+			}
+			return err
+		})
+		if err != nil {
+			return allStreams, err
+		}
+	}
+	return allStreams, nil
+}
+
+// GetAllDeliveryStreams returns a complete list of Kinesis Data Firehose delivery streams for a given session
+func GetAllDeliveryStreams(sess *session.Session) ([]*firehose.DeliveryStreamDescription, error) {
+	fc := firehose.New(sess)
+	var names []*string
+	input := firehose.ListDeliveryStreamsInput{}
+	for {
+		var result *firehose.ListDeliveryStreamsOutput
+		err := withRetry(func() (err error) {
+			result, err = fc.ListDeliveryStreams(&input)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, result.DeliveryStreamNames...)
+		if !aws.BoolValue(result.HasMoreDeliveryStreams) || len(result.DeliveryStreamNames) == 0 {
+			break
+		}
+		// Firehose paginates on the name of the last stream returned
+		input.ExclusiveStartDeliveryStreamName = names[len(names)-1]
+	}
+	var allStreams []*firehose.DeliveryStreamDescription
+	for _, name := range names {
+		err := withRetry(func() error {
+			result, err := fc.DescribeDeliveryStream(&firehose.DescribeDeliveryStreamInput{DeliveryStreamName: name})
+			if err == nil {
+				allStreams = append(allStreams, result.DeliveryStreamDescription)
+			}
+			return err
+		})
+		if err != nil {
+			return allStreams, err
+		}
+	}
+	return allStreams, nil
+}
+
+// GetAllEventBuses returns a complete list of EventBridge event buses with their rules and targets for a given session
+func GetAllEventBuses(sess *session.Session) ([]*EventBus, error) {
+	ebc := eventbridge.New(sess)
+	var allBuses []*EventBus
+	input := eventbridge.ListEventBusesInput{}
+	for {
+		var result *eventbridge.ListEventBusesOutput
+		err := withRetry(func() (err error) {
+			result, err = ebc.ListEventBuses(&input)
+			return err
+		})
+		if err != nil {
+			return allBuses, err
+		}
+		for _, b := range result.EventBuses {
+			bus := &EventBus{Bus: b}
+			if bus.Rules, err = getEventRules(ebc, b.Name); err != nil {
+				return allBuses, err
+			}
+			allBuses = append(allBuses, bus)
+		}
+		if result.NextToken == nil {
+			break
+		}
+		input.SetNextToken(*result.NextToken)
+	}
+	return allBuses, nil
+}
+
+// getEventRules returns every rule of the given event bus along with its targets
+func getEventRules(ebc *eventbridge.EventBridge, bus *string) ([]*EventRule, error) {
+	var rules []*EventRule
+	input := eventbridge.ListRulesInput{EventBusName: bus}
+	for {
+		var result *eventbridge.ListRulesOutput
+		err := withRetry(func() (err error) {
+			result, err = ebc.ListRules(&input)
+			return err
+		})
+		if err != nil {
+			return rules, err
+		}
+		for _, r := range result.Rules {
+			rule := &EventRule{Rule: r}
+			targetsInput := eventbridge.ListTargetsByRuleInput{EventBusName: bus, Rule: r.Name}
+			for {
+				var targets *eventbridge.ListTargetsByRuleOutput
+				err := withRetry(func() (err error) {
+					targets, err = ebc.ListTargetsByRule(&targetsInput)
+					return err
+				})
+				if err != nil {
+					return rules, err
+				}
+				rule.Targets = append(rule.Targets, targets.Targets...)
+				if targets.NextToken == nil {
+					break
+				}
+				targetsInput.SetNextToken(*targets.NextToken)
+			}
+			rules = append(rules, rule)
+		}
+		if result.NextToken == nil {
+			break
+		}
+		input.SetNextToken(*result.NextToken)
+	}
+	return rules, nil
+}
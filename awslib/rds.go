@@ -16,6 +16,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/rds"
@@ -61,3 +62,213 @@ func GetAllDBInstances(sess *session.Session) ([]*rds.DBInstance, error) {
 	}
 	return allInstances, nil
 }
+
+// DBClusterMembership records the DB cluster an instance belongs to and its role in it
+type DBClusterMembership struct {
+	DBClusterIdentifier string
+	IsClusterWriter     bool
+}
+
+// GetAllDBClusters returns a complete list of DBClusters (Aurora and Multi-AZ) for a given session
+func GetAllDBClusters(sess *session.Session) ([]*rds.DBCluster, error) {
+	rdsc := rds.New(sess)
+	allClustersDone := false
+	var allClusters []*rds.DBCluster
+	input := rds.DescribeDBClustersInput{}
+	b := newBackoff()
+	for !allClustersDone {
+		result, err := rdsc.DescribeDBClusters(&input)
+		if err != nil {
+			// Retry with backoff incase Rate has been exceeded
+			if isThrottled(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return allClusters, err
+		}
+		b.Reset()
+		allClusters = append(allClusters, result.DBClusters...)
+		if result.Marker == nil {
+			allClustersDone = true
+			continue
+		}
+		input.SetMarker(*result.Marker)
+	}
+	return allClusters, nil
+}
+
+// GetAllGlobalClusters returns a complete list of Aurora global clusters visible from a given session
+func GetAllGlobalClusters(sess *session.Session) ([]*rds.GlobalCluster, error) {
+	rdsc := rds.New(sess)
+	allClustersDone := false
+	var allClusters []*rds.GlobalCluster
+	input := rds.DescribeGlobalClustersInput{}
+	b := newBackoff()
+	for !allClustersDone {
+		result, err := rdsc.DescribeGlobalClusters(&input)
+		if err != nil {
+			if isThrottled(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return allClusters, err
+		}
+		b.Reset()
+		allClusters = append(allClusters, result.GlobalClusters...)
+		if result.Marker == nil {
+			allClustersDone = true
+			continue
+		}
+		input.SetMarker(*result.Marker)
+	}
+	return allClusters, nil
+}
+
+// GetAllDBSnapshots returns a complete list of manual and automated DB snapshots for a given session
+func GetAllDBSnapshots(sess *session.Session) ([]*rds.DBSnapshot, error) {
+	rdsc := rds.New(sess)
+	allSnapshotsDone := false
+	var allSnapshots []*rds.DBSnapshot
+	input := rds.DescribeDBSnapshotsInput{}
+	b := newBackoff()
+	for !allSnapshotsDone {
+		result, err := rdsc.DescribeDBSnapshots(&input)
+		if err != nil {
+			if isThrottled(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return allSnapshots, err
+		}
+		b.Reset()
+		allSnapshots = append(allSnapshots, result.DBSnapshots...)
+		if result.Marker == nil {
+			allSnapshotsDone = true
+			continue
+		}
+		input.SetMarker(*result.Marker)
+	}
+	return allSnapshots, nil
+}
+
+// GetAllDBClusterSnapshots returns a complete list of manual and automated DB cluster snapshots for a given session
+func GetAllDBClusterSnapshots(sess *session.Session) ([]*rds.DBClusterSnapshot, error) {
+	rdsc := rds.New(sess)
+	allSnapshotsDone := false
+	var allSnapshots []*rds.DBClusterSnapshot
+	input := rds.DescribeDBClusterSnapshotsInput{}
+	b := newBackoff()
+	for !allSnapshotsDone {
+		result, err := rdsc.DescribeDBClusterSnapshots(&input)
+		if err != nil {
+			if isThrottled(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return allSnapshots, err
+		}
+		b.Reset()
+		allSnapshots = append(allSnapshots, result.DBClusterSnapshots...)
+		if result.Marker == nil {
+			allSnapshotsDone = true
+			continue
+		}
+		input.SetMarker(*result.Marker)
+	}
+	return allSnapshots, nil
+}
+
+// GetAllDBParameterGroups returns a complete list of DB parameter groups for a given session
+func GetAllDBParameterGroups(sess *session.Session) ([]*rds.DBParameterGroup, error) {
+	rdsc := rds.New(sess)
+	allGroupsDone := false
+	var allGroups []*rds.DBParameterGroup
+	input := rds.DescribeDBParameterGroupsInput{}
+	b := newBackoff()
+	for !allGroupsDone {
+		result, err := rdsc.DescribeDBParameterGroups(&input)
+		if err != nil {
+			if isThrottled(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return allGroups, err
+		}
+		b.Reset()
+		allGroups = append(allGroups, result.DBParameterGroups...)
+		if result.Marker == nil {
+			allGroupsDone = true
+			continue
+		}
+		input.SetMarker(*result.Marker)
+	}
+	return allGroups, nil
+}
+
+// GetAllDBClusterParameterGroups returns a complete list of DB cluster parameter groups for a given session
+func GetAllDBClusterParameterGroups(sess *session.Session) ([]*rds.DBClusterParameterGroup, error) {
+	rdsc := rds.New(sess)
+	allGroupsDone := false
+	var allGroups []*rds.DBClusterParameterGroup
+	input := rds.DescribeDBClusterParameterGroupsInput{}
+	b := newBackoff()
+	for !allGroupsDone {
+		result, err := rdsc.DescribeDBClusterParameterGroups(&input)
+		if err != nil {
+			if isThrottled(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return allGroups, err
+		}
+		b.Reset()
+		allGroups = append(allGroups, result.DBClusterParameterGroups...)
+		if result.Marker == nil {
+			allGroupsDone = true
+			continue
+		}
+		input.SetMarker(*result.Marker)
+	}
+	return allGroups, nil
+}
+
+// GetAllDBSubnetGroups returns a complete list of DB subnet groups for a given session
+func GetAllDBSubnetGroups(sess *session.Session) ([]*rds.DBSubnetGroup, error) {
+	rdsc := rds.New(sess)
+	allGroupsDone := false
+	var allGroups []*rds.DBSubnetGroup
+	input := rds.DescribeDBSubnetGroupsInput{}
+	b := newBackoff()
+	for !allGroupsDone {
+		result, err := rdsc.DescribeDBSubnetGroups(&input)
+		if err != nil {
+			if isThrottled(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return allGroups, err
+		}
+		b.Reset()
+		allGroups = append(allGroups, result.DBSubnetGroups...)
+		if result.Marker == nil {
+			allGroupsDone = true
+			continue
+		}
+		input.SetMarker(*result.Marker)
+	}
+	return allGroups, nil
+}
+
+// GetDBClusterMembership maps every DB instance identifier that is part of one of the given clusters to its membership
+func GetDBClusterMembership(clusters []*rds.DBCluster) map[string]*DBClusterMembership {
+	membership := make(map[string]*DBClusterMembership)
+	for _, c := range clusters {
+		for _, m := range c.DBClusterMembers {
+			membership[aws.StringValue(m.DBInstanceIdentifier)] = &DBClusterMembership{
+				DBClusterIdentifier: aws.StringValue(c.DBClusterIdentifier),
+				IsClusterWriter:     aws.BoolValue(m.IsClusterWriter),
+			}
+		}
+	}
+	return membership
+}
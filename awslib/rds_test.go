@@ -0,0 +1,67 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// TestGetAllDBClusters checks if the lib is able to gather all DB clusters and their snapshots.
+// This test REQUIRES a working AWS account and credentials to read from RDS
+// This test does NOT fail unless there is an error in the gathering, the gathering itself is not validated.
+func TestGetAllDBClusters(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	sessions, err := BuildSessions(GetAllRegions())
+	if err != nil {
+		t.Errorf("Unable to get sessions: %v", err)
+	}
+	for r, sess := range sessions {
+		clusters, err := GetAllDBClusters(sess)
+		if err != nil {
+			t.Errorf("Failed to get DB Clusters for region: %s because %v", r, err)
+		}
+		snapshots, err := GetAllDBClusterSnapshots(sess)
+		if err != nil {
+			t.Errorf("Failed to get DB Cluster Snapshots for region: %s because %v", r, err)
+		}
+		t.Logf("Found %d clusters and %d cluster snapshots in %s", len(clusters), len(snapshots), r)
+	}
+}
+
+// TestGetDBClusterMembership checks that every cluster member is mapped back to its cluster
+func TestGetDBClusterMembership(t *testing.T) {
+	clusters := []*rds.DBCluster{{
+		DBClusterIdentifier: aws.String("aurora-1"),
+		DBClusterMembers: []*rds.DBClusterMember{
+			{DBInstanceIdentifier: aws.String("aurora-1-a"), IsClusterWriter: aws.Bool(true)},
+			{DBInstanceIdentifier: aws.String("aurora-1-b"), IsClusterWriter: aws.Bool(false)},
+		},
+	}, {
+		DBClusterIdentifier: aws.String("aurora-2"),
+	}}
+	membership := GetDBClusterMembership(clusters)
+	if len(membership) != 2 {
+		t.Fatalf("Expected 2 members, got %d", len(membership))
+	}
+	if m := membership["aurora-1-a"]; m.DBClusterIdentifier != "aurora-1" || !m.IsClusterWriter {
+		t.Errorf("aurora-1-a should be the writer of aurora-1: %+v", m)
+	}
+	if m := membership["aurora-1-b"]; m.DBClusterIdentifier != "aurora-1" || m.IsClusterWriter {
+		t.Errorf("aurora-1-b should be a reader of aurora-1: %+v", m)
+	}
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+// Record resolution statuses
+const (
+	// RecordResolved means the record points to a resource present in the inventory
+	RecordResolved = "resolved"
+	// RecordDangling means the record points into an AWS namespace or IP range the inventory covers,
+	// but to no resource present in it. These should be reviewed for subdomain takeover
+	RecordDangling = "dangling"
+	// RecordExternal means the record points to something the inventory cannot account for
+	RecordExternal = "external"
+)
+
+// danglingSuffixes are the AWS managed DNS namespaces whose resources are inventoried,
+// a record pointing there without a matching resource is considered dangling
+var danglingSuffixes = []string{
+	".elb.amazonaws.com",
+	".rds.amazonaws.com",
+	".compute.amazonaws.com",
+	".compute-1.amazonaws.com",
+	".compute.amazonaws.com.cn",
+	".elb.amazonaws.com.cn",
+	".rds.amazonaws.com.cn",
+}
+
+// IPRangesURL is where AWS publishes the IP ranges it owns
+const IPRangesURL = "https://ip-ranges.amazonaws.com/ip-ranges.json"
+
+// HostedZone holds a Route 53 hosted zone along with its associated VPCs (private zones only) and record sets
+type HostedZone struct {
+	Zone       *route53.HostedZone
+	VPCs       []*route53.VPC
+	RecordSets []*route53.ResourceRecordSet
+}
+
+// RecordLink is the resolution of a single value of a record set to inventoried resources.
+// A private IP can be owned by resources of several VPCs, Resources holds all of them unless
+// some belong to the VPCs of a private zone
+type RecordLink struct {
+	ZoneID    string
+	Name      string
+	Type      string
+	Target    string
+	Status    string
+	Resources []*ResourceRef
+}
+
+// GetAllHostedZones returns every hosted zone of the account along with its record sets.
+// Route 53 is a global service, any session can be used
+func GetAllHostedZones(sess *session.Session) ([]*HostedZone, error) {
+	r53c := route53.New(sess)
+	var zones []*route53.HostedZone
+	err := withRetry(func() error {
+		zones = nil
+		return r53c.ListHostedZonesPages(&route53.ListHostedZonesInput{}, func(page *route53.ListHostedZonesOutput, lastPage bool) bool {
+			zones = append(zones, page.HostedZones...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var allZones []*HostedZone
+	for _, z := range zones {
+		zone := &HostedZone{Zone: z}
+		// VPC associations are only returned when getting the zone itself
+		if z.Config != nil && aws.BoolValue(z.Config.PrivateZone) {
+			err := withRetry(func() error {
+				result, err := r53c.GetHostedZone(&route53.GetHostedZoneInput{Id: z.Id})
+				if err == nil {
+					zone.VPCs = result.VPCs
+				}
+				return err
+			})
+			if err != nil {
+				return allZones, err
+			}
+		}
+		err := withRetry(func() error {
+			zone.RecordSets = nil
+			return r53c.ListResourceRecordSetsPages(&route53.ListResourceRecordSetsInput{HostedZoneId: z.Id}, func(page *route53.ListResourceRecordSetsOutput, lastPage bool) bool {
+				zone.RecordSets = append(zone.RecordSets, page.ResourceRecordSets...)
+				return true
+			})
+		})
+		if err != nil {
+			return allZones, err
+		}
+		allZones = append(allZones, zone)
+	}
+	return allZones, nil
+}
+
+// GetAWSIPRanges downloads the IP ranges owned by AWS from IPRangesURL
+func GetAWSIPRanges() ([]*net.IPNet, error) {
+	client := &http.Client{Timeout: time.Minute}
+	resp, err := client.Get(IPRangesURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Failed to get %s: %s", IPRangesURL, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAWSIPRanges(data)
+}
+
+// ParseAWSIPRanges returns the IPv4 and IPv6 prefixes of an ip-ranges.json document.
+// Only the AMAZON service is kept, its prefixes cover those of every other service
+func ParseAWSIPRanges(data []byte) ([]*net.IPNet, error) {
+	var doc struct {
+		Prefixes []struct {
+			IPPrefix string `json:"ip_prefix"`
+			Service  string `json:"service"`
+		} `json:"prefixes"`
+		IPv6Prefixes []struct {
+			IPv6Prefix string `json:"ipv6_prefix"`
+			Service    string `json:"service"`
+		} `json:"ipv6_prefixes"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("Invalid AWS IP ranges: %v", err)
+	}
+	var prefixes []string
+	for _, p := range doc.Prefixes {
+		if p.Service == "AMAZON" {
+			prefixes = append(prefixes, p.IPPrefix)
+		}
+	}
+	for _, p := range doc.IPv6Prefixes {
+		if p.Service == "AMAZON" {
+			prefixes = append(prefixes, p.IPv6Prefix)
+		}
+	}
+	var ranges []*net.IPNet
+	for _, p := range prefixes {
+		_, n, err := net.ParseCIDR(p)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid AWS IP range %s: %v", p, err)
+		}
+		ranges = append(ranges, n)
+	}
+	return ranges, nil
+}
+
+// ResolveRecords links the A, AAAA and CNAME records (including aliases) of the given zones to
+// the resources of the index, flagging those which are dangling. IPs within awsRanges, the IP ranges
+// owned by AWS, are dangling when no resource holds them, e.g. a released Elastic IP. With no awsRanges
+// only records pointing to AWS managed names can be dangling
+func ResolveRecords(zones []*HostedZone, index ResourceIndex, awsRanges []*net.IPNet) []*RecordLink {
+	var links []*RecordLink
+	for _, z := range zones {
+		for _, rs := range z.RecordSets {
+			recordType := aws.StringValue(rs.Type)
+			if recordType != route53.RRTypeA && recordType != route53.RRTypeAaaa && recordType != route53.RRTypeCname {
+				continue
+			}
+			var targets []string
+			if rs.AliasTarget != nil {
+				targets = append(targets, aws.StringValue(rs.AliasTarget.DNSName))
+			}
+			for _, rr := range rs.ResourceRecords {
+				targets = append(targets, aws.StringValue(rr.Value))
+			}
+			for _, target := range targets {
+				link := &RecordLink{
+					ZoneID: aws.StringValue(z.Zone.Id),
+					Name:   aws.StringValue(rs.Name),
+					Type:   recordType,
+					Target: target,
+				}
+				if refs := index.Lookup(target); len(refs) > 0 {
+					link.Status = RecordResolved
+					link.Resources = zoneResources(z, refs)
+				} else if isDanglingTarget(target, awsRanges) {
+					link.Status = RecordDangling
+				} else {
+					link.Status = RecordExternal
+				}
+				links = append(links, link)
+			}
+		}
+	}
+	return links
+}
+
+// zoneResources returns the resources of refs within the VPCs associated with a private zone,
+// all of them for public zones or when none is within those VPCs
+func zoneResources(z *HostedZone, refs []*ResourceRef) []*ResourceRef {
+	var inZone []*ResourceRef
+	for _, ref := range refs {
+		for _, vpc := range z.VPCs {
+			if ref.VPC == aws.StringValue(vpc.VPCId) && ref.Region == aws.StringValue(vpc.VPCRegion) {
+				inZone = append(inZone, ref)
+				break
+			}
+		}
+	}
+	if len(inZone) == 0 {
+		return refs
+	}
+	return inZone
+}
+
+// isDanglingTarget reports whether target belongs to an AWS managed namespace or IP range covered by the inventory
+func isDanglingTarget(target string, awsRanges []*net.IPNet) bool {
+	target = normalizeAddress(target)
+	if ip := net.ParseIP(target); ip != nil {
+		for _, r := range awsRanges {
+			if r.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, suffix := range danglingSuffixes {
+		if strings.HasSuffix(target, suffix) {
+			return true
+		}
+	}
+	return false
+}
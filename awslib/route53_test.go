@@ -0,0 +1,131 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"net"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+func testRecordSet(name, recordType string, values ...string) *route53.ResourceRecordSet {
+	rs := &route53.ResourceRecordSet{Name: aws.String(name), Type: aws.String(recordType)}
+	for _, v := range values {
+		rs.ResourceRecords = append(rs.ResourceRecords, &route53.ResourceRecord{Value: aws.String(v)})
+	}
+	return rs
+}
+
+// TestResolveRecords checks that records are linked to indexed resources and dangling targets are flagged
+func TestResolveRecords(t *testing.T) {
+	index := NewResourceIndex()
+	index.AddInstances("us-east-1", []*ec2.Instance{{InstanceId: aws.String("i-1"), PublicIpAddress: aws.String("203.0.113.10")}})
+	index.AddDBInstances("us-east-1", []*rds.DBInstance{{
+		DBInstanceIdentifier: aws.String("db-1"),
+		Endpoint:             &rds.Endpoint{Address: aws.String("db-1.abc.us-east-1.rds.amazonaws.com")},
+	}})
+	index.AddLoadBalancers("us-east-1", []*elbv2.LoadBalancer{{
+		LoadBalancerArn: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/1"),
+		DNSName:         aws.String("web-1.us-east-1.elb.amazonaws.com"),
+	}})
+
+	alias := testRecordSet("www.example.com.", "A")
+	alias.AliasTarget = &route53.AliasTarget{DNSName: aws.String("dualstack.web-1.us-east-1.elb.amazonaws.com.")}
+	zones := []*HostedZone{{
+		Zone: &route53.HostedZone{Id: aws.String("/hostedzone/Z1"), Name: aws.String("example.com.")},
+		RecordSets: []*route53.ResourceRecordSet{
+			testRecordSet("example.com.", "NS", "ns-1.awsdns-01.org."),
+			testRecordSet("app.example.com.", "A", "203.0.113.10", "198.51.100.1"),
+			testRecordSet("released.example.com.", "A", "203.0.113.99"),
+			alias,
+			testRecordSet("db.example.com.", "CNAME", "DB-1.abc.us-east-1.rds.amazonaws.com"),
+			testRecordSet("old.example.com.", "CNAME", "old-2.us-west-2.elb.amazonaws.com"),
+		},
+	}}
+
+	_, awsRange, _ := net.ParseCIDR("203.0.113.0/24")
+	links := ResolveRecords(zones, index, []*net.IPNet{awsRange})
+	want := map[string]string{
+		"203.0.113.10": RecordResolved,
+		"203.0.113.99": RecordDangling,
+		"198.51.100.1": RecordExternal,
+		"dualstack.web-1.us-east-1.elb.amazonaws.com.": RecordResolved,
+		"DB-1.abc.us-east-1.rds.amazonaws.com":         RecordResolved,
+		"old-2.us-west-2.elb.amazonaws.com":            RecordDangling,
+	}
+	if len(links) != len(want) {
+		t.Fatalf("Expected %d links, got %d", len(want), len(links))
+	}
+	for _, l := range links {
+		if l.Status != want[l.Target] {
+			t.Errorf("%s %s -> %s\tWant:%s\tHave:%s", l.Type, l.Name, l.Target, want[l.Target], l.Status)
+		}
+		if l.Status == RecordResolved && len(l.Resources) == 0 {
+			t.Errorf("Resolved record %s has no resource", l.Name)
+		}
+	}
+
+	// Without the AWS ranges unknown IPs can't be told apart from external ones, only names are flagged
+	for _, l := range ResolveRecords(zones, index, nil) {
+		if l.Target == "203.0.113.99" && l.Status != RecordExternal {
+			t.Errorf("Expected %s to be external without AWS ranges, got %s", l.Target, l.Status)
+		}
+		if l.Target == "old-2.us-west-2.elb.amazonaws.com" && l.Status != RecordDangling {
+			t.Errorf("Expected %s to be dangling without AWS ranges, got %s", l.Target, l.Status)
+		}
+	}
+}
+
+// TestResolvePrivateRecords checks that records of a private zone resolve to the owners within its VPCs
+func TestResolvePrivateRecords(t *testing.T) {
+	index := NewResourceIndex()
+	index.AddInstances("us-east-1", []*ec2.Instance{{InstanceId: aws.String("i-1"), VpcId: aws.String("vpc-1"), PrivateIpAddress: aws.String("10.0.0.10")}})
+	index.AddInstances("us-west-2", []*ec2.Instance{{InstanceId: aws.String("i-2"), VpcId: aws.String("vpc-2"), PrivateIpAddress: aws.String("10.0.0.10")}})
+	record := testRecordSet("app.internal.example.com.", "A", "10.0.0.10")
+	zone := &HostedZone{
+		Zone:       &route53.HostedZone{Id: aws.String("/hostedzone/Z2"), Name: aws.String("internal.example.com.")},
+		RecordSets: []*route53.ResourceRecordSet{record},
+	}
+
+	links := ResolveRecords([]*HostedZone{zone}, index, nil)
+	if len(links) != 1 || len(links[0].Resources) != 2 {
+		t.Fatalf("Public zone record should resolve to both owners, got %+v", links)
+	}
+	zone.VPCs = []*route53.VPC{{VPCId: aws.String("vpc-2"), VPCRegion: aws.String("us-west-2")}}
+	links = ResolveRecords([]*HostedZone{zone}, index, nil)
+	if len(links) != 1 || len(links[0].Resources) != 1 || links[0].Resources[0].ID != "i-2" {
+		t.Errorf("Private zone record should only resolve to i-2, got %+v", links)
+	}
+}
+
+// TestParseAWSIPRanges checks that only the prefixes of the AMAZON service are kept, in both families
+func TestParseAWSIPRanges(t *testing.T) {
+	ranges, err := ParseAWSIPRanges([]byte(`{"syncToken":"1","prefixes":[
+		{"ip_prefix":"3.5.140.0/22","region":"ap-northeast-2","service":"AMAZON"},
+		{"ip_prefix":"3.5.140.0/22","region":"ap-northeast-2","service":"S3"}],
+		"ipv6_prefixes":[{"ipv6_prefix":"2600:1f14::/35","region":"us-west-2","service":"AMAZON"}]}`))
+	if err != nil {
+		t.Fatalf("Failed to parse IP ranges: %v", err)
+	}
+	if len(ranges) != 2 || ranges[0].String() != "3.5.140.0/22" || ranges[1].String() != "2600:1f14::/35" {
+		t.Errorf("Unexpected ranges %v", ranges)
+	}
+	if _, err := ParseAWSIPRanges([]byte(`{"prefixes":[{"ip_prefix":"3.5.140.0","service":"AMAZON"}]}`)); err == nil {
+		t.Errorf("Invalid prefixes should be rejected")
+	}
+}
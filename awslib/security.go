@@ -0,0 +1,180 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+// KMSKey holds a KMS key along with its aliases, rotation status and default key policy.
+// KeyMetadata.KeyManager tells customer managed keys apart from AWS managed ones.
+// RotationEnabled is only set for enabled or disabled customer managed symmetric keys
+type KMSKey struct {
+	Metadata        *kms.KeyMetadata
+	Aliases         []string
+	RotationEnabled *bool
+	Policy          *string
+}
+
+// GetAllKMSKeys returns a complete list of KMS keys with their aliases, rotation status and policy for a given session
+func GetAllKMSKeys(sess *session.Session) ([]*KMSKey, error) {
+	kmsc := kms.New(sess)
+	var keys []*kms.KeyListEntry
+	err := withRetry(func() error {
+		keys = nil
+		return kmsc.ListKeysPages(&kms.ListKeysInput{}, func(page *kms.ListKeysOutput, lastPage bool) bool {
+			keys = append(keys, page.Keys...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	aliases := make(map[string][]string)
+	err = withRetry(func() error {
+		aliases = make(map[string][]string)
+		return kmsc.ListAliasesPages(&kms.ListAliasesInput{}, func(page *kms.ListAliasesOutput, lastPage bool) bool {
+			for _, a := range page.Aliases {
+				// Aliases reserved for AWS services are listed even when no key backs them yet
+				if a.TargetKeyId != nil {
+					aliases[*a.TargetKeyId] = append(aliases[*a.TargetKeyId], aws.StringValue(a.AliasName))
+				}
+			}
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var allKeys []*KMSKey
+	for _, k := range keys {
+		key := &KMSKey{Aliases: aliases[aws.StringValue(k.KeyId)]}
+		err := withRetry(func() error {
+			result, err := kmsc.DescribeKey(&kms.DescribeKeyInput{KeyId: k.KeyId})
+			if err == nil {
+				key.Metadata = result.KeyMetadata
+			}
+			return err
+		})
+		if err != nil {
+			return allKeys, err
+		}
+		err = withRetry(func() error {
+			result, err := kmsc.GetKeyPolicy(&kms.GetKeyPolicyInput{KeyId: k.KeyId, PolicyName: aws.String("default")})
+			if err == nil {
+				key.Policy = result.Policy
+			}
+			return err
+		})
+		if err != nil {
+			return allKeys, err
+		}
+		if hasKeyRotation(key.Metadata) {
+			err = withRetry(func() error {
+				result, err := kmsc.GetKeyRotationStatus(&kms.GetKeyRotationStatusInput{KeyId: k.KeyId})
+				if err == nil {
+					key.RotationEnabled = result.KeyRotationEnabled
+				}
+				return err
+			})
+			if err != nil {
+				return allKeys, err
+			}
+		}
+		allKeys = append(allKeys, key)
+	}
+	return allKeys, nil
+}
+
+// hasKeyRotation reports whether the rotation status of a key can be queried.
+// Rotation only applies to usable customer managed symmetric keys
+func hasKeyRotation(m *kms.KeyMetadata) bool {
+	if m == nil || aws.StringValue(m.KeyManager) != kms.KeyManagerTypeCustomer {
+		return false
+	}
+	if aws.StringValue(m.KeySpec) != kms.KeySpecSymmetricDefault || aws.StringValue(m.Origin) != kms.OriginTypeAwsKms {
+		return false
+	}
+	state := aws.StringValue(m.KeyState)
+	return state == kms.KeyStateEnabled || state == kms.KeyStateDisabled
+}
+
+// GetAllSecrets returns the metadata of every Secrets Manager secret for a given session.
+// Secret values are never retrieved
+func GetAllSecrets(sess *session.Session) ([]*secretsmanager.SecretListEntry, error) {
+	smc := secretsmanager.New(sess)
+	var allSecrets []*secretsmanager.SecretListEntry
+	err := withRetry(func() error {
+		allSecrets = nil
+		return smc.ListSecretsPages(&secretsmanager.ListSecretsInput{}, func(page *secretsmanager.ListSecretsOutput, lastPage bool) bool {
+			allSecrets = append(allSecrets, page.SecretList...)
+			return true
+		})
+	})
+	return allSecrets, err
+}
+
+// GetAllCertificates returns a complete list of ACM certificates, of every key type, for a given session
+func GetAllCertificates(sess *session.Session) ([]*acm.CertificateDetail, error) {
+	acmc := acm.New(sess)
+	var summaries []*acm.CertificateSummary
+	// Without the key type filter only RSA certificates are listed
+	input := acm.ListCertificatesInput{Includes: &acm.Filters{KeyTypes: aws.StringSlice(acm.KeyAlgorithm_Values())}}
+	err := withRetry(func() error {
+		summaries = nil
+		return acmc.ListCertificatesPages(&input, func(page *acm.ListCertificatesOutput, lastPage bool) bool {
+			summaries = append(summaries, page.CertificateSummaryList...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	var allCertificates []*acm.CertificateDetail
+	for _, s := range summaries {
+		err := withRetry(func() error {
+			result, err := acmc.DescribeCertificate(&acm.DescribeCertificateInput{CertificateArn: s.CertificateArn})
+			if err == nil {
+				allCertificates = append(allCertificates, result.Certificate)
+			}
+			return err
+		})
+		if err != nil {
+			return allCertificates, err
+		}
+	}
+	return allCertificates, nil
+}
+
+// GetExpiringCertificates returns the certificates which expire before now+within, including already expired ones
+func GetExpiringCertificates(certificates []*acm.CertificateDetail, now time.Time, within time.Duration) []*acm.CertificateDetail {
+	var expiring []*acm.CertificateDetail
+	deadline := now.Add(within)
+	for _, c := range certificates {
+		// Certificates pending validation have no expiry yet
+		if c.NotAfter == nil {
+			continue
+		}
+		if c.NotAfter.Before(deadline) {
+			expiring = append(expiring, c)
+		}
+	}
+	return expiring
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+// TestGetExpiringCertificates checks that expired and soon to expire certificates are reported
+func TestGetExpiringCertificates(t *testing.T) {
+	now := time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC)
+	certificates := []*acm.CertificateDetail{
+		{DomainName: aws.String("expired.example.com"), NotAfter: aws.Time(now.AddDate(0, 0, -1))},
+		{DomainName: aws.String("soon.example.com"), NotAfter: aws.Time(now.AddDate(0, 0, 10))},
+		{DomainName: aws.String("later.example.com"), NotAfter: aws.Time(now.AddDate(0, 0, 90))},
+		{DomainName: aws.String("pending.example.com")},
+	}
+	expiring := GetExpiringCertificates(certificates, now, 30*24*time.Hour)
+	if len(expiring) != 2 {
+		t.Fatalf("Expected 2 expiring certificates, got %d", len(expiring))
+	}
+	for _, c := range expiring {
+		if d := *c.DomainName; d != "expired.example.com" && d != "soon.example.com" {
+			t.Errorf("Unexpected expiring certificate %s", d)
+		}
+	}
+}
+
+// TestHasKeyRotation checks that rotation status is only queried for usable customer managed symmetric keys
+func TestHasKeyRotation(t *testing.T) {
+	for _, testCase := range []struct {
+		name     string
+		metadata *kms.KeyMetadata
+		rotation bool
+	}{
+		{"customer", &kms.KeyMetadata{KeyManager: aws.String("CUSTOMER"), KeySpec: aws.String("SYMMETRIC_DEFAULT"), Origin: aws.String("AWS_KMS"), KeyState: aws.String("Enabled")}, true},
+		{"aws-managed", &kms.KeyMetadata{KeyManager: aws.String("AWS"), KeySpec: aws.String("SYMMETRIC_DEFAULT"), Origin: aws.String("AWS_KMS"), KeyState: aws.String("Enabled")}, false},
+		{"asymmetric", &kms.KeyMetadata{KeyManager: aws.String("CUSTOMER"), KeySpec: aws.String("RSA_2048"), Origin: aws.String("AWS_KMS"), KeyState: aws.String("Enabled")}, false},
+		{"pending-deletion", &kms.KeyMetadata{KeyManager: aws.String("CUSTOMER"), KeySpec: aws.String("SYMMETRIC_DEFAULT"), Origin: aws.String("AWS_KMS"), KeyState: aws.String("PendingDeletion")}, false},
+		{"imported", &kms.KeyMetadata{KeyManager: aws.String("CUSTOMER"), KeySpec: aws.String("SYMMETRIC_DEFAULT"), Origin: aws.String("EXTERNAL"), KeyState: aws.String("Enabled")}, false},
+	} {
+		if have := hasKeyRotation(testCase.metadata); have != testCase.rotation {
+			t.Errorf("%s\tWant:%t\tHave:%t", testCase.name, testCase.rotation, have)
+		}
+	}
+}
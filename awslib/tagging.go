@@ -0,0 +1,85 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+)
+
+// TaggedResource holds a resource discovered through the Resource Groups Tagging API, its ARN
+// broken down into service, resource type and ID, and its tags
+type TaggedResource struct {
+	ARN          string
+	Service      string
+	ResourceType string
+	ResourceID   string
+	AccountID    string
+	Tags         map[string]string
+}
+
+// GetAllTaggedResources returns every resource known to the Resource Groups Tagging API for a given session.
+// Only resources which are or once were tagged are returned
+func GetAllTaggedResources(sess *session.Session) ([]*TaggedResource, error) {
+	tc := resourcegroupstaggingapi.New(sess)
+	var mappings []*resourcegroupstaggingapi.ResourceTagMapping
+	err := withRetry(func() error {
+		mappings = nil
+		return tc.GetResourcesPages(&resourcegroupstaggingapi.GetResourcesInput{}, func(page *resourcegroupstaggingapi.GetResourcesOutput, lastPage bool) bool {
+			mappings = append(mappings, page.ResourceTagMappingList...)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	var allResources []*TaggedResource
+	for _, m := range mappings {
+		resource, err := ParseResourceARN(aws.StringValue(m.ResourceARN))
+		if err != nil {
+			return allResources, err
+		}
+		resource.Tags = make(map[string]string)
+		for _, t := range m.Tags {
+			resource.Tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+		}
+		allResources = append(allResources, resource)
+	}
+	return allResources, nil
+}
+
+// ParseResourceARN breaks an ARN down into a TaggedResource with no tags.
+// The resource part is split on its first "/" or ":", resources without a type only get an ID
+func ParseResourceARN(resourceARN string) (*TaggedResource, error) {
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return nil, err
+	}
+	resource := &TaggedResource{
+		ARN:       resourceARN,
+		Service:   parsed.Service,
+		AccountID: parsed.AccountID,
+	}
+	// API Gateway resources are paths such as /restapis/id
+	path := strings.TrimPrefix(parsed.Resource, "/")
+	if i := strings.IndexAny(path, "/:"); i >= 0 {
+		resource.ResourceType, resource.ResourceID = path[:i], path[i+1:]
+	} else {
+		resource.ResourceID = path
+	}
+	return resource, nil
+}
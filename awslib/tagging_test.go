@@ -0,0 +1,51 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"testing"
+)
+
+// TestParseResourceARN checks that the common ARN resource formats are broken down into type and ID
+func TestParseResourceARN(t *testing.T) {
+	for _, testCase := range []struct {
+		arn          string
+		service      string
+		resourceType string
+		resourceID   string
+	}{
+		{"arn:aws:ec2:us-east-1:123456789012:instance/i-0abc", "ec2", "instance", "i-0abc"},
+		{"arn:aws:rds:us-east-1:123456789012:db:mydb", "rds", "db", "mydb"},
+		{"arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/50dc6c495c0c9188", "elasticloadbalancing", "loadbalancer", "app/web/50dc6c495c0c9188"},
+		{"arn:aws:logs:us-east-1:123456789012:log-group:/aws/lambda/fn", "logs", "log-group", "/aws/lambda/fn"},
+		{"arn:aws:lambda:us-east-1:123456789012:function:fn", "lambda", "function", "fn"},
+		{"arn:aws:sqs:us-east-1:123456789012:queue", "sqs", "", "queue"},
+		{"arn:aws:s3:::bucket", "s3", "", "bucket"},
+		{"arn:aws:apigateway:us-east-1::/restapis/a1b2c3", "apigateway", "restapis", "a1b2c3"},
+		{"arn:aws-cn:ec2:cn-north-1:123456789012:volume/vol-1", "ec2", "volume", "vol-1"},
+	} {
+		resource, err := ParseResourceARN(testCase.arn)
+		if err != nil {
+			t.Errorf("%s\tFailed to parse: %v", testCase.arn, err)
+			continue
+		}
+		if resource.Service != testCase.service || resource.ResourceType != testCase.resourceType || resource.ResourceID != testCase.resourceID {
+			t.Errorf("%s\tWant:%s %s %s\tHave:%s %s %s", testCase.arn,
+				testCase.service, testCase.resourceType, testCase.resourceID,
+				resource.Service, resource.ResourceType, resource.ResourceID)
+		}
+	}
+	if _, err := ParseResourceARN("not-an-arn"); err == nil {
+		t.Errorf("Expected an error for an invalid ARN")
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package azurelib
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestARM returns a local stand-in for the login and ARM endpoints serving the given list responses by path.
+// Every ARM request has to carry the token issued by the stand-in, {{endpoint}} in responses is replaced
+// with the URL of the stand-in so that next links can be followed
+func newTestARM(t *testing.T, responses map[string]string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tenant/oauth2/v2.0/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("grant_type") != "client_credentials" {
+			t.Errorf("Unexpected token request: %v", r.Form)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"test-token","token_type":"Bearer","expires_in":3600}`)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":{"code":"InvalidAuthenticationToken","message":"missing token"}}`)
+			return
+		}
+		if r.URL.Query().Get("api-version") == "" {
+			t.Errorf("Missing api-version for %s", r.URL.Path)
+		}
+		key := r.URL.Path
+		if page := r.URL.Query().Get("page"); page != "" {
+			key += "?page=" + page
+		}
+		body, ok := responses[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"error":{"code":"NotFound","message":"%s"}}`, key)
+			return
+		}
+		fmt.Fprint(w, strings.Replace(body, "{{endpoint}}", "http://"+r.Host, -1))
+	})
+	return httptest.NewServer(mux)
+}
+
+func newTestClient(server *httptest.Server) *Client {
+	return newClient(server.URL, server.URL, "tenant", "client", "secret")
+}
+
+// TestGetAllSubscriptions checks that the client authenticates and follows next links
+func TestGetAllSubscriptions(t *testing.T) {
+	server := newTestARM(t, map[string]string{
+		"/subscriptions": `{"value":[{"subscriptionId":"sub-1","displayName":"one","state":"Enabled"}],` +
+			`"nextLink":"{{endpoint}}/subscriptions?api-version=2020-01-01&page=2"}`,
+		"/subscriptions?page=2": `{"value":[{"subscriptionId":"sub-2","displayName":"two","state":"Disabled"}]}`,
+	})
+	defer server.Close()
+	subscriptions, err := newTestClient(server).GetAllSubscriptions()
+	if err != nil {
+		t.Fatalf("Failed to list subscriptions: %v", err)
+	}
+	if len(subscriptions) != 2 || subscriptions[0].SubscriptionID != "sub-1" || subscriptions[1].State != "Disabled" {
+		t.Errorf("Unexpected subscriptions %+v", subscriptions)
+	}
+}
+
+// TestClientErrors checks that ARM errors are surfaced and throttled requests retried
+func TestClientErrors(t *testing.T) {
+	throttled := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/subscriptions":
+			if throttled == 0 {
+				throttled++
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			fmt.Fprint(w, `{"value":[{"subscriptionId":"sub-1"}]}`)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error":{"code":"AuthorizationFailed","message":"denied"}}`)
+		}
+	}))
+	defer server.Close()
+	client := NewClientWithHTTPClient(server.URL, server.Client())
+
+	subscriptions, err := client.GetAllSubscriptions()
+	if err != nil || len(subscriptions) != 1 || throttled != 1 {
+		t.Errorf("Throttled request was not retried: %v", err)
+	}
+	_, err = client.GetAllDisks("sub-1")
+	armErr, ok := err.(*Error)
+	if !ok || armErr.StatusCode != http.StatusForbidden || armErr.Code != "AuthorizationFailed" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+// TestGetAllVMs checks that virtual machines are linked to their network interfaces and public IPs
+func TestGetAllVMs(t *testing.T) {
+	const sub = "/subscriptions/sub-1"
+	server := newTestARM(t, map[string]string{
+		sub + "/providers/Microsoft.Compute/virtualMachines": `{"value":[{
+			"id":"/subscriptions/sub-1/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/web",
+			"name":"web","location":"westeurope","tags":{"env":"prod"},
+			"properties":{"hardwareProfile":{"vmSize":"Standard_B2s"},
+				"storageProfile":{"osDisk":{"osType":"Linux","name":"web-os"}},
+				"networkProfile":{"networkInterfaces":[{"id":"/subscriptions/sub-1/resourceGroups/RG/providers/Microsoft.Network/networkInterfaces/WEB-NIC"}]}}}]}`,
+		sub + "/providers/Microsoft.Network/networkInterfaces": `{"value":[{
+			"id":"/subscriptions/sub-1/resourceGroups/rg/providers/Microsoft.Network/networkInterfaces/web-nic","name":"web-nic",
+			"properties":{"ipConfigurations":[{"name":"ipconfig1","properties":{"privateIPAddress":"10.0.0.4",
+				"publicIPAddress":{"id":"/subscriptions/sub-1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/web-ip"}}}]}}]}`,
+		sub + "/providers/Microsoft.Network/publicIPAddresses": `{"value":[{
+			"id":"/subscriptions/sub-1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/web-ip","name":"web-ip",
+			"properties":{"ipAddress":"203.0.113.4","publicIPAllocationMethod":"Static"}}]}`,
+	})
+	defer server.Close()
+	vms, err := newTestClient(server).GetAllVMs("sub-1")
+	if err != nil {
+		t.Fatalf("Failed to list VMs: %v", err)
+	}
+	if len(vms) != 1 {
+		t.Fatalf("Expected 1 VM, got %d", len(vms))
+	}
+	vm := vms[0]
+	if vm.VirtualMachine.Properties.HardwareProfile.VMSize != "Standard_B2s" || vm.VirtualMachine.Properties.StorageProfile.OSDisk.OSType != "Linux" {
+		t.Errorf("Unexpected VM %+v", vm.VirtualMachine)
+	}
+	if vm.VirtualMachine.Tags["env"] != "prod" {
+		t.Errorf("VM tags were not decoded")
+	}
+	if len(vm.NetworkInterfaces) != 1 || vm.NetworkInterfaces[0].Properties.IPConfigurations[0].Properties.PrivateIPAddress != "10.0.0.4" {
+		t.Errorf("VM was not linked to its network interface")
+	}
+	if len(vm.PublicIPs) != 1 || vm.PublicIPs[0].Properties.IPAddress != "203.0.113.4" {
+		t.Errorf("VM was not linked to its public IP")
+	}
+}
+
+// TestGetAllDatabases checks that SQL servers are listed with their databases, along with PostgreSQL servers and disks
+func TestGetAllDatabases(t *testing.T) {
+	const sub = "/subscriptions/sub-1"
+	const server1 = "/subscriptions/sub-1/resourceGroups/rg/providers/Microsoft.Sql/servers/sql-1"
+	server := newTestARM(t, map[string]string{
+		sub + "/providers/Microsoft.Sql/servers": `{"value":[{"id":"` + server1 + `","name":"sql-1","location":"westeurope",
+			"properties":{"fullyQualifiedDomainName":"sql-1.database.windows.net","version":"12.0","state":"Ready"}}]}`,
+		server1 + "/databases": `{"value":[{"id":"` + server1 + `/databases/master","name":"master"},
+			{"id":"` + server1 + `/databases/app","name":"app","sku":{"name":"S0","tier":"Standard"}}]}`,
+		sub + "/providers/Microsoft.DBforPostgreSQL/flexibleServers": `{"value":[{"id":"pg-1","name":"pg-1",
+			"sku":{"name":"Standard_B1ms","tier":"Burstable"},"properties":{"version":"14","storage":{"storageSizeGB":32}}}]}`,
+		sub + "/providers/Microsoft.Compute/disks": `{"value":[{"id":"disk-1","name":"disk-1","managedBy":"vm-1",
+			"properties":{"diskSizeGB":30,"diskState":"Attached"}},{"id":"disk-2","name":"disk-2","properties":{"diskSizeGB":64,"diskState":"Unattached"}}]}`,
+	})
+	defer server.Close()
+	client := newTestClient(server)
+
+	sqlServers, err := client.GetAllSQLServers("sub-1")
+	if err != nil {
+		t.Fatalf("Failed to list SQL servers: %v", err)
+	}
+	if len(sqlServers) != 1 || len(sqlServers[0].Databases) != 2 || sqlServers[0].Databases[1].Sku.Tier != "Standard" {
+		t.Errorf("Unexpected SQL servers %+v", sqlServers)
+	}
+	pgServers, err := client.GetAllPostgreSQLServers("sub-1")
+	if err != nil {
+		t.Fatalf("Failed to list PostgreSQL servers: %v", err)
+	}
+	if len(pgServers) != 1 || pgServers[0].Properties.Storage.StorageSizeGB != 32 {
+		t.Errorf("Unexpected PostgreSQL servers %+v", pgServers)
+	}
+	disks, err := client.GetAllDisks("sub-1")
+	if err != nil {
+		t.Fatalf("Failed to list disks: %v", err)
+	}
+	if len(disks) != 2 || disks[0].ManagedBy != "vm-1" || disks[1].Properties.DiskState != "Unattached" {
+		t.Errorf("Unexpected disks %+v", disks)
+	}
+}
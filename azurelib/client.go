@@ -0,0 +1,142 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package azurelib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/adobe/cloudinventory/restlib"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	// ManagementEndpoint is the Azure Resource Manager endpoint of the public cloud
+	ManagementEndpoint = "https://management.azure.com"
+	// LoginEndpoint is the Azure Active Directory endpoint of the public cloud
+	LoginEndpoint = "https://login.microsoftonline.com"
+)
+
+// Client is a minimal Azure Resource Manager REST client, only supporting the list operations used for inventory
+type Client struct {
+	endpoint string
+	http     *http.Client
+}
+
+// NewClient returns a Client authenticating against the public cloud as the given service principal
+func NewClient(tenantID, clientID, clientSecret string) *Client {
+	return newClient(LoginEndpoint, ManagementEndpoint, tenantID, clientID, clientSecret)
+}
+
+// NewClientFromEnv returns a Client authenticating as the service principal described by the
+// standard AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET environment variables
+func NewClientFromEnv() (*Client, error) {
+	tenantID, clientID, clientSecret := os.Getenv("AZURE_TENANT_ID"), os.Getenv("AZURE_CLIENT_ID"), os.Getenv("AZURE_CLIENT_SECRET")
+	if tenantID == "" || clientID == "" || clientSecret == "" {
+		return nil, fmt.Errorf("Failed to get Azure Credentials, set AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET")
+	}
+	return NewClient(tenantID, clientID, clientSecret), nil
+}
+
+// NewClientWithHTTPClient returns a Client sending its requests to endpoint through httpClient,
+// which is expected to take care of authentication
+func NewClientWithHTTPClient(endpoint string, httpClient *http.Client) *Client {
+	return &Client{endpoint: strings.TrimSuffix(endpoint, "/"), http: httpClient}
+}
+
+func newClient(loginEndpoint, endpoint, tenantID, clientID, clientSecret string) *Client {
+	config := clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimSuffix(loginEndpoint, "/"), tenantID),
+		Scopes:       []string{ManagementEndpoint + "/.default"},
+	}
+	return NewClientWithHTTPClient(endpoint, config.Client(context.Background()))
+}
+
+// Error is returned for ARM requests which did not succeed
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Azure request failed with status %d: %s %s", e.StatusCode, e.Code, e.Message)
+}
+
+// list calls the ARM list operation at path, following nextLink until every page was passed to appendPage
+func (c *Client) list(path, apiVersion string, appendPage func(value json.RawMessage) error) error {
+	next := fmt.Sprintf("%s%s?api-version=%s", c.endpoint, path, url.QueryEscape(apiVersion))
+	for next != "" {
+		var page struct {
+			Value    json.RawMessage `json:"value"`
+			NextLink string          `json:"nextLink"`
+		}
+		if err := c.get(next, &page); err != nil {
+			return err
+		}
+		if len(page.Value) > 0 {
+			if err := appendPage(page.Value); err != nil {
+				return err
+			}
+		}
+		next = page.NextLink
+	}
+	return nil
+}
+
+// get decodes the JSON document at u into v, retrying throttled requests
+func (c *Client) get(u string, v interface{}) error {
+	status, body, err := restlib.Get(c.http, u)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		var armErr struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.Unmarshal(body, &armErr)
+		return &Error{StatusCode: status, Code: armErr.Error.Code, Message: armErr.Error.Message}
+	}
+	return json.Unmarshal(body, v)
+}
+
+// Subscription holds an Azure subscription the credentials have access to
+type Subscription struct {
+	SubscriptionID string `json:"subscriptionId"`
+	DisplayName    string `json:"displayName"`
+	State          string `json:"state"`
+}
+
+// GetAllSubscriptions returns every subscription the credentials have access to
+func (c *Client) GetAllSubscriptions() ([]*Subscription, error) {
+	var allSubscriptions []*Subscription
+	err := c.list("/subscriptions", "2020-01-01", func(value json.RawMessage) error {
+		var page []*Subscription
+		if err := json.Unmarshal(value, &page); err != nil {
+			return err
+		}
+		allSubscriptions = append(allSubscriptions, page...)
+		return nil
+	})
+	return allSubscriptions, err
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package azurelib
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SubResource references another ARM resource by ID
+type SubResource struct {
+	ID string `json:"id"`
+}
+
+// VirtualMachine holds the ARM representation of a virtual machine
+type VirtualMachine struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Location   string            `json:"location"`
+	Zones      []string          `json:"zones,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Properties struct {
+		VMID            string `json:"vmId"`
+		HardwareProfile struct {
+			VMSize string `json:"vmSize"`
+		} `json:"hardwareProfile"`
+		StorageProfile struct {
+			ImageReference json.RawMessage `json:"imageReference,omitempty"`
+			OSDisk         struct {
+				OSType      string       `json:"osType"`
+				Name        string       `json:"name"`
+				ManagedDisk *SubResource `json:"managedDisk,omitempty"`
+			} `json:"osDisk"`
+			DataDisks []struct {
+				Lun         int          `json:"lun"`
+				Name        string       `json:"name"`
+				ManagedDisk *SubResource `json:"managedDisk,omitempty"`
+			} `json:"dataDisks"`
+		} `json:"storageProfile"`
+		OSProfile *struct {
+			ComputerName string `json:"computerName"`
+		} `json:"osProfile,omitempty"`
+		NetworkProfile struct {
+			NetworkInterfaces []*SubResource `json:"networkInterfaces"`
+		} `json:"networkProfile"`
+		ProvisioningState string `json:"provisioningState"`
+	} `json:"properties"`
+}
+
+// NetworkInterface holds the ARM representation of a network interface
+type NetworkInterface struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Location   string            `json:"location"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Properties struct {
+		MACAddress       string `json:"macAddress"`
+		Primary          bool   `json:"primary"`
+		IPConfigurations []struct {
+			Name       string `json:"name"`
+			Properties struct {
+				PrivateIPAddress string       `json:"privateIPAddress"`
+				PublicIPAddress  *SubResource `json:"publicIPAddress,omitempty"`
+				Subnet           *SubResource `json:"subnet,omitempty"`
+			} `json:"properties"`
+		} `json:"ipConfigurations"`
+		VirtualMachine *SubResource `json:"virtualMachine,omitempty"`
+	} `json:"properties"`
+}
+
+// PublicIPAddress holds the ARM representation of a public IP address
+type PublicIPAddress struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Location   string            `json:"location"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Properties struct {
+		IPAddress                string `json:"ipAddress"`
+		PublicIPAllocationMethod string `json:"publicIPAllocationMethod"`
+		DNSSettings              *struct {
+			FQDN string `json:"fqdn"`
+		} `json:"dnsSettings,omitempty"`
+	} `json:"properties"`
+}
+
+// Disk holds the ARM representation of a managed disk.
+// ManagedBy is the ID of the virtual machine the disk is attached to, empty for unattached disks
+type Disk struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Location  string            `json:"location"`
+	ManagedBy string            `json:"managedBy,omitempty"`
+	Zones     []string          `json:"zones,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	Sku       *struct {
+		Name string `json:"name"`
+	} `json:"sku,omitempty"`
+	Properties struct {
+		OSType      string          `json:"osType,omitempty"`
+		DiskSizeGB  int             `json:"diskSizeGB"`
+		DiskState   string          `json:"diskState"`
+		TimeCreated string          `json:"timeCreated"`
+		Encryption  json.RawMessage `json:"encryption,omitempty"`
+	} `json:"properties"`
+}
+
+// VM holds a virtual machine along with its network interfaces and the public IPs attached to them
+type VM struct {
+	VirtualMachine    *VirtualMachine
+	NetworkInterfaces []*NetworkInterface
+	PublicIPs         []*PublicIPAddress
+}
+
+// GetAllVirtualMachines returns every virtual machine of a subscription
+func (c *Client) GetAllVirtualMachines(subscriptionID string) ([]*VirtualMachine, error) {
+	var allVMs []*VirtualMachine
+	err := c.list(fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Compute/virtualMachines", subscriptionID), "2023-03-01", func(value json.RawMessage) error {
+		var page []*VirtualMachine
+		if err := json.Unmarshal(value, &page); err != nil {
+			return err
+		}
+		allVMs = append(allVMs, page...)
+		return nil
+	})
+	return allVMs, err
+}
+
+// GetAllNetworkInterfaces returns every network interface of a subscription
+func (c *Client) GetAllNetworkInterfaces(subscriptionID string) ([]*NetworkInterface, error) {
+	var allInterfaces []*NetworkInterface
+	err := c.list(fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Network/networkInterfaces", subscriptionID), "2023-05-01", func(value json.RawMessage) error {
+		var page []*NetworkInterface
+		if err := json.Unmarshal(value, &page); err != nil {
+			return err
+		}
+		allInterfaces = append(allInterfaces, page...)
+		return nil
+	})
+	return allInterfaces, err
+}
+
+// GetAllPublicIPAddresses returns every public IP address of a subscription
+func (c *Client) GetAllPublicIPAddresses(subscriptionID string) ([]*PublicIPAddress, error) {
+	var allAddresses []*PublicIPAddress
+	err := c.list(fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Network/publicIPAddresses", subscriptionID), "2023-05-01", func(value json.RawMessage) error {
+		var page []*PublicIPAddress
+		if err := json.Unmarshal(value, &page); err != nil {
+			return err
+		}
+		allAddresses = append(allAddresses, page...)
+		return nil
+	})
+	return allAddresses, err
+}
+
+// GetAllDisks returns every managed disk of a subscription
+func (c *Client) GetAllDisks(subscriptionID string) ([]*Disk, error) {
+	var allDisks []*Disk
+	err := c.list(fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Compute/disks", subscriptionID), "2023-04-02", func(value json.RawMessage) error {
+		var page []*Disk
+		if err := json.Unmarshal(value, &page); err != nil {
+			return err
+		}
+		allDisks = append(allDisks, page...)
+		return nil
+	})
+	return allDisks, err
+}
+
+// GetAllVMs returns every virtual machine of a subscription linked to its network interfaces and public IPs
+func (c *Client) GetAllVMs(subscriptionID string) ([]*VM, error) {
+	vms, err := c.GetAllVirtualMachines(subscriptionID)
+	if err != nil || len(vms) == 0 {
+		return nil, err
+	}
+	interfaces, err := c.GetAllNetworkInterfaces(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	addresses, err := c.GetAllPublicIPAddresses(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return LinkVMNetworks(vms, interfaces, addresses), nil
+}
+
+// LinkVMNetworks links every virtual machine to its network interfaces and the public IPs attached to them.
+// ARM resource IDs are compared case insensitively
+func LinkVMNetworks(vms []*VirtualMachine, interfaces []*NetworkInterface, addresses []*PublicIPAddress) []*VM {
+	interfacesByID := make(map[string]*NetworkInterface)
+	for _, ni := range interfaces {
+		interfacesByID[strings.ToLower(ni.ID)] = ni
+	}
+	addressesByID := make(map[string]*PublicIPAddress)
+	for _, a := range addresses {
+		addressesByID[strings.ToLower(a.ID)] = a
+	}
+	var linked []*VM
+	for _, v := range vms {
+		vm := &VM{VirtualMachine: v}
+		for _, ref := range v.Properties.NetworkProfile.NetworkInterfaces {
+			ni, ok := interfacesByID[strings.ToLower(ref.ID)]
+			if !ok {
+				continue
+			}
+			vm.NetworkInterfaces = append(vm.NetworkInterfaces, ni)
+			for _, ipConfig := range ni.Properties.IPConfigurations {
+				if ipConfig.Properties.PublicIPAddress == nil {
+					continue
+				}
+				if a, ok := addressesByID[strings.ToLower(ipConfig.Properties.PublicIPAddress.ID)]; ok {
+					vm.PublicIPs = append(vm.PublicIPs, a)
+				}
+			}
+		}
+		linked = append(linked, vm)
+	}
+	return linked
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package azurelib
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Sku holds the pricing tier of a resource
+type Sku struct {
+	Name     string `json:"name"`
+	Tier     string `json:"tier,omitempty"`
+	Capacity int    `json:"capacity,omitempty"`
+}
+
+// SQLServer holds the ARM representation of an Azure SQL logical server
+type SQLServer struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Location   string            `json:"location"`
+	Kind       string            `json:"kind,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Properties struct {
+		FullyQualifiedDomainName string `json:"fullyQualifiedDomainName"`
+		Version                  string `json:"version"`
+		State                    string `json:"state"`
+		PublicNetworkAccess      string `json:"publicNetworkAccess,omitempty"`
+	} `json:"properties"`
+}
+
+// SQLDatabase holds the ARM representation of an Azure SQL database
+type SQLDatabase struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Location   string            `json:"location"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Sku        *Sku              `json:"sku,omitempty"`
+	Properties struct {
+		Status       string `json:"status"`
+		MaxSizeBytes int64  `json:"maxSizeBytes"`
+		ElasticPool  string `json:"elasticPoolId,omitempty"`
+	} `json:"properties"`
+}
+
+// SQLServerDatabases holds an Azure SQL server along with its databases
+type SQLServerDatabases struct {
+	Server    *SQLServer
+	Databases []*SQLDatabase
+}
+
+// PostgreSQLServer holds the ARM representation of an Azure Database for PostgreSQL flexible server
+type PostgreSQLServer struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Location   string            `json:"location"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Sku        *Sku              `json:"sku,omitempty"`
+	Properties struct {
+		FullyQualifiedDomainName string `json:"fullyQualifiedDomainName"`
+		Version                  string `json:"version"`
+		State                    string `json:"state"`
+		Storage                  struct {
+			StorageSizeGB int `json:"storageSizeGB"`
+		} `json:"storage"`
+		Network struct {
+			PublicNetworkAccess string `json:"publicNetworkAccess,omitempty"`
+		} `json:"network"`
+	} `json:"properties"`
+}
+
+// GetAllSQLServers returns every Azure SQL server of a subscription along with its databases
+func (c *Client) GetAllSQLServers(subscriptionID string) ([]*SQLServerDatabases, error) {
+	var servers []*SQLServer
+	err := c.list(fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Sql/servers", subscriptionID), "2021-11-01", func(value json.RawMessage) error {
+		var page []*SQLServer
+		if err := json.Unmarshal(value, &page); err != nil {
+			return err
+		}
+		servers = append(servers, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var allServers []*SQLServerDatabases
+	for _, s := range servers {
+		server := &SQLServerDatabases{Server: s}
+		err := c.list(s.ID+"/databases", "2021-11-01", func(value json.RawMessage) error {
+			var page []*SQLDatabase
+			if err := json.Unmarshal(value, &page); err != nil {
+				return err
+			}
+			server.Databases = append(server.Databases, page...)
+			return nil
+		})
+		if err != nil {
+			return allServers, err
+		}
+		allServers = append(allServers, server)
+	}
+	return allServers, nil
+}
+
+// GetAllPostgreSQLServers returns every Azure Database for PostgreSQL flexible server of a subscription
+func (c *Client) GetAllPostgreSQLServers(subscriptionID string) ([]*PostgreSQLServer, error) {
+	var allServers []*PostgreSQLServer
+	err := c.list(fmt.Sprintf("/subscriptions/%s/providers/Microsoft.DBforPostgreSQL/flexibleServers", subscriptionID), "2022-12-01", func(value json.RawMessage) error {
+		var page []*PostgreSQLServer
+		if err := json.Unmarshal(value, &page); err != nil {
+			return err
+		}
+		allServers = append(allServers, page...)
+		return nil
+	})
+	return allServers, err
+}
@@ -0,0 +1,418 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adobe/cloudinventory/azurelib"
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/adobe/cloudinventory/dolib"
+	"github.com/adobe/cloudinventory/gcplib"
+	"github.com/adobe/cloudinventory/hetznerlib"
+	"github.com/adobe/cloudinventory/linodelib"
+	"github.com/adobe/cloudinventory/openstacklib"
+	"github.com/adobe/cloudinventory/vspherelib"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// allConfig is the providers section of the config file, listing the providers collected by dump all
+type allConfig struct {
+	Providers []providerConfig `json:"providers"`
+}
+
+// providerConfig describes a single provider account of dump all. Only the settings of its type apply,
+// secrets are never part of the file, the environment variables holding them are named instead
+type providerConfig struct {
+	// Name keys the inventory of the provider in the envelope, the type if empty
+	Name string `json:"name"`
+	// Type is one of aws, azure, gcp, kubernetes, digitalocean, linode, hetzner, openstack or vsphere
+	Type string `json:"type"`
+	// Services limits the collected services, every service of the type if empty
+	Services []string `json:"services"`
+
+	// AWS: credentials of the shared config profile, optionally assuming a role, the environment if empty
+	Partition string   `json:"partition"`
+	Regions   []string `json:"regions"`
+	Profile   string   `json:"profile"`
+	RoleARN   string   `json:"role_arn"`
+
+	// Azure: service principal, the AZURE_* environment variables if empty
+	Subscriptions   []string `json:"subscriptions"`
+	TenantID        string   `json:"tenant_id"`
+	ClientID        string   `json:"client_id"`
+	ClientSecretEnv string   `json:"client_secret_env"`
+
+	// Google Cloud: service account key, Application Default Credentials if empty
+	Projects        []string `json:"projects"`
+	CredentialsFile string   `json:"credentials_file"`
+
+	// Kubernetes
+	Kubeconfig string   `json:"kubeconfig"`
+	Contexts   []string `json:"contexts"`
+
+	// DigitalOcean, Linode and Hetzner Cloud: variable holding the API token, the provider's standard one if empty
+	TokenEnv string `json:"token_env"`
+
+	// OpenStack
+	Cloud      string `json:"cloud"`
+	CloudsFile string `json:"clouds_file"`
+
+	// vSphere: the GOVC_* environment variables if URL is empty
+	URL         string `json:"url"`
+	Username    string `json:"username"`
+	PasswordEnv string `json:"password_env"`
+	Insecure    bool   `json:"insecure"`
+}
+
+// allEnvelope is the document written by dump all
+type allEnvelope struct {
+	GeneratedAt time.Time                  `json:"generated_at"`
+	Providers   map[string]*providerResult `json:"providers"`
+}
+
+// providerResult holds the inventory of a provider, keyed by service, along with the errors met while collecting it.
+// A provider failing to authenticate has no inventory, a failing service is left out of it
+type providerResult struct {
+	Type      string                 `json:"type"`
+	Inventory map[string]interface{} `json:"inventory"`
+	Errors    []string               `json:"errors,omitempty"`
+}
+
+// providerServices returns the services supported by every provider type
+func providerServices() map[string][]string {
+	return map[string][]string{
+		"aws":          awsServiceNames(),
+		"azure":        azureServiceNames(),
+		"gcp":          gcpServiceNames(),
+		"kubernetes":   {"clusters"},
+		"digitalocean": collector.DigitalOceanCollector{}.Services(),
+		"linode":       collector.LinodeCollector{}.Services(),
+		"hetzner":      collector.HetznerCollector{}.Services(),
+		"openstack":    collector.OpenStackCollector{}.Services(),
+		"vsphere":      collector.VSphereCollector{}.Services(),
+	}
+}
+
+// allCmd represents the all command
+var allCmd = &cobra.Command{
+	Use:   "all",
+	Short: "Dump the inventory of every provider listed in the config file",
+	Long: "Dump the inventory of every provider listed under providers in the config file, concurrently, into a single document.\n" +
+		"Each provider is keyed by its name under providers, along with the errors met while collecting it",
+	Run: func(cmd *cobra.Command, args []string) {
+		path := cmd.Flag("path").Value.String()
+		if cmd.Flag("filter").Value.String() != "" {
+			fmt.Printf("--filter is not supported by dump all, list services per provider in the config file\n")
+			return
+		}
+		configFile := settingsPath()
+		if configFile == "" {
+			fmt.Printf("No config file found, give the one listing the providers to collect with --config\n")
+			return
+		}
+		config, err := loadAllConfig(configFile)
+		if err != nil {
+			fmt.Printf("Invalid config %s: %v\n", configFile, err)
+			return
+		}
+
+		envelope := allEnvelope{GeneratedAt: time.Now().UTC(), Providers: make(map[string]*providerResult)}
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, p := range config.Providers {
+			wg.Add(1)
+			go func(p providerConfig) {
+				defer wg.Done()
+				result := collectProvider(p)
+				mu.Lock()
+				envelope.Providers[p.Name] = result
+				mu.Unlock()
+			}(p)
+		}
+		wg.Wait()
+
+		var failed []string
+		for name, result := range envelope.Providers {
+			if len(result.Errors) > 0 {
+				failed = append(failed, name)
+			}
+		}
+		sort.Strings(failed)
+		for _, name := range failed {
+			fmt.Printf("Errors while collecting %s:\n  %s\n", name, strings.Join(envelope.Providers[name].Errors, "\n  "))
+		}
+		fmt.Printf("Gathered %d providers, %d with errors\n", len(envelope.Providers), len(failed))
+
+		jsonBytes, err := json.Marshal(envelope)
+		if err != nil {
+			fmt.Printf("Error Marshalling JSON: %v\n", err)
+		}
+		err = writeDump(cmd, path, jsonBytes)
+		if err != nil {
+			fmt.Printf("Error writing inventory: %v\n", err)
+		}
+	},
+}
+
+// loadAllConfig reads the providers of the config file at path, defaulting their names to their type and checking their services.
+// Flag settings of the file are left to applySettings
+func loadAllConfig(path string) (*allConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file map[string]interface{}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	providers, err := yaml.Marshal(map[string]interface{}{"providers": file["providers"]})
+	if err != nil {
+		return nil, err
+	}
+	var config allConfig
+	if err := yaml.UnmarshalStrict(providers, &config); err != nil {
+		return nil, err
+	}
+	if len(config.Providers) == 0 {
+		return nil, fmt.Errorf("No providers listed")
+	}
+	supported := providerServices()
+	names := make(map[string]bool)
+	for i := range config.Providers {
+		p := &config.Providers[i]
+		services, ok := supported[p.Type]
+		if !ok {
+			return nil, fmt.Errorf("Unsupported provider type %q", p.Type)
+		}
+		if p.Name == "" {
+			p.Name = p.Type
+		}
+		if names[p.Name] {
+			return nil, fmt.Errorf("Provider %s is listed twice, give each a unique name", p.Name)
+		}
+		names[p.Name] = true
+		for _, s := range p.Services {
+			if !stringInSlice(s, services) {
+				return nil, fmt.Errorf("Provider %s does not support service %s, select from %s", p.Name, s, strings.Join(services, ", "))
+			}
+		}
+		if len(p.Services) == 0 {
+			p.Services = services
+		}
+	}
+	return &config, nil
+}
+
+// collectProvider collects every service of a provider, recording errors instead of stopping at the first one
+func collectProvider(p providerConfig) *providerResult {
+	result := &providerResult{Type: p.Type, Inventory: make(map[string]interface{})}
+	fail := func(err error) *providerResult {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+	// collect runs every service of the provider through run
+	collect := func(run func(service string) error) *providerResult {
+		for _, service := range p.Services {
+			if err := run(service); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", service, err))
+			}
+		}
+		return result
+	}
+
+	switch p.Type {
+	case "aws":
+		creds, err := awsCredentials(p)
+		if err != nil {
+			return fail(err)
+		}
+		partition := p.Partition
+		if partition == "" {
+			partition = "default"
+		}
+		col, err := collector.NewAWSCollectorForRegions(partition, p.Regions, creds)
+		if err != nil {
+			return fail(err)
+		}
+		collect(func(service string) error {
+			return awsServices[service](col, result.Inventory)
+		})
+		if inventory, err := collector.AnnotateAWSOwners(result.Inventory); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("owners: %v", err))
+		} else {
+			result.Inventory = inventory
+		}
+		return result
+	case "azure":
+		var client *azurelib.Client
+		if p.TenantID != "" {
+			secret, err := requireEnv(p.ClientSecretEnv)
+			if err != nil {
+				return fail(err)
+			}
+			client = azurelib.NewClient(p.TenantID, p.ClientID, secret)
+		}
+		col, err := collector.NewAzureCollectorForSubscriptions(client, p.Subscriptions)
+		if err != nil {
+			return fail(err)
+		}
+		return collect(func(service string) error {
+			return azureServices[service](col, result.Inventory)
+		})
+	case "gcp":
+		client, err := gcplib.NewClient(context.Background(), p.CredentialsFile)
+		if err != nil {
+			return fail(err)
+		}
+		col, err := collector.NewGCPCollector(client, p.Projects)
+		if err != nil {
+			return fail(err)
+		}
+		return collect(func(service string) error {
+			return gcpServices[service](col, result.Inventory)
+		})
+	case "kubernetes":
+		col, err := collector.NewKubernetesCollector(p.Kubeconfig, p.Contexts)
+		if err != nil {
+			return fail(err)
+		}
+		return collect(func(service string) error {
+			clusters, err := col.CollectClusters()
+			if err == nil {
+				result.Inventory[service] = clusters
+			}
+			return err
+		})
+	default:
+		provider, err := newConfiguredProvider(p)
+		if err != nil {
+			return fail(err)
+		}
+		if closer, ok := provider.(io.Closer); ok {
+			defer closer.Close()
+		}
+		return collect(func(service string) error {
+			inventory, err := provider.Collect(service)
+			if err == nil {
+				result.Inventory[service] = inventory
+			}
+			return err
+		})
+	}
+}
+
+// newConfiguredProvider returns the collector.Provider of the providers without a dedicated command of their own
+func newConfiguredProvider(p providerConfig) (collector.Provider, error) {
+	switch p.Type {
+	case "digitalocean":
+		if p.TokenEnv == "" {
+			return collector.NewDigitalOceanCollector(nil)
+		}
+		token, err := requireEnv(p.TokenEnv)
+		if err != nil {
+			return nil, err
+		}
+		return collector.NewDigitalOceanCollector(dolib.NewClient(token))
+	case "linode":
+		if p.TokenEnv == "" {
+			return collector.NewLinodeCollector(nil)
+		}
+		token, err := requireEnv(p.TokenEnv)
+		if err != nil {
+			return nil, err
+		}
+		return collector.NewLinodeCollector(linodelib.NewClient(token))
+	case "hetzner":
+		if p.TokenEnv == "" {
+			return collector.NewHetznerCollector(nil)
+		}
+		token, err := requireEnv(p.TokenEnv)
+		if err != nil {
+			return nil, err
+		}
+		return collector.NewHetznerCollector(hetznerlib.NewClient(token))
+	case "openstack":
+		cloud, err := openstacklib.LoadCloud(p.CloudsFile, p.Cloud)
+		if err != nil {
+			return nil, err
+		}
+		client, err := openstacklib.NewClient(cloud)
+		if err != nil {
+			return nil, err
+		}
+		return collector.NewOpenStackCollector(client)
+	case "vsphere":
+		if p.URL == "" {
+			return collector.NewVSphereCollector(nil)
+		}
+		client, err := vspherelib.NewClient(context.Background(), p.URL, p.Username, os.Getenv(p.PasswordEnv), p.Insecure)
+		if err != nil {
+			return nil, err
+		}
+		return collector.NewVSphereCollector(client)
+	default:
+		return nil, fmt.Errorf("Unsupported provider type %q", p.Type)
+	}
+}
+
+// requireEnv returns the value of the environment variable holding a secret, failing if it is not set
+func requireEnv(name string) (string, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return "", fmt.Errorf("Environment variable %q is not set", name)
+	}
+	return value, nil
+}
+
+// awsCredentials returns the credentials of the profile of an AWS provider, assuming its role if one is given.
+// Returns nil to use the Standard Environment variables when neither is set
+func awsCredentials(p providerConfig) (*credentials.Credentials, error) {
+	if p.RoleARN == "" {
+		if p.Profile == "" {
+			return nil, nil
+		}
+		return credentials.NewSharedCredentials("", p.Profile), nil
+	}
+	region := "us-east-1"
+	if strings.ToLower(p.Partition) == "china" {
+		region = "cn-north-1"
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            aws.Config{Region: aws.String(region)},
+		Profile:           p.Profile,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stscreds.NewCredentials(sess, p.RoleARN), nil
+}
+
+func init() {
+	dumpCmd.AddCommand(allCmd)
+}
@@ -16,22 +16,35 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
 
-	"github.com/aws/aws-sdk-go/service/ec2"
-	"github.com/spf13/cobra"
 	"github.com/adobe/cloudinventory/ansible"
+	"github.com/adobe/cloudinventory/awslib"
 	"github.com/adobe/cloudinventory/collector"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/spf13/cobra"
 )
 
 var partition string
 var ansibleinv string
 var ansibleEnable bool
 var ansiblePriv bool
+var ansibleGroupBy string
+var certExpiryDays int
+var ecrMaxImages int
+var ipIndexPath string
+var collectionMode string
+var source string
+var configAggregator string
+var configRegion string
 
 // awsCmd represents the aws command
 var awsCmd = &cobra.Command{
 	Use:   "aws",
-	Short: "Dump AWS inventory. Currently supports EC2/RDS",
+	Short: "Dump AWS inventory for all supported services, or the one selected with --filter",
 	Run: func(cmd *cobra.Command, args []string) {
 		path := cmd.Flag("path").Value.String()
 		filter := cmd.Flag("filter").Value.String()
@@ -39,73 +52,240 @@ var awsCmd = &cobra.Command{
 			fmt.Printf("Invalid filter selected, please select a supported AWS service")
 			return
 		}
-
-		col, err := collector.NewAWSCollector(partition, nil)
-		if err != nil {
-			fmt.Printf("Failed to create AWS collector: %v\n", err)
+		if collectionMode != "describe" && collectionMode != "tagging-api" {
+			fmt.Printf("Invalid mode %s, select describe/tagging-api\n", collectionMode)
 			return
 		}
 
 		// Create a map per service
 		result := make(map[string]interface{})
 
-		switch filter {
-		case "ec2":
-			err := collectEC2(col, result)
+		switch source {
+		case "api":
+			col, err := collector.NewAWSCollector(partition, nil)
 			if err != nil {
+				fmt.Printf("Failed to create AWS collector: %v\n", err)
 				return
 			}
-		case "rds":
-			err := collectRDS(col, result)
-			if err != nil {
+			if err := collectFromAPI(col, filter, result); err != nil {
 				return
 			}
-		default:
-			err := collectEC2(col, result)
-			if err != nil {
+			if ipIndexPath != "" {
+				fmt.Printf("Exporting IP index to %s\n", ipIndexPath)
+				if err := exportIPIndex(col, result, ipIndexPath); err != nil {
+					fmt.Printf("Error exporting IP index: %v\n", err)
+				}
+			}
+			if ansibleEnable {
+				writeAnsibleInventory(col, result)
+			}
+		case "config":
+			if collectionMode != "describe" || ipIndexPath != "" || ansibleGroupBy != "region" {
+				fmt.Printf("--mode, --ip_index and --ansible_group_by are not available with --source config\n")
 				return
 			}
-			err = collectRDS(col, result)
+			src, err := collector.NewConfigCollector(configRegion, configAggregator, nil)
 			if err != nil {
+				fmt.Printf("Failed to create AWS Config collector: %v\n", err)
+				return
+			}
+			if err := collectFromConfig(src, filter, result); err != nil {
 				return
 			}
+			if ansibleEnable {
+				writeConfigAnsibleInventory(result)
+			}
+		default:
+			fmt.Printf("Invalid source %s, select api/config\n", source)
+			return
 		}
-		fmt.Printf("Dumping to %s\n", path)
-		jsonBytes, err := json.Marshal(result)
+
+		dump, err := collector.AnnotateAWSOwners(result)
+		if err != nil {
+			fmt.Printf("Error annotating resource owners: %v\n", err)
+			dump = result
+		}
+		jsonBytes, err := json.Marshal(dump)
 		if err != nil {
 			fmt.Printf("Error Marshalling JSON: %v\n", err)
 		}
-		err = ioutil.WriteFile(path, jsonBytes, 0644)
+		err = writeDump(cmd, path, jsonBytes)
 		if err != nil {
-			fmt.Printf("Error writing file: %v\n", err)
+			fmt.Printf("Error writing inventory: %v\n", err)
 		}
+	},
+}
 
-		if ansibleEnable {
-			fmt.Printf("Building Inventory for Ansible at: %s", ansibleinv)
-			ansinv, err := ansible.BuildEC2Inventory(result["ec2"].(map[string][]*ec2.Instance), ansiblePriv)
-			if err != nil {
-				fmt.Printf("Error while building Ansible Inventory: %v\n", err)
+// collectFromAPI collects the services selected by filter, all of them if empty, through the Describe APIs of every region
+func collectFromAPI(col collector.AWSCollector, filter string, result map[string]interface{}) error {
+	if filter != "" {
+		if err := awsServices[filter](col, result); err != nil {
+			return err
+		}
+	} else {
+		collectServices(awsServiceNames(), func(service string) error {
+			return awsServices[service](col, result)
+		}, result)
+	}
+	// The tagging API covers services without a dedicated collector, merge it with the detailed inventory
+	if collectionMode == "tagging-api" {
+		return collectTaggedResources(col, result)
+	}
+	return nil
+}
+
+// collectFromConfig collects the services selected by filter, all of them if empty, which the aggregator is able to produce.
+// Inventories are keyed by account, then region
+func collectFromConfig(src collector.ConfigCollector, filter string, result map[string]interface{}) error {
+	services := map[string]func(collector.ConfigCollector, map[string]interface{}) error{
+		"ec2": collectConfigEC2,
+		"rds": collectConfigRDS,
+	}
+	if filter != "" {
+		collect, ok := services[filter]
+		if !ok {
+			fmt.Printf("%s is not available from this source, select ec2/rds\n", filter)
+			return fmt.Errorf("Unsupported service %s", filter)
+		}
+		return collect(src, result)
+	}
+	for _, collect := range []func(collector.ConfigCollector, map[string]interface{}) error{collectConfigEC2, collectConfigRDS} {
+		if err := collect(src, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func collectConfigEC2(src collector.ConfigCollector, result map[string]interface{}) error {
+	instances, err := src.CollectEC2()
+	if err != nil {
+		fmt.Printf("Failed to gather EC2 Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered EC2 Instances across %d accounts\n", len(instances))
+	result["ec2"] = instances
+	return nil
+}
+
+func collectConfigRDS(src collector.ConfigCollector, result map[string]interface{}) error {
+	instances, err := src.CollectRDS()
+	if err != nil {
+		fmt.Printf("Failed to gather RDS Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered RDS Instances across %d accounts\n", len(instances))
+	// The aggregator only knows about instances, keep the shape of the full RDS inventory
+	inventory := make(map[string]map[string]*collector.RDSInventory)
+	for account, regions := range instances {
+		inventory[account] = make(map[string]*collector.RDSInventory)
+		for region, i := range regions {
+			inventory[account][region] = &collector.RDSInventory{Instances: i}
+		}
+	}
+	result["rds"] = inventory
+	return nil
+}
+
+// writeConfigAnsibleInventory builds the EC2 ansible inventory of an aggregator dump, grouping the hosts
+// of every account by region
+func writeConfigAnsibleInventory(result map[string]interface{}) {
+	accounts, _ := result["ec2"].(map[string]map[string][]*ec2.Instance)
+	instances := make(map[string][]*ec2.Instance)
+	for _, regions := range accounts {
+		for region, i := range regions {
+			instances[region] = append(instances[region], i...)
+		}
+	}
+	writeAnsibleInventory(collector.AWSCollector{}, map[string]interface{}{"ec2": instances})
+}
+
+// writeAnsibleInventory builds the EC2 ansible inventory and writes it to --ansible_inv
+func writeAnsibleInventory(col collector.AWSCollector, result map[string]interface{}) {
+	fmt.Printf("Building Inventory for Ansible at: %s", ansibleinv)
+	ansinv, err := buildAnsibleInventory(col, result)
+	if err != nil {
+		fmt.Printf("Error while building Ansible Inventory: %v\n", err)
+	}
+	err = ioutil.WriteFile(ansibleinv, []byte(ansinv), 0644)
+	if err != nil {
+		fmt.Printf("Error writing to Ansible Inventory file: %v\n", err)
+	}
+}
+
+// awsServices maps every supported --filter value to the function collecting it
+var awsServices = map[string]func(collector.AWSCollector, map[string]interface{}) error{
+	"ec2":            collectEC2,
+	"rds":            collectRDS,
+	"ebs":            collectEBS,
+	"eks":            collectEKS,
+	"ecs":            collectECS,
+	"dynamodb":       collectDynamoDB,
+	"elasticache":    collectElastiCache,
+	"redshift":       collectRedshift,
+	"iam":            collectIAM,
+	"elb":            collectELB,
+	"route53":        collectRoute53,
+	"autoscaling":    collectAutoScaling,
+	"cloudformation": collectCloudFormation,
+	"sqs":            collectSQS,
+	"sns":            collectSNS,
+	"kinesis":        collectKinesis,
+	"eventbridge":    collectEventBridge,
+	"cloudfront":     collectCloudFront,
+	"apigateway":     collectAPIGateway,
+	"waf":            collectWAF,
+	"kms":            collectKMS,
+	"secretsmanager": collectSecretsManager,
+	"acm":            collectACM,
+	"ecr":            collectECR,
+	"addresses":      collectAddresses,
+	"filesystems":    collectFileSystems,
+}
+
+// awsServiceNames returns the supported AWS services in a stable order
+func awsServiceNames() []string {
+	var names []string
+	for name := range awsServices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// buildAnsibleInventory builds the EC2 ansible inventory grouped as requested by --ansible_group_by
+func buildAnsibleInventory(col collector.AWSCollector, result map[string]interface{}) (string, error) {
+	instances, ok := result["ec2"].(map[string][]*ec2.Instance)
+	if !ok {
+		return "", fmt.Errorf("EC2 inventory was not collected")
+	}
+	switch ansibleGroupBy {
+	case "region":
+		return ansible.BuildEC2Inventory(instances, ansiblePriv)
+	case "asg":
+		if _, ok := result["autoscaling"]; !ok {
+			if err := collectAutoScaling(col, result); err != nil {
+				return "", err
 			}
-			err = ioutil.WriteFile(ansibleinv, []byte(ansinv), 0644)
-			if err != nil {
-				fmt.Printf("Error writing to Ansible Inventory file: %v\n", err)
+		}
+		instanceGroups := make(map[string]string)
+		for _, inv := range result["autoscaling"].(map[string]*collector.AutoScalingInventory) {
+			for instance, group := range inv.InstanceGroups {
+				instanceGroups[instance] = group
 			}
 		}
-	},
+		return ansible.BuildEC2InventoryByASG(instances, instanceGroups, ansiblePriv)
+	default:
+		return "", fmt.Errorf("Invalid ansible grouping %s, select region/asg", ansibleGroupBy)
+	}
 }
 
 func validateAWSFilter(filter string) bool {
-	validSlice := []string{
-		"ec2",
-		"rds",
-		"",
-	}
-	for _, service := range validSlice {
-		if filter == service {
-			return true
-		}
+	if filter == "" {
+		return true
 	}
-	return false
+	_, ok := awsServices[filter]
+	return ok
 }
 
 func collectEC2(col collector.AWSCollector, result map[string]interface{}) error {
@@ -120,20 +300,388 @@ func collectEC2(col collector.AWSCollector, result map[string]interface{}) error
 }
 
 func collectRDS(col collector.AWSCollector, result map[string]interface{}) error {
-	instances, err := col.CollectRDS()
+	inventory, err := col.CollectRDSInventory()
 	if err != nil {
 		fmt.Printf("Failed to gather RDS Data: %v\n", err)
 		return err
 	}
-	fmt.Printf("Gathered RDS Instances across %d regions\n", len(instances))
-	result["rds"] = instances
+	fmt.Printf("Gathered RDS Instances, Clusters, Snapshots and Groups across %d regions\n", len(inventory))
+	result["rds"] = inventory
+	return nil
+}
+
+func collectEBS(col collector.AWSCollector, result map[string]interface{}) error {
+	inventory, err := col.CollectEBS()
+	if err != nil {
+		fmt.Printf("Failed to gather EBS Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered EBS Volumes, Snapshots and AMIs across %d regions\n", len(inventory))
+	result["ebs"] = inventory
+	return nil
+}
+
+func collectEKS(col collector.AWSCollector, result map[string]interface{}) error {
+	clusters, err := col.CollectEKS()
+	if err != nil {
+		fmt.Printf("Failed to gather EKS Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered EKS Clusters across %d regions\n", len(clusters))
+	result["eks"] = clusters
+	return nil
+}
+
+func collectECS(col collector.AWSCollector, result map[string]interface{}) error {
+	inventory, err := col.CollectECS()
+	if err != nil {
+		fmt.Printf("Failed to gather ECS Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered ECS Clusters and Task Definitions across %d regions\n", len(inventory))
+	result["ecs"] = inventory
+	return nil
+}
+
+func collectDynamoDB(col collector.AWSCollector, result map[string]interface{}) error {
+	tables, err := col.CollectDynamoDB()
+	if err != nil {
+		fmt.Printf("Failed to gather DynamoDB Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered DynamoDB Tables across %d regions\n", len(tables))
+	result["dynamodb"] = tables
+	return nil
+}
+
+func collectElastiCache(col collector.AWSCollector, result map[string]interface{}) error {
+	inventory, err := col.CollectElastiCache()
+	if err != nil {
+		fmt.Printf("Failed to gather ElastiCache Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered ElastiCache Replication Groups and Clusters across %d regions\n", len(inventory))
+	result["elasticache"] = inventory
+	return nil
+}
+
+func collectRedshift(col collector.AWSCollector, result map[string]interface{}) error {
+	clusters, err := col.CollectRedshift()
+	if err != nil {
+		fmt.Printf("Failed to gather Redshift Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Redshift Clusters across %d regions\n", len(clusters))
+	result["redshift"] = clusters
+	return nil
+}
+
+func collectIAM(col collector.AWSCollector, result map[string]interface{}) error {
+	inventory, err := col.CollectIAM()
+	if err != nil {
+		fmt.Printf("Failed to gather IAM Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered %d IAM Users, %d Groups and %d Roles\n", len(inventory.Users), len(inventory.Groups), len(inventory.Roles))
+	result["iam"] = inventory
+	return nil
+}
+
+func collectELB(col collector.AWSCollector, result map[string]interface{}) error {
+	inventory, err := col.CollectELB()
+	if err != nil {
+		fmt.Printf("Failed to gather ELB Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Load Balancers across %d regions\n", len(inventory))
+	result["elb"] = inventory
+	return nil
+}
+
+func collectRoute53(col collector.AWSCollector, result map[string]interface{}) error {
+	// Records are resolved against EC2, RDS, ELB and the addresses, gather those as well if they were filtered out
+	for service, collect := range map[string]func(collector.AWSCollector, map[string]interface{}) error{
+		"ec2": collectEC2,
+		"rds": collectRDS,
+		"elb": collectELB,
+	} {
+		if _, ok := result[service]; ok {
+			continue
+		}
+		if err := collect(col, result); err != nil {
+			return err
+		}
+	}
+	if _, ok := result["addresses"]; !ok && collectAddresses(col, result) != nil {
+		fmt.Printf("Route53 records pointing to IPs will not be flagged as dangling without the addresses\n")
+	}
+	_, withIPs := result["addresses"]
+	inventory, err := col.CollectRoute53(buildResourceIndex(result), withIPs)
+	if err != nil {
+		fmt.Printf("Failed to gather Route53 Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered %d Route53 Hosted Zones, %d records need review for dangling targets\n", len(inventory.Zones), len(inventory.Dangling))
+	result["route53"] = inventory
+	return nil
+}
+
+func collectAutoScaling(col collector.AWSCollector, result map[string]interface{}) error {
+	inventory, err := col.CollectAutoScaling()
+	if err != nil {
+		fmt.Printf("Failed to gather AutoScaling Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Auto Scaling Groups and Launch Templates across %d regions\n", len(inventory))
+	result["autoscaling"] = inventory
+	return nil
+}
+
+func collectCloudFormation(col collector.AWSCollector, result map[string]interface{}) error {
+	inventory, err := col.CollectCloudFormation()
+	if err != nil {
+		fmt.Printf("Failed to gather CloudFormation Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered CloudFormation Stacks across %d regions\n", len(inventory))
+	result["cloudformation"] = inventory
+	return nil
+}
+
+func collectSQS(col collector.AWSCollector, result map[string]interface{}) error {
+	queues, err := col.CollectSQS()
+	if err != nil {
+		fmt.Printf("Failed to gather SQS Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered SQS Queues across %d regions\n", len(queues))
+	result["sqs"] = queues
+	return nil
+}
+
+func collectSNS(col collector.AWSCollector, result map[string]interface{}) error {
+	topics, err := col.CollectSNS()
+	if err != nil {
+		fmt.Printf("Failed to gather SNS Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered SNS Topics across %d regions\n", len(topics))
+	result["sns"] = topics
+	return nil
+}
+
+func collectKinesis(col collector.AWSCollector, result map[string]interface{}) error {
+	inventory, err := col.CollectKinesis()
+	if err != nil {
+		fmt.Printf("Failed to gather Kinesis Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Kinesis and Firehose Streams across %d regions\n", len(inventory))
+	result["kinesis"] = inventory
+	return nil
+}
+
+func collectEventBridge(col collector.AWSCollector, result map[string]interface{}) error {
+	buses, err := col.CollectEventBridge()
+	if err != nil {
+		fmt.Printf("Failed to gather EventBridge Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered EventBridge Buses across %d regions\n", len(buses))
+	result["eventbridge"] = buses
+	return nil
+}
+
+func collectCloudFront(col collector.AWSCollector, result map[string]interface{}) error {
+	inventory, err := col.CollectCloudFront()
+	if err != nil {
+		fmt.Printf("Failed to gather CloudFront Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered %d CloudFront Distributions and %d CloudFront Web ACLs\n", len(inventory.Distributions), len(inventory.WebACLs))
+	result["cloudfront"] = inventory
+	return nil
+}
+
+func collectAPIGateway(col collector.AWSCollector, result map[string]interface{}) error {
+	inventory, err := col.CollectAPIGateway()
+	if err != nil {
+		fmt.Printf("Failed to gather API Gateway Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered API Gateway APIs across %d regions\n", len(inventory))
+	result["apigateway"] = inventory
+	return nil
+}
+
+func collectWAF(col collector.AWSCollector, result map[string]interface{}) error {
+	acls, err := col.CollectWAF()
+	if err != nil {
+		fmt.Printf("Failed to gather WAF Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered regional WAF Web ACLs across %d regions\n", len(acls))
+	result["waf"] = acls
+	return nil
+}
+
+func collectKMS(col collector.AWSCollector, result map[string]interface{}) error {
+	keys, err := col.CollectKMS()
+	if err != nil {
+		fmt.Printf("Failed to gather KMS Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered KMS Keys across %d regions\n", len(keys))
+	result["kms"] = keys
+	return nil
+}
+
+func collectSecretsManager(col collector.AWSCollector, result map[string]interface{}) error {
+	secrets, err := col.CollectSecretsManager()
+	if err != nil {
+		fmt.Printf("Failed to gather Secrets Manager Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Secrets Manager Secrets across %d regions\n", len(secrets))
+	result["secretsmanager"] = secrets
+	return nil
+}
+
+func collectACM(col collector.AWSCollector, result map[string]interface{}) error {
+	certificates, err := col.CollectACM()
+	if err != nil {
+		fmt.Printf("Failed to gather ACM Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered ACM Certificates across %d regions\n", len(certificates))
+	for region, c := range certificates {
+		for _, cert := range awslib.GetExpiringCertificates(c, time.Now(), time.Duration(certExpiryDays)*24*time.Hour) {
+			fmt.Printf("Certificate for %s in %s expires on %s (in use by %d resources)\n",
+				aws.StringValue(cert.DomainName), region, cert.NotAfter.Format("2006-01-02"), len(cert.InUseBy))
+		}
+	}
+	result["acm"] = certificates
+	return nil
+}
+
+func collectECR(col collector.AWSCollector, result map[string]interface{}) error {
+	repositories, err := col.CollectECR(ecrMaxImages)
+	if err != nil {
+		fmt.Printf("Failed to gather ECR Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered ECR Repositories across %d regions\n", len(repositories))
+	result["ecr"] = repositories
 	return nil
 }
 
+func collectAddresses(col collector.AWSCollector, result map[string]interface{}) error {
+	inventory, err := col.CollectAddresses()
+	if err != nil {
+		fmt.Printf("Failed to gather Address Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Elastic IPs, Network Interfaces and NAT Gateways across %d regions\n", len(inventory))
+	result["addresses"] = inventory
+	return nil
+}
+
+func collectFileSystems(col collector.AWSCollector, result map[string]interface{}) error {
+	inventory, err := col.CollectFileSystems()
+	if err != nil {
+		fmt.Printf("Failed to gather EFS and FSx Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered EFS and FSx File Systems across %d regions\n", len(inventory))
+	result["filesystems"] = inventory
+	return nil
+}
+
+func collectTaggedResources(col collector.AWSCollector, result map[string]interface{}) error {
+	resources, err := col.CollectTaggedResources()
+	if err != nil {
+		fmt.Printf("Failed to gather Tagged Resources: %v\n", err)
+		return err
+	}
+	uncovered := 0
+	for _, r := range resources {
+		for _, resource := range r {
+			if resource.Collector == "" {
+				uncovered++
+			}
+		}
+	}
+	fmt.Printf("Gathered Tagged Resources across %d regions, %d of them have no dedicated collector\n", len(resources), uncovered)
+	result["tagged_resources"] = resources
+	return nil
+}
+
+// exportIPIndex writes the IP to owner resource index of the dump to path as JSON
+func exportIPIndex(col collector.AWSCollector, result map[string]interface{}, path string) error {
+	// Most IPs are only known through network interfaces, gather those as well if they were filtered out
+	for service, collect := range map[string]func(collector.AWSCollector, map[string]interface{}) error{
+		"ec2":       collectEC2,
+		"addresses": collectAddresses,
+	} {
+		if _, ok := result[service]; ok {
+			continue
+		}
+		if err := collect(col, result); err != nil {
+			return err
+		}
+	}
+	jsonBytes, err := json.Marshal(buildResourceIndex(result).IPs())
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, jsonBytes, 0644)
+}
+
+// buildResourceIndex indexes the addresses of every resource already present in result.
+// Network interfaces are indexed first so that more specific owners take precedence
+func buildResourceIndex(result map[string]interface{}) awslib.ResourceIndex {
+	index := awslib.NewResourceIndex()
+	if inventory, ok := result["addresses"].(map[string]*collector.AddressInventory); ok {
+		for region, inv := range inventory {
+			index.AddNetworkInterfaces(region, inv.NetworkInterfaces)
+			index.AddAddresses(region, inv.ElasticIPs)
+			index.AddNatGateways(region, inv.NatGateways)
+		}
+	}
+	if instances, ok := result["ec2"].(map[string][]*ec2.Instance); ok {
+		for region, i := range instances {
+			index.AddInstances(region, i)
+		}
+	}
+	if inventory, ok := result["rds"].(map[string]*collector.RDSInventory); ok {
+		for region, inv := range inventory {
+			index.AddDBInstances(region, inv.Instances)
+			index.AddDBClusters(region, inv.Clusters)
+		}
+	}
+	if inventory, ok := result["elb"].(map[string]*collector.ELBInventory); ok {
+		for region, inv := range inventory {
+			index.AddLoadBalancers(region, inv.LoadBalancers)
+			index.AddClassicLoadBalancers(region, inv.ClassicLoadBalancers)
+		}
+	}
+	return index
+}
+
 func init() {
+	awsCmd.Long = fmt.Sprintf("Dump AWS inventory. Supported services: %s\n"+
+		"Regional services are keyed by region, global services (cloudfront, iam, route53) are not", strings.Join(awsServiceNames(), ", "))
 	awsCmd.PersistentFlags().StringVarP(&partition, "partition", "", "default", "Which partition of AWS to run for default/china")
 	awsCmd.PersistentFlags().BoolVarP(&ansibleEnable, "ansible", "a", false, "Create a an ansible inventory as well (only for EC2)")
 	awsCmd.PersistentFlags().StringVarP(&ansibleinv, "ansible_inv", "", "ansible.inv", "File to create the EC2 ansible inventory in")
 	awsCmd.PersistentFlags().BoolVarP(&ansiblePriv, "ansible_private", "", false, "Create Ansible Inventory with private DNS instead of public")
+	awsCmd.PersistentFlags().IntVarP(&certExpiryDays, "cert_expiry_days", "", 30, "Report ACM certificates expiring within this many days")
+	awsCmd.PersistentFlags().IntVarP(&ecrMaxImages, "ecr_max_images", "", 0, "Keep only this many of the most recent images per ECR repository, 0 keeps all")
+	awsCmd.PersistentFlags().StringVarP(&ipIndexPath, "ip_index", "", "", "Also export an index of every known IP to the resources owning it to this file")
+	awsCmd.PersistentFlags().StringVarP(&collectionMode, "mode", "", "describe", "Collection mode describe/tagging-api, tagging-api also lists every tagged resource under tagged_resources")
+	awsCmd.PersistentFlags().StringVarP(&source, "source", "", "api", "Where to collect from api/config, config only supports ec2 and rds and needs --config_aggregator")
+	awsCmd.PersistentFlags().StringVarP(&configAggregator, "config_aggregator", "", "", "Name of the AWS Config aggregator to query with --source config")
+	awsCmd.PersistentFlags().StringVarP(&configRegion, "config_region", "", "us-east-1", "Region hosting the AWS Config aggregator")
+	awsCmd.PersistentFlags().StringVarP(&ansibleGroupBy, "ansible_group_by", "", "region", "Group hosts in the Ansible Inventory by region/asg")
 	dumpCmd.AddCommand(awsCmd)
 }
@@ -0,0 +1,56 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"net"
+	"testing"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+// TestRoute53ResourceIndex checks that with a route53 only filter, records pointing to the Elastic IPs
+// of the account are resolved through the addresses gathered as a prerequisite instead of flagged as dangling
+func TestRoute53ResourceIndex(t *testing.T) {
+	result := map[string]interface{}{
+		"ec2": map[string][]*ec2.Instance{},
+		"rds": map[string]*collector.RDSInventory{},
+		"elb": map[string]*collector.ELBInventory{},
+		"addresses": map[string]*collector.AddressInventory{"us-east-1": {
+			ElasticIPs: []*ec2.Address{{AllocationId: aws.String("eipalloc-1"), PublicIp: aws.String("52.0.0.10")}},
+		}},
+	}
+	_, awsRange, _ := net.ParseCIDR("52.0.0.0/8")
+	zones := []*awslib.HostedZone{{
+		Zone: &route53.HostedZone{Id: aws.String("/hostedzone/Z1"), Name: aws.String("example.com.")},
+		RecordSets: []*route53.ResourceRecordSet{
+			{Name: aws.String("eip.example.com."), Type: aws.String("A"), ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("52.0.0.10")}}},
+			{Name: aws.String("released.example.com."), Type: aws.String("A"), ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("52.0.0.99")}}},
+		},
+	}}
+
+	links := awslib.ResolveRecords(zones, buildResourceIndex(result), []*net.IPNet{awsRange})
+	if len(links) != 2 {
+		t.Fatalf("Expected 2 record links, got %d", len(links))
+	}
+	if l := links[0]; l.Status != awslib.RecordResolved || len(l.Resources) != 1 || l.Resources[0].ID != "eipalloc-1" {
+		t.Errorf("Expected %s to resolve to eipalloc-1, got %s %v", l.Target, l.Status, l.Resources)
+	}
+	if l := links[1]; l.Status != awslib.RecordDangling {
+		t.Errorf("Expected %s to be dangling, got %s", l.Target, l.Status)
+	}
+}
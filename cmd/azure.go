@@ -0,0 +1,129 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/spf13/cobra"
+)
+
+// azureCmd represents the azure command
+var azureCmd = &cobra.Command{
+	Use:   "azure",
+	Short: "Dump Azure inventory for all supported services, or the one selected with --filter",
+	Run: func(cmd *cobra.Command, args []string) {
+		path := cmd.Flag("path").Value.String()
+		filter := cmd.Flag("filter").Value.String()
+		if !validateAzureFilter(filter) {
+			fmt.Printf("Invalid filter selected, please select a supported Azure service")
+			return
+		}
+
+		col, err := collector.NewAzureCollector(nil)
+		if err != nil {
+			fmt.Printf("Failed to create Azure collector: %v\n", err)
+			return
+		}
+
+		// Create a map per service
+		result := make(map[string]interface{})
+
+		if filter != "" {
+			if err := azureServices[filter](col, result); err != nil {
+				return
+			}
+		} else {
+			collectServices(azureServiceNames(), func(service string) error {
+				return azureServices[service](col, result)
+			}, result)
+		}
+		jsonBytes, err := json.Marshal(result)
+		if err != nil {
+			fmt.Printf("Error Marshalling JSON: %v\n", err)
+		}
+		err = writeDump(cmd, path, jsonBytes)
+		if err != nil {
+			fmt.Printf("Error writing inventory: %v\n", err)
+		}
+	},
+}
+
+// azureServices maps every supported --filter value to the function collecting it
+var azureServices = map[string]func(collector.AzureCollector, map[string]interface{}) error{
+	"vm":   collectAzureVMs,
+	"disk": collectAzureDisks,
+	"sql":  collectAzureSQL,
+}
+
+// azureServiceNames returns the supported Azure services in a stable order
+func azureServiceNames() []string {
+	var names []string
+	for name := range azureServices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validateAzureFilter(filter string) bool {
+	if filter == "" {
+		return true
+	}
+	_, ok := azureServices[filter]
+	return ok
+}
+
+func collectAzureVMs(col collector.AzureCollector, result map[string]interface{}) error {
+	vms, err := col.CollectVMs()
+	if err != nil {
+		fmt.Printf("Failed to gather Azure VM Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Azure VMs across %d subscriptions\n", len(vms))
+	result["vm"] = vms
+	return nil
+}
+
+func collectAzureDisks(col collector.AzureCollector, result map[string]interface{}) error {
+	disks, err := col.CollectDisks()
+	if err != nil {
+		fmt.Printf("Failed to gather Azure Disk Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Azure Managed Disks across %d subscriptions\n", len(disks))
+	result["disk"] = disks
+	return nil
+}
+
+func collectAzureSQL(col collector.AzureCollector, result map[string]interface{}) error {
+	inventory, err := col.CollectSQL()
+	if err != nil {
+		fmt.Printf("Failed to gather Azure SQL Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Azure SQL and PostgreSQL Servers across %d subscriptions\n", len(inventory))
+	result["sql"] = inventory
+	return nil
+}
+
+func init() {
+	azureCmd.Long = fmt.Sprintf("Dump Azure inventory. Supported services: %s\n"+
+		"Credentials are read from AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET, "+
+		"every enabled subscription of the service principal is collected and keyed by subscription ID", strings.Join(azureServiceNames(), ", "))
+	dumpCmd.AddCommand(azureCmd)
+}
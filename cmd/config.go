@@ -0,0 +1,297 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+// envPrefix prefixes the environment variable of every flag, e.g. CLOUDINVENTORY_ANSIBLE_INV for --ansible_inv
+const envPrefix = "CLOUDINVENTORY_"
+
+// defaultConfigFile is read from the home directory when no config file is given
+const defaultConfigFile = ".cloudinventory.yaml"
+
+var configPath string
+
+// settings holds the content of the config file. Top level keys set the flag of that name of every command,
+// nested sections named after subcommands (e.g. dump, then aws) only apply to them and take precedence
+type settings map[string]interface{}
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration applied to every command",
+	Long: "Every flag can also be set with a CLOUDINVENTORY_<FLAG> environment variable (e.g. CLOUDINVENTORY_ANSIBLE_INV)\n" +
+		"or in the config file (--config, CLOUDINVENTORY_CONFIG or ~/" + defaultConfigFile + ").\n" +
+		"A flag given on the command line wins over the environment, which wins over the config file, which wins over the default.\n" +
+		"In the config file, settings nested under a command (e.g. dump: aws: partition: china) win over top level ones",
+}
+
+// configShowCmd represents the config show command
+var configShowCmd = &cobra.Command{
+	Use:   "show [command]",
+	Short: "Print the effective configuration of a command (e.g. dump aws), or of every command, along with where each value comes from",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := rootCmd
+		if len(args) > 0 {
+			var err error
+			if target, _, err = rootCmd.Find(args); err != nil {
+				return err
+			}
+		}
+		conf, path, err := loadSettings()
+		if err != nil {
+			return err
+		}
+		if path != "" {
+			fmt.Printf("Config file: %s\n", path)
+		} else {
+			fmt.Printf("Config file: none\n")
+		}
+		for _, c := range runnableCommands(target) {
+			if c == cmd {
+				continue
+			}
+			fmt.Printf("\n%s\n", strings.TrimPrefix(c.CommandPath(), rootCmd.Name()+" "))
+			for _, f := range commandFlags(c) {
+				value, source := resolveFlag(c, f, conf, path)
+				if value == nil {
+					value = &f.DefValue
+				}
+				fmt.Printf("  --%-20s %-30s %s\n", f.Name, *value, source)
+			}
+		}
+		return nil
+	},
+}
+
+// applySettings sets every flag of cmd not given on the command line from the environment or the config file
+func applySettings(cmd *cobra.Command, args []string) error {
+	conf, path, err := loadSettings()
+	if err != nil {
+		return err
+	}
+	for _, f := range commandFlags(cmd) {
+		value, source := resolveFlag(cmd, f, conf, path)
+		if value == nil || f.Changed {
+			continue
+		}
+		// Setting the value directly leaves the flag unchanged, as if the default had been overridden
+		if err := f.Value.Set(*value); err != nil {
+			return fmt.Errorf("Invalid value %q for --%s from %s: %v", *value, f.Name, source, err)
+		}
+	}
+	return nil
+}
+
+// resolveFlag returns the value of a flag of cmd by precedence, and its source. Returns a nil value for flags left to their default
+func resolveFlag(cmd *cobra.Command, f *pflag.Flag, conf settings, path string) (*string, string) {
+	if f.Changed {
+		value := f.Value.String()
+		return &value, "flag"
+	}
+	env := flagEnv(f.Name)
+	if value, ok := os.LookupEnv(env); ok {
+		return &value, "env " + env
+	}
+	// The most specific section of the config wins
+	sections := commandSections(cmd)
+	for i := len(sections); i >= 0; i-- {
+		section := conf
+		for _, name := range sections[:i] {
+			nested, ok := section[name].(map[string]interface{})
+			if !ok {
+				section = nil
+				break
+			}
+			section = nested
+		}
+		raw, ok := section[f.Name]
+		if !ok {
+			continue
+		}
+		value := settingString(raw)
+		return &value, fmt.Sprintf("config %s (%s)", path, strings.Join(append(sections[:i:i], f.Name), "."))
+	}
+	return nil, "default"
+}
+
+// flagEnv returns the environment variable of a flag
+func flagEnv(name string) string {
+	return envPrefix + strings.ToUpper(strings.Replace(name, "-", "_", -1))
+}
+
+// commandSections returns the path of cmd below the root command, e.g. [dump aws]
+func commandSections(cmd *cobra.Command) []string {
+	var sections []string
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		sections = append([]string{c.Name()}, sections...)
+	}
+	return sections
+}
+
+// commandFlags returns every flag of cmd, including the inherited ones, by name. --help and --config are left out
+func commandFlags(cmd *cobra.Command) []*pflag.Flag {
+	var flags []*pflag.Flag
+	seen := make(map[string]bool)
+	visit := func(f *pflag.Flag) {
+		if f.Name == "help" || f.Name == "config" || seen[f.Name] {
+			return
+		}
+		seen[f.Name] = true
+		flags = append(flags, f)
+	}
+	cmd.LocalFlags().VisitAll(visit)
+	cmd.InheritedFlags().VisitAll(visit)
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
+
+// runnableCommands returns cmd and every command below it which can be run
+func runnableCommands(cmd *cobra.Command) []*cobra.Command {
+	var commands []*cobra.Command
+	if cmd.Runnable() {
+		commands = append(commands, cmd)
+	}
+	for _, c := range cmd.Commands() {
+		if c.Name() == "help" {
+			continue
+		}
+		commands = append(commands, runnableCommands(c)...)
+	}
+	return commands
+}
+
+// settingString returns a config value as given on the command line, lists are comma separated
+func settingString(raw interface{}) string {
+	switch v := raw.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			values = append(values, settingString(item))
+		}
+		return strings.Join(values, ",")
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// settingsPath returns the config file to read: --config, CLOUDINVENTORY_CONFIG, or ~/.cloudinventory.yaml when it exists.
+// Returns an empty path when there is none
+func settingsPath() string {
+	if configPath != "" {
+		return configPath
+	}
+	if path := os.Getenv(envPrefix + "CONFIG"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	path := filepath.Join(home, defaultConfigFile)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// loadSettings reads and checks the config file, returning the path it was read from
+func loadSettings() (settings, string, error) {
+	path := settingsPath()
+	if path == "" {
+		return nil, "", nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, path, err
+	}
+	var conf settings
+	if err := yaml.Unmarshal(data, &conf); err != nil {
+		return nil, path, fmt.Errorf("Failed to parse %s: %v", path, err)
+	}
+	if err := checkSettings(rootCmd, conf, nil); err != nil {
+		return nil, path, fmt.Errorf("Invalid config %s: %v", path, err)
+	}
+	return conf, path, nil
+}
+
+// checkSettings reports unknown keys of a config section: every key has to be a flag of cmd or of a command below it,
+// or a section named after a subcommand. The providers list of dump all is only allowed at the top level
+func checkSettings(cmd *cobra.Command, section map[string]interface{}, keys []string) error {
+	for key, value := range section {
+		if len(keys) == 0 && key == "providers" {
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			sub := subCommand(cmd, key)
+			if sub == nil {
+				return fmt.Errorf("Unknown command section %s", strings.Join(append(keys, key), "."))
+			}
+			if err := checkSettings(sub, nested, append(keys, key)); err != nil {
+				return err
+			}
+			continue
+		}
+		if !hasFlag(cmd, key) {
+			return fmt.Errorf("Unknown setting %s", strings.Join(append(keys, key), "."))
+		}
+	}
+	return nil
+}
+
+// subCommand returns the direct subcommand of cmd with the given name
+func subCommand(cmd *cobra.Command, name string) *cobra.Command {
+	for _, c := range cmd.Commands() {
+		if c.Name() == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// hasFlag reports whether cmd or any command below it has a flag of the given name
+func hasFlag(cmd *cobra.Command, name string) bool {
+	for _, c := range runnableCommands(cmd) {
+		for _, f := range commandFlags(c) {
+			if f.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "", "", "Config file setting flags, CLOUDINVENTORY_CONFIG or ~/"+defaultConfigFile+" if empty")
+	rootCmd.PersistentPreRunE = applySettings
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
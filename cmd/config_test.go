@@ -0,0 +1,69 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// TestResolveFlag checks that flags win over the environment, which wins over the most specific config section
+func TestResolveFlag(t *testing.T) {
+	root := &cobra.Command{Use: "root"}
+	parent := &cobra.Command{Use: "dump"}
+	child := &cobra.Command{Use: "aws", Run: func(cmd *cobra.Command, args []string) {}}
+	root.AddCommand(parent)
+	parent.AddCommand(child)
+	parent.PersistentFlags().String("path", "default.json", "")
+	child.PersistentFlags().String("partition", "default", "")
+	child.PersistentFlags().StringSlice("regions", nil, "")
+	child.PersistentFlags().Int("max", 0, "")
+
+	conf := settings{
+		"path":      "top.json",
+		"partition": "top",
+		"max":       float64(3),
+		"dump": map[string]interface{}{
+			"aws": map[string]interface{}{"partition": "china", "regions": []interface{}{"a", "b"}},
+		},
+	}
+	if err := checkSettings(root, conf, nil); err != nil {
+		t.Fatalf("Unexpected invalid settings: %v", err)
+	}
+	os.Setenv("CLOUDINVENTORY_PATH", "env.json")
+	defer os.Unsetenv("CLOUDINVENTORY_PATH")
+	for name, expected := range map[string]string{"path": "env.json", "partition": "china", "regions": "a,b", "max": "3"} {
+		value, source := resolveFlag(child, child.Flags().Lookup(name), conf, "test.yaml")
+		if value == nil || *value != expected {
+			t.Errorf("Unexpected %s from %s: %v", name, source, value)
+		}
+	}
+	if err := child.ParseFlags([]string{"--partition", "flag"}); err != nil {
+		t.Fatal(err)
+	}
+	if value, source := resolveFlag(child, child.Flags().Lookup("partition"), conf, "test.yaml"); *value != "flag" || source != "flag" {
+		t.Errorf("Expected the flag to win, got %s from %s", *value, source)
+	}
+
+	for _, invalid := range []settings{
+		{"unknown": "x"},
+		{"dump": map[string]interface{}{"gcp": map[string]interface{}{}}},
+		{"dump": map[string]interface{}{"providers": []interface{}{}}},
+	} {
+		if err := checkSettings(root, invalid, nil); err == nil {
+			t.Errorf("Expected %v to be invalid", invalid)
+		}
+	}
+}
@@ -13,9 +13,21 @@ governing permissions and limitations under the License.
 package cmd
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/adobe/cloudinventory/history"
+	"github.com/adobe/cloudinventory/sink"
 	"github.com/spf13/cobra"
 )
 
+var (
+	sseMode     string
+	sseKMSKeyID string
+	s3Endpoint  string
+)
+
 // dumpCmd represents the dump command
 var dumpCmd = &cobra.Command{
 	Use:   "dump",
@@ -24,7 +36,58 @@ var dumpCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(dumpCmd)
-	dumpCmd.PersistentFlags().StringP("filter", "f", "", "limit dump to a particular cloud service, e.g ec2/rds")
-	dumpCmd.PersistentFlags().StringP("path", "p", "cloudinventory.json", "file path to dump the inventory in")
+	dumpCmd.PersistentFlags().StringP("filter", "f", "", "limit dump to a particular cloud service, e.g ec2/rds/ebs")
+	dumpCmd.PersistentFlags().StringP("path", "p", "cloudinventory.json", "file path or s3://, gs://, azblob:// URL to dump the inventory in, {{date}} and {{time}} are replaced with the current UTC date and time")
+	dumpCmd.PersistentFlags().StringVar(&sseMode, "sse", "", "Server side encryption of s3:// paths AES256/aws:kms, the bucket default if empty")
+	dumpCmd.PersistentFlags().StringVar(&sseKMSKeyID, "sse_kms_key_id", "", "KMS key encrypting s3:// paths with --sse aws:kms, the AWS managed key if empty")
+	dumpCmd.PersistentFlags().StringVar(&s3Endpoint, "s3_endpoint", "", "Endpoint of an S3 compatible store to write s3:// paths to instead of AWS")
+
+}
+
+// writeDump writes data, the inventory dumped by cmd, to path once its date placeholders are expanded, through the sink of its scheme.
+// The inventory is also saved as a snapshot of the history store when one is set
+func writeDump(cmd *cobra.Command, path string, data []byte) error {
+	now := time.Now()
+	if historyDir != "" {
+		source := cmd.Name()
+		if filter := cmd.Flag("filter").Value.String(); filter != "" {
+			source += "-" + filter
+		}
+		if snap, err := history.NewStore(historyDir).Save(source, now, data); err != nil {
+			fmt.Printf("Error saving history snapshot: %v\n", err)
+		} else {
+			fmt.Printf("Saved history snapshot %s\n", snap.Path)
+		}
+	}
+	location, err := sink.Expand(path, now)
+	if err != nil {
+		return err
+	}
+	s, err := sink.New(location, sink.Options{
+		ServerSideEncryption: sseMode,
+		KMSKeyID:             sseKMSKeyID,
+		S3Endpoint:           s3Endpoint,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Dumping to %s\n", s)
+	return s.Write(data)
+}
 
+// collectServices runs collect for every service in order, carrying on past failing services so that the others are
+// still dumped. The error of every failing service is kept in result under errors, keyed by service
+func collectServices(services []string, collect func(service string) error, result map[string]interface{}) {
+	errors := make(map[string]string)
+	var failed []string
+	for _, service := range services {
+		if err := collect(service); err != nil {
+			errors[service] = err.Error()
+			failed = append(failed, service)
+		}
+	}
+	if len(failed) > 0 {
+		fmt.Printf("Failed to gather %d services, dumping the others: %s\n", len(failed), strings.Join(failed, ", "))
+		result["errors"] = errors
+	}
 }
@@ -0,0 +1,128 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/adobe/cloudinventory/gcplib"
+	"github.com/spf13/cobra"
+)
+
+var gcpProjects []string
+var gcpCredentials string
+
+// gcpCmd represents the gcp command
+var gcpCmd = &cobra.Command{
+	Use:   "gcp",
+	Short: "Dump Google Cloud inventory for all supported services, or the one selected with --filter",
+	Run: func(cmd *cobra.Command, args []string) {
+		path := cmd.Flag("path").Value.String()
+		filter := cmd.Flag("filter").Value.String()
+		if !validateGCPFilter(filter) {
+			fmt.Printf("Invalid filter selected, please select a supported Google Cloud service")
+			return
+		}
+
+		client, err := gcplib.NewClient(context.Background(), gcpCredentials)
+		if err != nil {
+			fmt.Printf("Failed to create Google Cloud client: %v\n", err)
+			return
+		}
+		col, err := collector.NewGCPCollector(client, gcpProjects)
+		if err != nil {
+			fmt.Printf("Failed to create Google Cloud collector: %v\n", err)
+			return
+		}
+
+		// Create a map per service
+		result := make(map[string]interface{})
+
+		if filter != "" {
+			if err := gcpServices[filter](col, result); err != nil {
+				return
+			}
+		} else {
+			collectServices(gcpServiceNames(), func(service string) error {
+				return gcpServices[service](col, result)
+			}, result)
+		}
+		jsonBytes, err := json.Marshal(result)
+		if err != nil {
+			fmt.Printf("Error Marshalling JSON: %v\n", err)
+		}
+		err = writeDump(cmd, path, jsonBytes)
+		if err != nil {
+			fmt.Printf("Error writing inventory: %v\n", err)
+		}
+	},
+}
+
+// gcpServices maps every supported --filter value to the function collecting it
+var gcpServices = map[string]func(collector.GCPCollector, map[string]interface{}) error{
+	"compute": collectGCPCompute,
+	"sql":     collectGCPSQL,
+}
+
+// gcpServiceNames returns the supported Google Cloud services in a stable order
+func gcpServiceNames() []string {
+	var names []string
+	for name := range gcpServices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validateGCPFilter(filter string) bool {
+	if filter == "" {
+		return true
+	}
+	_, ok := gcpServices[filter]
+	return ok
+}
+
+func collectGCPCompute(col collector.GCPCollector, result map[string]interface{}) error {
+	instances, err := col.CollectComputeInstances()
+	if err != nil {
+		fmt.Printf("Failed to gather Compute Engine Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Compute Engine Instances across %d projects\n", len(instances))
+	result["compute"] = instances
+	return nil
+}
+
+func collectGCPSQL(col collector.GCPCollector, result map[string]interface{}) error {
+	instances, err := col.CollectSQLInstances()
+	if err != nil {
+		fmt.Printf("Failed to gather Cloud SQL Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Cloud SQL Instances across %d projects\n", len(instances))
+	result["sql"] = instances
+	return nil
+}
+
+func init() {
+	gcpCmd.Long = fmt.Sprintf("Dump Google Cloud inventory. Supported services: %s\n"+
+		"Inventories are keyed by project, then by zone (compute) or region (sql)", strings.Join(gcpServiceNames(), ", "))
+	gcpCmd.PersistentFlags().StringSliceVarP(&gcpProjects, "projects", "", nil, "Projects to collect, every active project the credentials can list if empty")
+	gcpCmd.PersistentFlags().StringVarP(&gcpCredentials, "credentials", "", "", "Service account JSON key file, Application Default Credentials are used if empty")
+	dumpCmd.AddCommand(gcpCmd)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/adobe/cloudinventory/history"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyDir    string
+	historySource string
+	historyAt     string
+)
+
+// historySnapshot is the inventory of a source as of a given time, as printed by history show
+type historySnapshot struct {
+	Time      time.Time   `json:"time"`
+	Inventory interface{} `json:"inventory"`
+}
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Query the inventory snapshots saved by every dump with --history_dir",
+	Long: "Every dump run with --history_dir is saved in that directory as a snapshot of its command, e.g. aws,\n" +
+		"or of its command and filter, e.g. aws-ec2. Resources are followed across the snapshots of each source",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := applySettings(cmd, args); err != nil {
+			return err
+		}
+		if historyDir == "" {
+			return fmt.Errorf("No snapshot store, set --history_dir")
+		}
+		return nil
+	},
+}
+
+// historyListCmd represents the history list command
+var historyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the saved snapshots, oldest first",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snapshots, err := history.NewStore(historyDir).List()
+		if err != nil {
+			return err
+		}
+		for _, snap := range snapshots {
+			if historySource != "" && snap.Source != historySource {
+				continue
+			}
+			fmt.Printf("%s  %-20s %s\n", snap.Time.Format(time.RFC3339), snap.Source, snap.Path)
+		}
+		return nil
+	},
+}
+
+// historyShowCmd represents the history show command
+var historyShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the inventory of every source as of a given time, as JSON keyed by source",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		at, err := parseHistoryTime(historyAt)
+		if err != nil {
+			return err
+		}
+		store := history.NewStore(historyDir)
+		snapshots, err := store.Latest(at)
+		if err != nil {
+			return err
+		}
+		result := make(map[string]*historySnapshot)
+		for _, snap := range snapshots {
+			if historySource != "" && snap.Source != historySource {
+				continue
+			}
+			inventory, err := store.Load(snap)
+			if err != nil {
+				return err
+			}
+			result[snap.Source] = &historySnapshot{Time: snap.Time, Inventory: inventory}
+		}
+		if len(result) == 0 {
+			return fmt.Errorf("No snapshot taken at or before %s", at.Format(time.RFC3339))
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	},
+}
+
+// historyResourceCmd represents the history resource command
+var historyResourceCmd = &cobra.Command{
+	Use:   "resource <id>",
+	Short: "Show when a resource was first and last seen, and how it changed across snapshots",
+	Long: "Show when a resource was first and last seen, and how it changed across snapshots.\n" +
+		"Resources are found by their ID, e.g. an EC2 instance ID, an ARN, an Azure resource ID or a Kubernetes UID",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l, err := history.NewStore(historyDir).Resource(args[0])
+		if err != nil {
+			return err
+		}
+		if l == nil {
+			return fmt.Errorf("Resource %s is not in any snapshot", args[0])
+		}
+		fmt.Printf("%s\n", l.ID)
+		fmt.Printf("First seen: %s\n", l.FirstSeen.Format(time.RFC3339))
+		fmt.Printf("Last seen:  %s\n", l.LastSeen.Format(time.RFC3339))
+		for _, e := range l.Events {
+			fmt.Printf("\n%s  %-8s %s %s\n", e.Time.Format(time.RFC3339), e.Kind, e.Source, e.Location)
+			for _, c := range e.Changes {
+				switch {
+				case c.Old == "":
+					fmt.Printf("  + %s: %s\n", c.Attribute, c.New)
+				case c.New == "":
+					fmt.Printf("  - %s: %s\n", c.Attribute, c.Old)
+				default:
+					fmt.Printf("  ~ %s: %s -> %s\n", c.Attribute, c.Old, c.New)
+				}
+			}
+		}
+		return nil
+	},
+}
+
+// parseHistoryTime parses an RFC 3339 time, or a date which stands for the end of that UTC day. Empty is now
+func parseHistoryTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now(), nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return t, fmt.Errorf("Invalid time %s, expected a date such as 2026-09-01 or an RFC 3339 time", value)
+	}
+	return t, nil
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyListCmd)
+	historyCmd.AddCommand(historyShowCmd)
+	historyCmd.AddCommand(historyResourceCmd)
+	rootCmd.PersistentFlags().StringVar(&historyDir, "history_dir", "", "Directory of the snapshot store, every dump is also saved in it when set")
+	historyListCmd.Flags().StringVar(&historySource, "snapshot_source", "", "Only list the snapshots of this source, e.g. aws or aws-ec2")
+	historyShowCmd.Flags().StringVar(&historySource, "snapshot_source", "", "Only show the snapshot of this source, e.g. aws or aws-ec2")
+	historyShowCmd.Flags().StringVar(&historyAt, "at", "", "Date (end of that UTC day) or RFC 3339 time to show the inventory as of, now if empty")
+}
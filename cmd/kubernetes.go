@@ -0,0 +1,112 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/adobe/cloudinventory/k8slib"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/spf13/cobra"
+)
+
+var kubeconfig string
+var kubeContexts []string
+var kubeAWSDump string
+
+// kubernetesCmd represents the kubernetes command
+var kubernetesCmd = &cobra.Command{
+	Use:   "kubernetes",
+	Short: "Dump Kubernetes inventory for one or more kubeconfig contexts",
+	Long: "Dump Kubernetes inventory: nodes, namespaces, workloads (deployments, statefulsets, daemonsets, cronjobs), " +
+		"LoadBalancer services and ingresses, keyed by context under clusters\n" +
+		"Nodes are linked to their EC2 instances under ec2_nodes when an AWS dump is given with --aws_dump",
+	Run: func(cmd *cobra.Command, args []string) {
+		path := cmd.Flag("path").Value.String()
+		if cmd.Flag("filter").Value.String() != "" {
+			fmt.Printf("Filtering is not supported for Kubernetes, every resource kind is collected")
+			return
+		}
+
+		var instances map[string][]*ec2.Instance
+		if kubeAWSDump != "" {
+			var err error
+			if instances, err = loadEC2Dump(kubeAWSDump); err != nil {
+				fmt.Printf("Failed to load AWS dump: %v\n", err)
+				return
+			}
+		}
+
+		col, err := collector.NewKubernetesCollector(kubeconfig, kubeContexts)
+		if err != nil {
+			fmt.Printf("Failed to create Kubernetes collector: %v\n", err)
+			return
+		}
+		clusters, err := col.CollectClusters()
+		if err != nil {
+			fmt.Printf("Failed to gather Kubernetes Data: %v\n", err)
+			return
+		}
+		fmt.Printf("Gathered Kubernetes inventory across %d clusters\n", len(clusters))
+
+		result := map[string]interface{}{"clusters": clusters}
+		if instances != nil {
+			links := make(map[string]*k8slib.NodeInstances)
+			for context, inv := range clusters {
+				if l := k8slib.LinkNodeInstances(inv.Nodes, instances); l != nil {
+					links[context] = l
+					if len(l.Unmatched) > 0 {
+						fmt.Printf("%d nodes of %s are not in the AWS dump: %v\n", len(l.Unmatched), context, l.Unmatched)
+					}
+				}
+			}
+			result["ec2_nodes"] = links
+		}
+		jsonBytes, err := json.Marshal(result)
+		if err != nil {
+			fmt.Printf("Error Marshalling JSON: %v\n", err)
+		}
+		err = writeDump(cmd, path, jsonBytes)
+		if err != nil {
+			fmt.Printf("Error writing inventory: %v\n", err)
+		}
+	},
+}
+
+// loadEC2Dump returns the EC2 instances, keyed by region, of a dump written by dump aws
+func loadEC2Dump(path string) (map[string][]*ec2.Instance, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var dump struct {
+		EC2 map[string][]*ec2.Instance `json:"ec2"`
+	}
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return nil, err
+	}
+	if dump.EC2 == nil {
+		return nil, fmt.Errorf("%s holds no EC2 inventory", path)
+	}
+	return dump.EC2, nil
+}
+
+func init() {
+	kubernetesCmd.PersistentFlags().StringVarP(&kubeconfig, "kubeconfig", "", "", "Kubeconfig file, KUBECONFIG or ~/.kube/config if empty")
+	kubernetesCmd.PersistentFlags().StringSliceVarP(&kubeContexts, "contexts", "", nil, "Kubeconfig contexts to collect, the current context if empty")
+	kubernetesCmd.PersistentFlags().StringVarP(&kubeAWSDump, "aws_dump", "", "", "AWS dump to link nodes to their EC2 instances")
+	dumpCmd.AddCommand(kubernetesCmd)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/adobe/cloudinventory/ansible"
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/adobe/cloudinventory/openstacklib"
+	"github.com/spf13/cobra"
+)
+
+var openstackCloud string
+var openstackCloudsFile string
+
+// newProviderCmd returns the dump command of a provider implementing collector.Provider.
+// The provider is only created when the command runs, as it reads its credentials from the environment
+func newProviderCmd(name, title, credentials string, services []string, newProvider func() (collector.Provider, error)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   name,
+		Short: fmt.Sprintf("Dump %s inventory for all supported services, or the one selected with --filter", title),
+		Long: fmt.Sprintf("Dump %s inventory. Supported services: %s\n"+
+			"Credentials are read from %s", title, strings.Join(services, ", "), credentials),
+		Run: func(cmd *cobra.Command, args []string) {
+			path := cmd.Flag("path").Value.String()
+			filter := cmd.Flag("filter").Value.String()
+			if filter != "" && !stringInSlice(filter, services) {
+				fmt.Printf("Invalid filter selected, please select a supported %s service", title)
+				return
+			}
+
+			p, err := newProvider()
+			if err != nil {
+				fmt.Printf("Failed to create %s collector: %v\n", title, err)
+				return
+			}
+			if closer, ok := p.(io.Closer); ok {
+				defer closer.Close()
+			}
+
+			// Create a map per service
+			result := make(map[string]interface{})
+
+			collect := func(service string) error {
+				inventory, err := p.Collect(service)
+				if err != nil {
+					fmt.Printf("Failed to gather %s %s Data: %v\n", title, service, err)
+					return err
+				}
+				fmt.Printf("Gathered %s %s\n", title, service)
+				result[service] = inventory
+				return nil
+			}
+			if filter != "" {
+				if err := collect(filter); err != nil {
+					return
+				}
+			} else {
+				collectServices(p.Services(), collect, result)
+			}
+			jsonBytes, err := json.Marshal(result)
+			if err != nil {
+				fmt.Printf("Error Marshalling JSON: %v\n", err)
+			}
+			err = writeDump(cmd, path, jsonBytes)
+			if err != nil {
+				fmt.Printf("Error writing inventory: %v\n", err)
+			}
+
+			if ansibleEnable {
+				writeHostInventory(p, result)
+			}
+		},
+	}
+	cmd.PersistentFlags().BoolVarP(&ansibleEnable, "ansible", "a", false, "Create an ansible inventory as well (hosts grouped by region)")
+	cmd.PersistentFlags().StringVarP(&ansibleinv, "ansible_inv", "", "ansible.inv", "File to create the ansible inventory in")
+	cmd.PersistentFlags().BoolVarP(&ansiblePriv, "ansible_private", "", false, "Create Ansible Inventory with private IPs instead of public")
+	return cmd
+}
+
+// writeHostInventory builds the ansible inventory of the hosts of a provider and writes it to --ansible_inv
+func writeHostInventory(p collector.Provider, result map[string]interface{}) {
+	fmt.Printf("Building Inventory for Ansible at: %s", ansibleinv)
+	hosts, err := p.Hosts(result)
+	if err != nil {
+		fmt.Printf("Error while gathering hosts: %v\n", err)
+		return
+	}
+	var ansibleHosts []*ansible.Host
+	for _, h := range hosts {
+		ansibleHosts = append(ansibleHosts, &ansible.Host{Name: h.Name, Region: h.Region, PublicIP: h.PublicIP, PrivateIP: h.PrivateIP})
+	}
+	ansinv, err := ansible.BuildHostInventory(ansibleHosts, ansiblePriv)
+	if err != nil {
+		fmt.Printf("Error while building Ansible Inventory: %v\n", err)
+	}
+	err = ioutil.WriteFile(ansibleinv, []byte(ansinv), 0644)
+	if err != nil {
+		fmt.Printf("Error writing to Ansible Inventory file: %v\n", err)
+	}
+}
+
+// stringInSlice reports whether s is one of list
+func stringInSlice(s string, list []string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	dumpCmd.AddCommand(newProviderCmd("digitalocean", "DigitalOcean", "DIGITALOCEAN_TOKEN", collector.DigitalOceanCollector{}.Services(), func() (collector.Provider, error) {
+		return collector.NewDigitalOceanCollector(nil)
+	}))
+	dumpCmd.AddCommand(newProviderCmd("linode", "Linode", "LINODE_TOKEN", collector.LinodeCollector{}.Services(), func() (collector.Provider, error) {
+		return collector.NewLinodeCollector(nil)
+	}))
+	dumpCmd.AddCommand(newProviderCmd("hetzner", "Hetzner Cloud", "HCLOUD_TOKEN", collector.HetznerCollector{}.Services(), func() (collector.Provider, error) {
+		return collector.NewHetznerCollector(nil)
+	}))
+
+	openstackCmd := newProviderCmd("openstack", "OpenStack", "clouds.yaml (--clouds_file, or the standard locations)", collector.OpenStackCollector{}.Services(), func() (collector.Provider, error) {
+		cloud, err := openstacklib.LoadCloud(openstackCloudsFile, openstackCloud)
+		if err != nil {
+			return nil, err
+		}
+		client, err := openstacklib.NewClient(cloud)
+		if err != nil {
+			return nil, err
+		}
+		return collector.NewOpenStackCollector(client)
+	})
+	openstackCmd.PersistentFlags().StringVarP(&openstackCloud, "cloud", "", "", "Cloud of clouds.yaml to collect, OS_CLOUD or the only cloud of the file if empty")
+	openstackCmd.PersistentFlags().StringVarP(&openstackCloudsFile, "clouds_file", "", "", "clouds.yaml to read, OS_CLIENT_CONFIG_FILE or the standard locations if empty")
+	dumpCmd.AddCommand(openstackCmd)
+	dumpCmd.AddCommand(newProviderCmd("vsphere", "vSphere", "GOVC_URL, GOVC_USERNAME, GOVC_PASSWORD and GOVC_INSECURE", collector.VSphereCollector{}.Services(), func() (collector.Provider, error) {
+		return collector.NewVSphereCollector(nil)
+	}))
+}
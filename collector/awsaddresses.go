@@ -0,0 +1,78 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// AddressInventory holds the Elastic IPs, network interfaces and NAT gateways of a single region.
+// IdleElasticIPs lists the public IPs of Elastic IPs not associated with anything
+type AddressInventory struct {
+	ElasticIPs        []*ec2.Address
+	NetworkInterfaces []*ec2.NetworkInterface
+	NatGateways       []*ec2.NatGateway
+	IdleElasticIPs    []string
+}
+
+// CollectAddresses returns a concurrently collected address inventory for all the regions
+func (col AWSCollector) CollectAddresses() (map[string]*AddressInventory, error) {
+	chunks, err := col.collectPerRegion("Addresses", func(sess *session.Session) (interface{}, error) {
+		inv, err := CollectAddressesPerSession(sess)
+		// Ignore regions with no addresses
+		if inv == nil {
+			return nil, err
+		}
+		return inv, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	inventory := make(map[string]*AddressInventory)
+	for region, chunk := range chunks {
+		inventory[region] = chunk.(*AddressInventory)
+	}
+	return inventory, nil
+}
+
+// CollectAddressesPerSession returns an address inventory for a given session.
+// Returns nil if the region holds no Elastic IPs, network interfaces or NAT gateways
+func CollectAddressesPerSession(sess *session.Session) (*AddressInventory, error) {
+	addresses, err := awslib.GetAllAddresses(sess)
+	if err != nil {
+		return nil, err
+	}
+	interfaces, err := awslib.GetAllNetworkInterfaces(sess)
+	if err != nil {
+		return nil, err
+	}
+	gateways, err := awslib.GetAllNatGateways(sess)
+	if err != nil {
+		return nil, err
+	}
+	if len(addresses) == 0 && len(interfaces) == 0 && len(gateways) == 0 {
+		return nil, nil
+	}
+	inv := &AddressInventory{
+		ElasticIPs:        addresses,
+		NetworkInterfaces: interfaces,
+		NatGateways:       gateways,
+	}
+	for _, a := range awslib.GetIdleAddresses(addresses) {
+		inv.IdleElasticIPs = append(inv.IdleElasticIPs, aws.StringValue(a.PublicIp))
+	}
+	return inv, nil
+}
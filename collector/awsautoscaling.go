@@ -0,0 +1,74 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+)
+
+// AutoScalingInventory holds the Auto Scaling groups, launch configurations and launch templates of a single region.
+// InstanceGroups maps the ID of every EC2 instance managed by a group to the name of that group,
+// AnnotateAWSOwners sets it as the AutoScalingGroup of the instances of a dump
+type AutoScalingInventory struct {
+	Groups               []*autoscaling.Group
+	LaunchConfigurations []*autoscaling.LaunchConfiguration
+	LaunchTemplates      []*awslib.LaunchTemplate
+	InstanceGroups       map[string]string
+}
+
+// CollectAutoScaling returns a concurrently collected Auto Scaling inventory for all the regions
+func (col AWSCollector) CollectAutoScaling() (map[string]*AutoScalingInventory, error) {
+	chunks, err := col.collectPerRegion("AutoScaling", func(sess *session.Session) (interface{}, error) {
+		inv, err := CollectAutoScalingPerSession(sess)
+		// Ignore regions with no groups, configurations or templates
+		if inv == nil {
+			return nil, err
+		}
+		return inv, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	inventory := make(map[string]*AutoScalingInventory)
+	for region, chunk := range chunks {
+		inventory[region] = chunk.(*AutoScalingInventory)
+	}
+	return inventory, nil
+}
+
+// CollectAutoScalingPerSession returns an Auto Scaling inventory for a given session
+func CollectAutoScalingPerSession(sess *session.Session) (*AutoScalingInventory, error) {
+	groups, err := awslib.GetAllAutoScalingGroups(sess)
+	if err != nil {
+		return nil, err
+	}
+	configurations, err := awslib.GetAllLaunchConfigurations(sess)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := awslib.GetAllLaunchTemplates(sess)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 && len(configurations) == 0 && len(templates) == 0 {
+		return nil, nil
+	}
+	return &AutoScalingInventory{
+		Groups:               groups,
+		LaunchConfigurations: configurations,
+		LaunchTemplates:      templates,
+		InstanceGroups:       awslib.GetInstanceGroups(groups),
+	}, nil
+}
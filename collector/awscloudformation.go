@@ -0,0 +1,59 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// CloudFormationInventory holds the CloudFormation stacks of a single region.
+// ResourceOwners maps the type, then the physical ID of every stack managed resource to its owning stack,
+// resources of the region missing from it were not created through CloudFormation.
+// AnnotateAWSOwners sets it as the CloudFormationStack of the resources of a dump
+type CloudFormationInventory struct {
+	Stacks         []*awslib.Stack
+	ResourceOwners map[string]map[string]*awslib.StackOwner
+}
+
+// CollectCloudFormation returns a concurrently collected CloudFormation inventory for all the regions
+func (col AWSCollector) CollectCloudFormation() (map[string]*CloudFormationInventory, error) {
+	chunks, err := col.collectPerRegion("CloudFormation", func(sess *session.Session) (interface{}, error) {
+		inv, err := CollectCloudFormationPerSession(sess)
+		// Ignore regions with no stacks
+		if inv == nil {
+			return nil, err
+		}
+		return inv, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	inventory := make(map[string]*CloudFormationInventory)
+	for region, chunk := range chunks {
+		inventory[region] = chunk.(*CloudFormationInventory)
+	}
+	return inventory, nil
+}
+
+// CollectCloudFormationPerSession returns a CloudFormation inventory for a given session
+func CollectCloudFormationPerSession(sess *session.Session) (*CloudFormationInventory, error) {
+	stacks, err := awslib.GetAllStacks(sess)
+	if err != nil {
+		return nil, err
+	}
+	if len(stacks) == 0 {
+		return nil, nil
+	}
+	return &CloudFormationInventory{Stacks: stacks, ResourceOwners: awslib.GetStackOwnership(stacks)}, nil
+}
@@ -27,7 +27,7 @@ import (
 // NewAWSCollector returns an AWSCollector with initialized sessions.
 // Uses supplied credentials, Standard Environment variables if creds not specified
 func NewAWSCollector(partition string, creds *credentials.Credentials) (AWSCollector, error) {
-	var col AWSCollector
+	col := AWSCollector{partition: strings.ToLower(partition)}
 	regions := col.getRegions(partition)
 	if regions == nil {
 		return col, fmt.Errorf("Invalid Region Selected")
@@ -43,9 +43,48 @@ func NewAWSCollector(partition string, creds *credentials.Credentials) (AWSColle
 	return col, nil
 }
 
+// NewAWSCollectorForRegions returns an AWSCollector restricted to the given regions of the partition, or every region if none are given.
+// Global services are still collected through the global region of the partition
+func NewAWSCollectorForRegions(partition string, regions []string, creds *credentials.Credentials) (AWSCollector, error) {
+	if len(regions) == 0 {
+		return NewAWSCollector(partition, creds)
+	}
+	col := AWSCollector{partition: strings.ToLower(partition)}
+	available := make(map[string]bool)
+	for _, r := range col.getRegions(partition) {
+		available[r] = true
+	}
+	if len(available) == 0 {
+		return col, fmt.Errorf("Invalid Region Selected")
+	}
+	for _, r := range regions {
+		if !available[r] {
+			return col, fmt.Errorf("Region %s is not part of the %s partition", r, partition)
+		}
+	}
+	err := col.initSessions(regions, creds)
+	if err != nil {
+		return col, err
+	}
+	if _, ok := col.sessions[col.getGlobalRegion()]; !ok {
+		sessions, err := buildSessions([]string{col.getGlobalRegion()}, creds)
+		if err != nil {
+			return col, fmt.Errorf("Unable to build AWS Sessions: %v", err)
+		}
+		col.global = sessions[col.getGlobalRegion()]
+	}
+	if !col.CheckCredentials() {
+		return col, fmt.Errorf("Error obtaining AWS Credentials")
+	}
+	return col, nil
+}
+
 // AWSCollector is a concurrent inventory collection struct for Amazon Web Services
 type AWSCollector struct {
-	sessions map[string]*session.Session
+	partition string
+	sessions  map[string]*session.Session
+	// global is the session of the global region when it is not one of the collected regions
+	global *session.Session
 }
 
 func (col *AWSCollector) getRegions(partition string) []string {
@@ -61,14 +100,28 @@ func (col *AWSCollector) getRegions(partition string) []string {
 	return regions
 }
 
-func (col *AWSCollector) initSessions(regions []string, creds *credentials.Credentials) error {
-	var sessions map[string]*session.Session
-	var err error
-	if creds == nil {
-		sessions, err = awslib.BuildSessions(regions)
-	} else {
-		sessions, err = awslib.BuildSessionsWithCredentials(regions, creds)
+// getGlobalRegion returns the region hosting the endpoints of global services such as IAM for the partition
+func (col AWSCollector) getGlobalRegion() string {
+	if col.partition == "china" {
+		return "cn-north-1"
+	}
+	return "us-east-1"
+}
+
+// globalSession returns the session used to query global services, which are not tied to a region
+func (col AWSCollector) globalSession() (*session.Session, error) {
+	if col.global != nil {
+		return col.global, nil
 	}
+	sess, ok := col.sessions[col.getGlobalRegion()]
+	if !ok {
+		return nil, fmt.Errorf("No session available for global region %s", col.getGlobalRegion())
+	}
+	return sess, nil
+}
+
+func (col *AWSCollector) initSessions(regions []string, creds *credentials.Credentials) error {
+	sessions, err := buildSessions(regions, creds)
 	if err != nil {
 		return fmt.Errorf("Unable to build AWS Sessions: %v", err)
 	}
@@ -76,6 +129,14 @@ func (col *AWSCollector) initSessions(regions []string, creds *credentials.Crede
 	return nil
 }
 
+// buildSessions returns a session per region using creds, or the Standard Environment variables if creds is nil
+func buildSessions(regions []string, creds *credentials.Credentials) (map[string]*session.Session, error) {
+	if creds == nil {
+		return awslib.BuildSessions(regions)
+	}
+	return awslib.BuildSessionsWithCredentials(regions, creds)
+}
+
 // CheckCredentials tests the proper availability of AWS Credentials in the environment
 func (col AWSCollector) CheckCredentials() bool {
 	for _, sess := range col.sessions {
@@ -107,7 +168,7 @@ func (col AWSCollector) CollectEC2() (map[string][]*ec2.Instance, error) {
 			chunk, err := CollectEC2PerSession(sess)
 
 			if err != nil {
-				errChan <- fmt.Errorf(fmt.Sprintf("Error while gathering %s: %v", region, err))
+				errChan <- fmt.Errorf("Error while gathering %s: %v", region, err)
 				return
 			}
 
@@ -123,7 +184,7 @@ func (col AWSCollector) CollectEC2() (map[string][]*ec2.Instance, error) {
 	close(errChan)
 
 	if len(errChan) > 0 {
-		return nil, fmt.Errorf(fmt.Sprintf("Failed to gather EC2 Data: %v", <-errChan))
+		return nil, fmt.Errorf("Failed to gather EC2 Data: %v", <-errChan)
 	}
 
 	for regionChunk := range instancesChan {
@@ -153,7 +214,7 @@ func (col AWSCollector) CollectRDS() (map[string][]*rds.DBInstance, error) {
 			chunk, err := CollectRDSPerSession(sess)
 
 			if err != nil {
-				errChan <- fmt.Errorf(fmt.Sprintf("Error while gathering %s: %v", region, err))
+				errChan <- fmt.Errorf("Error while gathering %s: %v", region, err)
 				return
 			}
 
@@ -169,7 +230,7 @@ func (col AWSCollector) CollectRDS() (map[string][]*rds.DBInstance, error) {
 	close(errChan)
 
 	if len(errChan) > 0 {
-		return nil, fmt.Errorf(fmt.Sprintf("Failed to gather RDS Data: %v", <-errChan))
+		return nil, fmt.Errorf("Failed to gather RDS Data: %v", <-errChan)
 	}
 
 	for regionChunk := range instancesChan {
@@ -179,6 +240,18 @@ func (col AWSCollector) CollectRDS() (map[string][]*rds.DBInstance, error) {
 
 }
 
+// collectPerRegion concurrently runs collect against every regional session.
+// Regions for which collect returns nil are left out of the result
+func (col AWSCollector) collectPerRegion(service string, collect func(sess *session.Session) (interface{}, error)) (map[string]interface{}, error) {
+	var regions []string
+	for region := range col.sessions {
+		regions = append(regions, region)
+	}
+	return collectConcurrently(service, regions, func(region string) (interface{}, error) {
+		return collect(col.sessions[region])
+	})
+}
+
 // CollectRDSPerSession returns an RDS inventory for a given session
 func CollectRDSPerSession(sess *session.Session) ([]*rds.DBInstance, error) {
 	instances, err := awslib.GetAllDBInstances(sess)
@@ -13,9 +13,16 @@ governing permissions and limitations under the License.
 package collector
 
 import (
+	"os"
 	"testing"
 
 	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/iam"
 )
 
 // TestAWSCollectorCreation attempts to build a new collector with initialized sessions for the given partition. This test is also very credential dependent.
@@ -39,6 +46,26 @@ func TestAWSCollectorCreation(t *testing.T) {
 	}
 }
 
+// TestAWSCollectorForRegions builds a collector restricted to a region, which still holds a session for global services
+func TestAWSCollectorForRegions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	col, err := NewAWSCollectorForRegions("default", []string{"eu-west-1"}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create collector: %v", err)
+	}
+	if len(col.sessions) != 1 || col.sessions["eu-west-1"] == nil {
+		t.Errorf("Expected a single regional session, got %v", col.sessions)
+	}
+	if sess, err := col.globalSession(); err != nil || *sess.Config.Region != "us-east-1" {
+		t.Errorf("Expected a us-east-1 global session: %v", err)
+	}
+	if _, err := NewAWSCollectorForRegions("china", []string{"eu-west-1"}, nil); err == nil {
+		t.Errorf("Expected a region outside of the partition to fail")
+	}
+}
+
 // TestCollectEC2 tries to gather instances across all regions
 func TestCollectEC2(t *testing.T) {
 	if testing.Short() {
@@ -87,6 +114,442 @@ func TestCollectRDS(t *testing.T) {
 	}
 }
 
+// TestCollectEBS tries to gather EBS volumes, snapshots and AMIs across all regions
+func TestCollectEBS(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	col, err := NewAWSCollector("default", nil)
+	if err != nil {
+		t.Errorf("Failed to create default collector: %v", err)
+	}
+	inv, err := col.CollectEBS()
+	if err != nil {
+		t.Errorf("Failed to collect EBS resources: %v", err)
+	}
+	for r := range inv {
+		if !stringInSlice(r, awslib.GetAllRegions()) {
+			t.Errorf("Found rougue region in EBS inventory")
+		}
+	}
+}
+
+// TestLinkInstanceStorage checks that instances are linked to the IDs of their volumes and owned AMI
+func TestLinkInstanceStorage(t *testing.T) {
+	instances := []*ec2.Instance{{
+		InstanceId:          aws.String("i-1"),
+		ImageId:             aws.String("ami-1"),
+		BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{{Ebs: &ec2.EbsInstanceBlockDevice{VolumeId: aws.String("vol-1")}}},
+	}, {
+		InstanceId: aws.String("i-2"),
+		ImageId:    aws.String("ami-public"),
+	}}
+	volumes := []*ec2.Volume{{VolumeId: aws.String("vol-1")}, {VolumeId: aws.String("vol-2")}}
+	images := []*ec2.Image{{ImageId: aws.String("ami-1")}}
+
+	storage := linkInstanceStorage(instances, volumes, images)
+	if s := storage["i-1"]; s == nil || s.ImageID != "ami-1" || len(s.VolumeIDs) != 1 || s.VolumeIDs[0] != "vol-1" {
+		t.Errorf("i-1 not linked to ami-1 and vol-1: %+v", s)
+	}
+	if s := storage["i-2"]; s == nil || s.ImageID != "" || len(s.VolumeIDs) != 0 {
+		t.Errorf("i-2 should not link to any owned image or volume: %+v", s)
+	}
+}
+
+// TestCollectRDSInventory tries to gather RDS instances, clusters, snapshots and groups across all regions
+func TestCollectRDSInventory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	col, err := NewAWSCollector("default", nil)
+	if err != nil {
+		t.Errorf("Failed to create default collector: %v", err)
+	}
+	inv, err := col.CollectRDSInventory()
+	if err != nil {
+		t.Errorf("Failed to collect RDS resources: %v", err)
+	}
+	for r := range inv {
+		if !stringInSlice(r, awslib.GetAllRegions()) {
+			t.Errorf("Found rougue region in RDS inventory")
+		}
+	}
+}
+
+// TestCollectContainers tries to gather EKS and ECS clusters across all regions
+func TestCollectContainers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	col, err := NewAWSCollector("default", nil)
+	if err != nil {
+		t.Errorf("Failed to create default collector: %v", err)
+	}
+	if _, err := col.CollectEKS(); err != nil {
+		t.Errorf("Failed to collect EKS clusters: %v", err)
+	}
+	if _, err := col.CollectECS(); err != nil {
+		t.Errorf("Failed to collect ECS clusters: %v", err)
+	}
+}
+
+// TestCollectDataStores tries to gather DynamoDB, ElastiCache and Redshift across all regions
+func TestCollectDataStores(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	col, err := NewAWSCollector("default", nil)
+	if err != nil {
+		t.Errorf("Failed to create default collector: %v", err)
+	}
+	if _, err := col.CollectDynamoDB(); err != nil {
+		t.Errorf("Failed to collect DynamoDB tables: %v", err)
+	}
+	if _, err := col.CollectElastiCache(); err != nil {
+		t.Errorf("Failed to collect ElastiCache clusters: %v", err)
+	}
+	if _, err := col.CollectRedshift(); err != nil {
+		t.Errorf("Failed to collect Redshift clusters: %v", err)
+	}
+}
+
+// TestGetGlobalRegion checks that global services are queried from the home region of each partition
+func TestGetGlobalRegion(t *testing.T) {
+	for partition, region := range map[string]string{"default": "us-east-1", "china": "cn-north-1"} {
+		col := AWSCollector{partition: partition}
+		if have := col.getGlobalRegion(); have != region {
+			t.Errorf("%s\tWant:%s\tHave:%s", partition, region, have)
+		}
+	}
+}
+
+// TestCollectIAM tries to gather the IAM inventory of the account
+func TestCollectIAM(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	col, err := NewAWSCollector("default", nil)
+	if err != nil {
+		t.Errorf("Failed to create default collector: %v", err)
+	}
+	if _, err := col.CollectIAM(); err != nil {
+		t.Errorf("Failed to collect IAM inventory: %v", err)
+	}
+}
+
+// TestCollectRoute53 tries to gather load balancers and hosted zones
+func TestCollectRoute53(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	col, err := NewAWSCollector("default", nil)
+	if err != nil {
+		t.Errorf("Failed to create default collector: %v", err)
+	}
+	if _, err := col.CollectELB(); err != nil {
+		t.Errorf("Failed to collect load balancers: %v", err)
+	}
+	if _, err := col.CollectRoute53(awslib.NewResourceIndex(), true); err != nil {
+		t.Errorf("Failed to collect hosted zones: %v", err)
+	}
+}
+
+// TestCollectAutoScaling tries to gather Auto Scaling groups across all regions
+func TestCollectAutoScaling(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	col, err := NewAWSCollector("default", nil)
+	if err != nil {
+		t.Errorf("Failed to create default collector: %v", err)
+	}
+	if _, err := col.CollectAutoScaling(); err != nil {
+		t.Errorf("Failed to collect Auto Scaling groups: %v", err)
+	}
+}
+
+// TestAnnotateAWSOwners checks that instances of an Auto Scaling group are annotated with its name,
+// and stack managed resources with their stack
+func TestAnnotateAWSOwners(t *testing.T) {
+	inventory := map[string]interface{}{
+		"ec2": map[string][]*ec2.Instance{
+			"us-east-1": {{InstanceId: aws.String("i-1")}, {InstanceId: aws.String("i-2")}},
+		},
+		"autoscaling": map[string]*AutoScalingInventory{
+			"us-east-1": {InstanceGroups: map[string]string{"i-1": "web"}},
+		},
+		"dynamodb": map[string][]*awslib.DynamoDBTable{
+			"us-east-1": {
+				{Table: &dynamodb.TableDescription{TableName: aws.String("orders")}},
+				{Table: &dynamodb.TableDescription{TableName: aws.String("jobs")}},
+			},
+		},
+		"iam": &awslib.IAMInventory{Roles: []*iam.RoleDetail{{RoleName: aws.String("app-role")}}},
+		"cloudformation": map[string]*CloudFormationInventory{
+			"us-east-1": {ResourceOwners: map[string]map[string]*awslib.StackOwner{
+				"AWS::EC2::Instance":   {"i-1": {StackName: "app", LogicalID: "Web", ResourceType: "AWS::EC2::Instance"}},
+				"AWS::DynamoDB::Table": {"orders": {StackName: "app", LogicalID: "Orders", ResourceType: "AWS::DynamoDB::Table"}},
+				"AWS::SQS::Queue":      {"jobs": {StackName: "app", LogicalID: "Jobs", ResourceType: "AWS::SQS::Queue"}},
+				"AWS::IAM::Role":       {"app-role": {StackName: "app", LogicalID: "Role", ResourceType: "AWS::IAM::Role"}},
+			}},
+		},
+	}
+	doc, err := AnnotateAWSOwners(inventory)
+	if err != nil {
+		t.Fatalf("Failed to annotate owners: %v", err)
+	}
+	instances := inventoryResources(doc, "ec2", "us-east-1", "")
+	if len(instances) != 2 {
+		t.Fatalf("Expected 2 instances, got %d", len(instances))
+	}
+	if group := jsonObject(instances[0])["AutoScalingGroup"]; group != "web" {
+		t.Errorf("i-1 should belong to web, got %v", group)
+	}
+	if group, ok := jsonObject(instances[1])["AutoScalingGroup"]; ok {
+		t.Errorf("i-2 should not belong to a group, got %v", group)
+	}
+	if stack := jsonObject(jsonObject(instances[0])["CloudFormationStack"]); stack["StackName"] != "app" || stack["LogicalID"] != "Web" {
+		t.Errorf("i-1 should be owned by app/Web, got %v", stack)
+	}
+	tables := inventoryResources(doc, "dynamodb", "us-east-1", "")
+	if _, ok := jsonObject(tables[0])["CloudFormationStack"]; !ok {
+		t.Errorf("Table should be owned by app")
+	}
+	roles := inventoryResources(doc, "iam", "", "Roles")
+	if _, ok := jsonObject(roles[0])["CloudFormationStack"]; !ok {
+		t.Errorf("Global role should be owned by app")
+	}
+	// An owner only annotates resources of its own type
+	if _, ok := jsonObject(tables[1])["CloudFormationStack"]; ok {
+		t.Errorf("Table named after a queue should not be owned by app")
+	}
+}
+
+// TestAnnotateAWSOwnersSharedIDs checks that same-named resources of different types are annotated with their own stack
+func TestAnnotateAWSOwnersSharedIDs(t *testing.T) {
+	inventory := map[string]interface{}{
+		"ecs": map[string]*ECSInventory{
+			"us-east-1": {Clusters: []*awslib.ECSCluster{{Cluster: &ecs.Cluster{ClusterName: aws.String("app")}}}},
+		},
+		"eks": map[string][]*awslib.EKSCluster{
+			"us-east-1": {{Cluster: &eks.Cluster{Name: aws.String("app")}}},
+		},
+		"dynamodb": map[string][]*awslib.DynamoDBTable{
+			"us-east-1": {{Table: &dynamodb.TableDescription{TableName: aws.String("app")}}},
+		},
+		"cloudformation": map[string]*CloudFormationInventory{
+			"us-east-1": {ResourceOwners: map[string]map[string]*awslib.StackOwner{
+				"AWS::ECS::Cluster":    {"app": {StackName: "ecs", LogicalID: "Cluster", ResourceType: "AWS::ECS::Cluster"}},
+				"AWS::EKS::Cluster":    {"app": {StackName: "eks", LogicalID: "Cluster", ResourceType: "AWS::EKS::Cluster"}},
+				"AWS::DynamoDB::Table": {"app": {StackName: "data", LogicalID: "Table", ResourceType: "AWS::DynamoDB::Table"}},
+			}},
+		},
+	}
+	doc, err := AnnotateAWSOwners(inventory)
+	if err != nil {
+		t.Fatalf("Failed to annotate owners: %v", err)
+	}
+	for _, c := range []struct{ service, field, stack string }{
+		{"ecs", "Clusters", "ecs"},
+		{"eks", "", "eks"},
+		{"dynamodb", "", "data"},
+	} {
+		resources := inventoryResources(doc, c.service, "us-east-1", c.field)
+		if len(resources) != 1 {
+			t.Fatalf("Expected 1 %s resource, got %d", c.service, len(resources))
+		}
+		if stack := jsonObject(jsonObject(resources[0])["CloudFormationStack"]); stack["StackName"] != c.stack {
+			t.Errorf("%s app should be owned by %s, got %v", c.service, c.stack, stack)
+		}
+	}
+}
+
+// TestCollectCloudFormation tries to gather CloudFormation stacks across all regions
+func TestCollectCloudFormation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	col, err := NewAWSCollector("default", nil)
+	if err != nil {
+		t.Errorf("Failed to create default collector: %v", err)
+	}
+	if _, err := col.CollectCloudFormation(); err != nil {
+		t.Errorf("Failed to collect CloudFormation stacks: %v", err)
+	}
+}
+
+// TestCollectMessaging tries to gather SQS, SNS, Kinesis and EventBridge across all regions
+func TestCollectMessaging(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	col, err := NewAWSCollector("default", nil)
+	if err != nil {
+		t.Errorf("Failed to create default collector: %v", err)
+	}
+	if _, err := col.CollectSQS(); err != nil {
+		t.Errorf("Failed to collect SQS queues: %v", err)
+	}
+	if _, err := col.CollectSNS(); err != nil {
+		t.Errorf("Failed to collect SNS topics: %v", err)
+	}
+	if _, err := col.CollectKinesis(); err != nil {
+		t.Errorf("Failed to collect Kinesis streams: %v", err)
+	}
+	if _, err := col.CollectEventBridge(); err != nil {
+		t.Errorf("Failed to collect EventBridge buses: %v", err)
+	}
+}
+
+// TestCollectEdge tries to gather CloudFront, API Gateway and WAF
+func TestCollectEdge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	col, err := NewAWSCollector("default", nil)
+	if err != nil {
+		t.Errorf("Failed to create default collector: %v", err)
+	}
+	if _, err := col.CollectCloudFront(); err != nil {
+		t.Errorf("Failed to collect CloudFront distributions: %v", err)
+	}
+	if _, err := col.CollectAPIGateway(); err != nil {
+		t.Errorf("Failed to collect API Gateway APIs: %v", err)
+	}
+	if _, err := col.CollectWAF(); err != nil {
+		t.Errorf("Failed to collect WAF web ACLs: %v", err)
+	}
+}
+
+// TestCollectSecurity tries to gather KMS keys, secrets and certificates across all regions
+func TestCollectSecurity(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	col, err := NewAWSCollector("default", nil)
+	if err != nil {
+		t.Errorf("Failed to create default collector: %v", err)
+	}
+	if _, err := col.CollectKMS(); err != nil {
+		t.Errorf("Failed to collect KMS keys: %v", err)
+	}
+	if _, err := col.CollectSecretsManager(); err != nil {
+		t.Errorf("Failed to collect secrets: %v", err)
+	}
+	if _, err := col.CollectACM(); err != nil {
+		t.Errorf("Failed to collect certificates: %v", err)
+	}
+}
+
+// TestCollectECR tries to gather ECR repositories, keeping a single image per repository
+func TestCollectECR(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	col, err := NewAWSCollector("default", nil)
+	if err != nil {
+		t.Errorf("Failed to create default collector: %v", err)
+	}
+	repositories, err := col.CollectECR(1)
+	if err != nil {
+		t.Errorf("Failed to collect ECR repositories: %v", err)
+	}
+	for region, r := range repositories {
+		for _, repository := range r {
+			if len(repository.Images) > 1 {
+				t.Errorf("Repository %s in %s kept %d images", *repository.Repository.RepositoryName, region, len(repository.Images))
+			}
+		}
+	}
+}
+
+// TestCollectAddresses tries to gather Elastic IPs, network interfaces and NAT gateways across all regions
+func TestCollectAddresses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	col, err := NewAWSCollector("default", nil)
+	if err != nil {
+		t.Errorf("Failed to create default collector: %v", err)
+	}
+	if _, err := col.CollectAddresses(); err != nil {
+		t.Errorf("Failed to collect addresses: %v", err)
+	}
+}
+
+// TestCollectFileSystems tries to gather EFS and FSx file systems across all regions
+func TestCollectFileSystems(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	col, err := NewAWSCollector("default", nil)
+	if err != nil {
+		t.Errorf("Failed to create default collector: %v", err)
+	}
+	inventory, err := col.CollectFileSystems()
+	if err != nil {
+		t.Errorf("Failed to collect file systems: %v", err)
+	}
+	for region, inv := range inventory {
+		if len(inv.Networks) != len(inv.EFS)+len(inv.FSx) {
+			t.Errorf("Not every file system in %s is linked to a network", region)
+		}
+	}
+}
+
+// TestDedicatedCollector checks that tagged resources are matched to the collector gathering their details
+func TestDedicatedCollector(t *testing.T) {
+	for _, testCase := range []struct {
+		service      string
+		resourceType string
+		collector    string
+	}{
+		{"ec2", "instance", "ec2"},
+		{"ec2", "volume", "ebs"},
+		{"ec2", "vpc-endpoint", ""},
+		{"rds", "cluster", "rds"},
+		{"sqs", "", "sqs"},
+		{"elasticloadbalancing", "targetgroup", ""},
+		{"lambda", "function", ""},
+	} {
+		if have := DedicatedCollector(testCase.service, testCase.resourceType); have != testCase.collector {
+			t.Errorf("%s:%s\tWant:%q\tHave:%q", testCase.service, testCase.resourceType, testCase.collector, have)
+		}
+	}
+}
+
+// TestCollectTaggedResources tries to gather every tagged resource across all regions
+func TestCollectTaggedResources(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	col, err := NewAWSCollector("default", nil)
+	if err != nil {
+		t.Errorf("Failed to create default collector: %v", err)
+	}
+	if _, err := col.CollectTaggedResources(); err != nil {
+		t.Errorf("Failed to collect tagged resources: %v", err)
+	}
+}
+
+// TestConfigCollector tries to gather EC2 and RDS instances from the aggregator named in CLOUDINVENTORY_TEST_AGGREGATOR
+func TestConfigCollector(t *testing.T) {
+	aggregator := os.Getenv("CLOUDINVENTORY_TEST_AGGREGATOR")
+	if testing.Short() || aggregator == "" {
+		t.Skip("Skipping test in short mode or without an aggregator")
+	}
+	src, err := NewConfigCollector("us-east-1", aggregator, nil)
+	if err != nil {
+		t.Fatalf("Failed to create Config collector: %v", err)
+	}
+	if _, err := src.CollectEC2(); err != nil {
+		t.Errorf("Failed to collect EC2 instances: %v", err)
+	}
+	if _, err := src.CollectRDS(); err != nil {
+		t.Errorf("Failed to collect RDS instances: %v", err)
+	}
+}
+
 func stringInSlice(a string, list []string) bool {
 	for _, b := range list {
 		if b == a {
@@ -0,0 +1,69 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// ConfigCollector collects inventory from an AWS Config aggregator instead of the Describe APIs of every region.
+// A single advanced query covers every account and region of the aggregator, inventories are keyed by account, then region
+type ConfigCollector struct {
+	aggregator string
+	sess       *session.Session
+}
+
+// NewConfigCollector returns a ConfigCollector querying the aggregator hosted in region.
+// Uses supplied credentials, Standard Environment variables if creds not specified
+func NewConfigCollector(region, aggregator string, creds *credentials.Credentials) (ConfigCollector, error) {
+	col := ConfigCollector{aggregator: aggregator}
+	if aggregator == "" {
+		return col, fmt.Errorf("No AWS Config aggregator selected")
+	}
+	var sessions map[string]*session.Session
+	var err error
+	if creds == nil {
+		sessions, err = awslib.BuildSessions([]string{region})
+	} else {
+		sessions, err = awslib.BuildSessionsWithCredentials([]string{region}, creds)
+	}
+	if err != nil {
+		return col, fmt.Errorf("Unable to build AWS Session: %v", err)
+	}
+	col.sess = sessions[region]
+	return col, nil
+}
+
+// CollectEC2 returns the EC2 inventory recorded by the aggregator for all its accounts and regions
+func (col ConfigCollector) CollectEC2() (map[string]map[string][]*ec2.Instance, error) {
+	instances, err := awslib.GetConfigInstances(col.sess, col.aggregator)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather EC2 Data from AWS Config: %v", err)
+	}
+	return instances, nil
+}
+
+// CollectRDS returns the RDS inventory recorded by the aggregator for all its accounts and regions
+func (col ConfigCollector) CollectRDS() (map[string]map[string][]*rds.DBInstance, error) {
+	instances, err := awslib.GetConfigDBInstances(col.sess, col.aggregator)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather RDS Data from AWS Config: %v", err)
+	}
+	return instances, nil
+}
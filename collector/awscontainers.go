@@ -0,0 +1,87 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+// ECSInventory holds the ECS clusters and active task definitions of a single region
+type ECSInventory struct {
+	Clusters        []*awslib.ECSCluster
+	TaskDefinitions []*ecs.TaskDefinition
+}
+
+// CollectEKS returns a concurrently collected EKS inventory for all the regions
+func (col AWSCollector) CollectEKS() (map[string][]*awslib.EKSCluster, error) {
+	chunks, err := col.collectPerRegion("EKS", func(sess *session.Session) (interface{}, error) {
+		clusters, err := CollectEKSPerSession(sess)
+		// Ignore regions with no clusters
+		if clusters == nil {
+			return nil, err
+		}
+		return clusters, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	clusters := make(map[string][]*awslib.EKSCluster)
+	for region, chunk := range chunks {
+		clusters[region] = chunk.([]*awslib.EKSCluster)
+	}
+	return clusters, nil
+}
+
+// CollectEKSPerSession returns an EKS inventory for a given session
+func CollectEKSPerSession(sess *session.Session) ([]*awslib.EKSCluster, error) {
+	clusters, err := awslib.GetAllEKSClusters(sess)
+	return clusters, err
+}
+
+// CollectECS returns a concurrently collected ECS inventory for all the regions
+func (col AWSCollector) CollectECS() (map[string]*ECSInventory, error) {
+	chunks, err := col.collectPerRegion("ECS", func(sess *session.Session) (interface{}, error) {
+		inv, err := CollectECSPerSession(sess)
+		if inv == nil {
+			return nil, err
+		}
+		return inv, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	inventory := make(map[string]*ECSInventory)
+	for region, chunk := range chunks {
+		inventory[region] = chunk.(*ECSInventory)
+	}
+	return inventory, nil
+}
+
+// CollectECSPerSession returns an ECS inventory for a given session.
+// Returns nil if the region holds neither clusters nor task definitions
+func CollectECSPerSession(sess *session.Session) (*ECSInventory, error) {
+	clusters, err := awslib.GetAllECSClusters(sess)
+	if err != nil {
+		return nil, err
+	}
+	taskDefinitions, err := awslib.GetAllTaskDefinitions(sess)
+	if err != nil {
+		return nil, err
+	}
+	if len(clusters) == 0 && len(taskDefinitions) == 0 {
+		return nil, nil
+	}
+	return &ECSInventory{Clusters: clusters, TaskDefinitions: taskDefinitions}, nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/redshift"
+)
+
+// ElastiCacheInventory holds the ElastiCache replication groups and cache clusters of a single region
+type ElastiCacheInventory struct {
+	ReplicationGroups []*elasticache.ReplicationGroup
+	CacheClusters     []*elasticache.CacheCluster
+}
+
+// CollectDynamoDB returns a concurrently collected DynamoDB inventory for all the regions
+func (col AWSCollector) CollectDynamoDB() (map[string][]*awslib.DynamoDBTable, error) {
+	chunks, err := col.collectPerRegion("DynamoDB", func(sess *session.Session) (interface{}, error) {
+		tables, err := CollectDynamoDBPerSession(sess)
+		// Ignore regions with no tables
+		if tables == nil {
+			return nil, err
+		}
+		return tables, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	tables := make(map[string][]*awslib.DynamoDBTable)
+	for region, chunk := range chunks {
+		tables[region] = chunk.([]*awslib.DynamoDBTable)
+	}
+	return tables, nil
+}
+
+// CollectDynamoDBPerSession returns a DynamoDB inventory for a given session
+func CollectDynamoDBPerSession(sess *session.Session) ([]*awslib.DynamoDBTable, error) {
+	tables, err := awslib.GetAllDynamoDBTables(sess)
+	return tables, err
+}
+
+// CollectElastiCache returns a concurrently collected ElastiCache inventory for all the regions
+func (col AWSCollector) CollectElastiCache() (map[string]*ElastiCacheInventory, error) {
+	chunks, err := col.collectPerRegion("ElastiCache", func(sess *session.Session) (interface{}, error) {
+		inv, err := CollectElastiCachePerSession(sess)
+		if inv == nil {
+			return nil, err
+		}
+		return inv, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	inventory := make(map[string]*ElastiCacheInventory)
+	for region, chunk := range chunks {
+		inventory[region] = chunk.(*ElastiCacheInventory)
+	}
+	return inventory, nil
+}
+
+// CollectElastiCachePerSession returns an ElastiCache inventory for a given session.
+// Returns nil if the region holds no replication groups or cache clusters
+func CollectElastiCachePerSession(sess *session.Session) (*ElastiCacheInventory, error) {
+	groups, err := awslib.GetAllReplicationGroups(sess)
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := awslib.GetAllCacheClusters(sess)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 && len(clusters) == 0 {
+		return nil, nil
+	}
+	return &ElastiCacheInventory{ReplicationGroups: groups, CacheClusters: clusters}, nil
+}
+
+// CollectRedshift returns a concurrently collected Redshift inventory for all the regions
+func (col AWSCollector) CollectRedshift() (map[string][]*redshift.Cluster, error) {
+	chunks, err := col.collectPerRegion("Redshift", func(sess *session.Session) (interface{}, error) {
+		clusters, err := CollectRedshiftPerSession(sess)
+		if clusters == nil {
+			return nil, err
+		}
+		return clusters, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	clusters := make(map[string][]*redshift.Cluster)
+	for region, chunk := range chunks {
+		clusters[region] = chunk.([]*redshift.Cluster)
+	}
+	return clusters, nil
+}
+
+// CollectRedshiftPerSession returns a Redshift inventory for a given session
+func CollectRedshiftPerSession(sess *session.Session) ([]*redshift.Cluster, error) {
+	clusters, err := awslib.GetAllRedshiftClusters(sess)
+	return clusters, err
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// EBSInventory holds the EBS volumes, snapshots and AMIs of a single region.
+// UnattachedVolumes and OrphanedSnapshots list the IDs of resources which are likely only adding cost.
+// InstanceStorage links every instance of the region to its volumes and AMI, keyed by instance ID
+type EBSInventory struct {
+	Volumes           []*ec2.Volume
+	Snapshots         []*ec2.Snapshot
+	Images            []*ec2.Image
+	UnattachedVolumes []string
+	OrphanedSnapshots []string
+	InstanceStorage   map[string]*InstanceStorage
+}
+
+// InstanceStorage holds the IDs of the volumes attached to an instance and of the AMI it was built from.
+// ImageID is empty for AMIs which are not owned by the account, e.g. public ones
+type InstanceStorage struct {
+	ImageID   string
+	VolumeIDs []string
+}
+
+// CollectEBS returns a concurrently collected EBS inventory for all the regions
+func (col AWSCollector) CollectEBS() (map[string]*EBSInventory, error) {
+	chunks, err := col.collectPerRegion("EBS", func(sess *session.Session) (interface{}, error) {
+		inv, err := CollectEBSPerSession(sess)
+		// Ignore regions with no EBS resources
+		if inv == nil {
+			return nil, err
+		}
+		return inv, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	inventory := make(map[string]*EBSInventory)
+	for region, chunk := range chunks {
+		inventory[region] = chunk.(*EBSInventory)
+	}
+	return inventory, nil
+}
+
+// CollectEBSPerSession returns an EBS inventory for a given session.
+// Returns nil if the region holds no volumes, snapshots or images
+func CollectEBSPerSession(sess *session.Session) (*EBSInventory, error) {
+	volumes, err := awslib.GetAllVolumes(sess)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := awslib.GetAllSnapshots(sess)
+	if err != nil {
+		return nil, err
+	}
+	images, err := awslib.GetAllImages(sess)
+	if err != nil {
+		return nil, err
+	}
+	if len(volumes) == 0 && len(snapshots) == 0 && len(images) == 0 {
+		return nil, nil
+	}
+	instances, err := awslib.GetAllInstances(sess)
+	if err != nil {
+		return nil, err
+	}
+	inv := &EBSInventory{
+		Volumes:   volumes,
+		Snapshots: snapshots,
+		Images:    images,
+	}
+	for _, v := range awslib.GetUnattachedVolumes(volumes) {
+		inv.UnattachedVolumes = append(inv.UnattachedVolumes, aws.StringValue(v.VolumeId))
+	}
+	for _, s := range awslib.GetOrphanedSnapshots(snapshots, volumes, images) {
+		inv.OrphanedSnapshots = append(inv.OrphanedSnapshots, aws.StringValue(s.SnapshotId))
+	}
+	inv.InstanceStorage = linkInstanceStorage(instances, volumes, images)
+	return inv, nil
+}
+
+// linkInstanceStorage returns the volume and AMI IDs of every instance, keyed by instance ID.
+// IDs are kept rather than the records themselves, which are already part of the inventory
+func linkInstanceStorage(instances []*ec2.Instance, volumes []*ec2.Volume, images []*ec2.Image) map[string]*InstanceStorage {
+	storage := make(map[string]*InstanceStorage)
+	for id, link := range awslib.LinkInstanceStorage(instances, volumes, images) {
+		s := &InstanceStorage{}
+		if link.Image != nil {
+			s.ImageID = aws.StringValue(link.Image.ImageId)
+		}
+		for _, v := range link.Volumes {
+			s.VolumeIDs = append(s.VolumeIDs, aws.StringValue(v.VolumeId))
+		}
+		storage[id] = s
+	}
+	return storage
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// CollectECR returns a concurrently collected ECR repository inventory for all the regions.
+// When maxImages is positive only that many of the most recent images are kept per repository
+func (col AWSCollector) CollectECR(maxImages int) (map[string][]*awslib.ECRRepository, error) {
+	chunks, err := col.collectPerRegion("ECR", func(sess *session.Session) (interface{}, error) {
+		repositories, err := awslib.GetAllECRRepositories(sess, maxImages)
+		// Ignore regions with no repositories
+		if repositories == nil {
+			return nil, err
+		}
+		return repositories, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	repositories := make(map[string][]*awslib.ECRRepository)
+	for region, chunk := range chunks {
+		repositories[region] = chunk.([]*awslib.ECRRepository)
+	}
+	return repositories, nil
+}
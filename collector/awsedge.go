@@ -0,0 +1,123 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/wafv2"
+)
+
+// CloudFrontInventory holds the CloudFront distributions of the account along with the
+// WAF web ACLs which can be associated with them
+type CloudFrontInventory struct {
+	Distributions []*cloudfront.DistributionSummary
+	WebACLs       []*awslib.WebACL
+}
+
+// APIGatewayInventory holds the REST, HTTP and WebSocket APIs and the custom domain names of a single region
+type APIGatewayInventory struct {
+	RestAPIs        []*awslib.RestAPI
+	RestDomainNames []*apigateway.DomainName
+	HTTPAPIs        []*awslib.HTTPAPI
+	HTTPDomainNames []*apigatewayv2.DomainName
+}
+
+// CollectCloudFront returns the CloudFront inventory of the account.
+// CloudFront is global, so unlike regional services the inventory is not keyed by region
+func (col AWSCollector) CollectCloudFront() (*CloudFrontInventory, error) {
+	sess, err := col.globalSession()
+	if err != nil {
+		return nil, err
+	}
+	var inv CloudFrontInventory
+	inv.Distributions, err = awslib.GetAllDistributions(sess)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather CloudFront Data: %v", err)
+	}
+	// Web ACLs for CloudFront only exist in us-east-1, the China partition has none
+	if col.getGlobalRegion() == "us-east-1" {
+		inv.WebACLs, err = awslib.GetAllWebACLs(sess, wafv2.ScopeCloudfront)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to gather CloudFront WAF Data: %v", err)
+		}
+	}
+	return &inv, nil
+}
+
+// CollectAPIGateway returns a concurrently collected API Gateway inventory for all the regions
+func (col AWSCollector) CollectAPIGateway() (map[string]*APIGatewayInventory, error) {
+	chunks, err := col.collectPerRegion("APIGateway", func(sess *session.Session) (interface{}, error) {
+		inv, err := CollectAPIGatewayPerSession(sess)
+		// Ignore regions with no APIs
+		if inv == nil {
+			return nil, err
+		}
+		return inv, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	inventory := make(map[string]*APIGatewayInventory)
+	for region, chunk := range chunks {
+		inventory[region] = chunk.(*APIGatewayInventory)
+	}
+	return inventory, nil
+}
+
+// CollectAPIGatewayPerSession returns an API Gateway inventory for a given session
+func CollectAPIGatewayPerSession(sess *session.Session) (*APIGatewayInventory, error) {
+	var inv APIGatewayInventory
+	var err error
+	if inv.RestAPIs, err = awslib.GetAllRestAPIs(sess); err != nil {
+		return nil, err
+	}
+	if inv.RestDomainNames, err = awslib.GetAllRestDomainNames(sess); err != nil {
+		return nil, err
+	}
+	if inv.HTTPAPIs, err = awslib.GetAllHTTPAPIs(sess); err != nil {
+		return nil, err
+	}
+	if inv.HTTPDomainNames, err = awslib.GetAllHTTPDomainNames(sess); err != nil {
+		return nil, err
+	}
+	if len(inv.RestAPIs) == 0 && len(inv.RestDomainNames) == 0 && len(inv.HTTPAPIs) == 0 && len(inv.HTTPDomainNames) == 0 {
+		return nil, nil
+	}
+	return &inv, nil
+}
+
+// CollectWAF returns a concurrently collected inventory of regional WAF web ACLs for all the regions.
+// Web ACLs protecting CloudFront distributions are part of CollectCloudFront
+func (col AWSCollector) CollectWAF() (map[string][]*awslib.WebACL, error) {
+	chunks, err := col.collectPerRegion("WAF", func(sess *session.Session) (interface{}, error) {
+		acls, err := awslib.GetAllWebACLs(sess, wafv2.ScopeRegional)
+		if acls == nil {
+			return nil, err
+		}
+		return acls, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	acls := make(map[string][]*awslib.WebACL)
+	for region, chunk := range chunks {
+		acls[region] = chunk.([]*awslib.WebACL)
+	}
+	return acls, nil
+}
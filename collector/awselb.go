@@ -0,0 +1,62 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// ELBInventory holds the load balancers of a single region
+type ELBInventory struct {
+	LoadBalancers        []*elbv2.LoadBalancer
+	ClassicLoadBalancers []*elb.LoadBalancerDescription
+}
+
+// CollectELB returns a concurrently collected load balancer inventory for all the regions
+func (col AWSCollector) CollectELB() (map[string]*ELBInventory, error) {
+	chunks, err := col.collectPerRegion("ELB", func(sess *session.Session) (interface{}, error) {
+		inv, err := CollectELBPerSession(sess)
+		// Ignore regions with no load balancers
+		if inv == nil {
+			return nil, err
+		}
+		return inv, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	inventory := make(map[string]*ELBInventory)
+	for region, chunk := range chunks {
+		inventory[region] = chunk.(*ELBInventory)
+	}
+	return inventory, nil
+}
+
+// CollectELBPerSession returns a load balancer inventory for a given session
+func CollectELBPerSession(sess *session.Session) (*ELBInventory, error) {
+	loadBalancers, err := awslib.GetAllLoadBalancers(sess)
+	if err != nil {
+		return nil, err
+	}
+	classicLoadBalancers, err := awslib.GetAllClassicLoadBalancers(sess)
+	if err != nil {
+		return nil, err
+	}
+	if len(loadBalancers) == 0 && len(classicLoadBalancers) == 0 {
+		return nil, nil
+	}
+	return &ELBInventory{LoadBalancers: loadBalancers, ClassicLoadBalancers: classicLoadBalancers}, nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"sort"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/fsx"
+)
+
+// FileSystemInventory holds the EFS and FSx file systems of a single region.
+// Networks maps every file system ID to its VPC and subnets, whose records are in VPCs and Subnets
+type FileSystemInventory struct {
+	EFS      []*awslib.EFSFileSystem
+	FSx      []*fsx.FileSystem
+	Networks map[string]*awslib.FileSystemNetwork
+	VPCs     []*ec2.Vpc
+	Subnets  []*ec2.Subnet
+}
+
+// CollectFileSystems returns a concurrently collected EFS and FSx inventory for all the regions
+func (col AWSCollector) CollectFileSystems() (map[string]*FileSystemInventory, error) {
+	chunks, err := col.collectPerRegion("FileSystems", func(sess *session.Session) (interface{}, error) {
+		inv, err := CollectFileSystemsPerSession(sess)
+		// Ignore regions with no file systems
+		if inv == nil {
+			return nil, err
+		}
+		return inv, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	inventory := make(map[string]*FileSystemInventory)
+	for region, chunk := range chunks {
+		inventory[region] = chunk.(*FileSystemInventory)
+	}
+	return inventory, nil
+}
+
+// CollectFileSystemsPerSession returns an EFS and FSx inventory for a given session.
+// Returns nil if the region holds no file systems
+func CollectFileSystemsPerSession(sess *session.Session) (*FileSystemInventory, error) {
+	efsFileSystems, err := awslib.GetAllEFSFileSystems(sess)
+	if err != nil {
+		return nil, err
+	}
+	fsxFileSystems, err := awslib.GetAllFSxFileSystems(sess)
+	if err != nil {
+		return nil, err
+	}
+	if len(efsFileSystems) == 0 && len(fsxFileSystems) == 0 {
+		return nil, nil
+	}
+	inv := &FileSystemInventory{
+		EFS:      efsFileSystems,
+		FSx:      fsxFileSystems,
+		Networks: awslib.GetFileSystemNetworks(efsFileSystems, fsxFileSystems),
+	}
+
+	// Only describe the VPCs and subnets the file systems are reachable from
+	vpcs := make(map[string]bool)
+	subnets := make(map[string]bool)
+	for _, network := range inv.Networks {
+		if network.VpcID != "" {
+			vpcs[network.VpcID] = true
+		}
+		for _, s := range network.SubnetIDs {
+			subnets[s] = true
+		}
+	}
+	if inv.VPCs, err = awslib.GetVpcs(sess, sortedKeys(vpcs)); err != nil {
+		return nil, err
+	}
+	if inv.Subnets, err = awslib.GetSubnets(sess, sortedKeys(subnets)); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	var keys []string
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
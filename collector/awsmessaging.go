@@ -0,0 +1,119 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+)
+
+// KinesisInventory holds the Kinesis data streams and Firehose delivery streams of a single region
+type KinesisInventory struct {
+	Streams         []*kinesis.StreamDescriptionSummary
+	DeliveryStreams []*firehose.DeliveryStreamDescription
+}
+
+// CollectSQS returns a concurrently collected SQS inventory for all the regions
+func (col AWSCollector) CollectSQS() (map[string][]*awslib.SQSQueue, error) {
+	chunks, err := col.collectPerRegion("SQS", func(sess *session.Session) (interface{}, error) {
+		queues, err := awslib.GetAllQueues(sess)
+		// Ignore regions with no queues
+		if queues == nil {
+			return nil, err
+		}
+		return queues, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	queues := make(map[string][]*awslib.SQSQueue)
+	for region, chunk := range chunks {
+		queues[region] = chunk.([]*awslib.SQSQueue)
+	}
+	return queues, nil
+}
+
+// CollectSNS returns a concurrently collected SNS inventory for all the regions
+func (col AWSCollector) CollectSNS() (map[string][]*awslib.SNSTopic, error) {
+	chunks, err := col.collectPerRegion("SNS", func(sess *session.Session) (interface{}, error) {
+		topics, err := awslib.GetAllTopics(sess)
+		if topics == nil {
+			return nil, err
+		}
+		return topics, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	topics := make(map[string][]*awslib.SNSTopic)
+	for region, chunk := range chunks {
+		topics[region] = chunk.([]*awslib.SNSTopic)
+	}
+	return topics, nil
+}
+
+// CollectKinesis returns a concurrently collected Kinesis and Firehose inventory for all the regions
+func (col AWSCollector) CollectKinesis() (map[string]*KinesisInventory, error) {
+	chunks, err := col.collectPerRegion("Kinesis", func(sess *session.Session) (interface{}, error) {
+		inv, err := CollectKinesisPerSession(sess)
+		if inv == nil {
+			return nil, err
+		}
+		return inv, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	inventory := make(map[string]*KinesisInventory)
+	for region, chunk := range chunks {
+		inventory[region] = chunk.(*KinesisInventory)
+	}
+	return inventory, nil
+}
+
+// CollectKinesisPerSession returns a Kinesis and Firehose inventory for a given session
+func CollectKinesisPerSession(sess *session.Session) (*KinesisInventory, error) {
+	streams, err := awslib.GetAllKinesisStreams(sess)
+	if err != nil {
+		return nil, err
+	}
+	deliveryStreams, err := awslib.GetAllDeliveryStreams(sess)
+	if err != nil {
+		return nil, err
+	}
+	if len(streams) == 0 && len(deliveryStreams) == 0 {
+		return nil, nil
+	}
+	return &KinesisInventory{Streams: streams, DeliveryStreams: deliveryStreams}, nil
+}
+
+// CollectEventBridge returns a concurrently collected EventBridge inventory for all the regions
+func (col AWSCollector) CollectEventBridge() (map[string][]*awslib.EventBus, error) {
+	chunks, err := col.collectPerRegion("EventBridge", func(sess *session.Session) (interface{}, error) {
+		buses, err := awslib.GetAllEventBuses(sess)
+		if buses == nil {
+			return nil, err
+		}
+		return buses, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	buses := make(map[string][]*awslib.EventBus)
+	for region, chunk := range chunks {
+		buses[region] = chunk.([]*awslib.EventBus)
+	}
+	return buses, nil
+}
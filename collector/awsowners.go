@@ -0,0 +1,151 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// stackResource locates the resources of a CloudFormation resource type in the inventory, and the attribute
+// holding their physical ID
+type stackResource struct {
+	service string
+	field   string
+	idKey   string
+	global  bool
+}
+
+// stackResourceTypes maps the CloudFormation resource types whose physical ID identifies an inventoried resource
+// to that resource. Nested attributes are separated by dots
+var stackResourceTypes = map[string]stackResource{
+	"AWS::EC2::Instance":                        {service: "ec2", idKey: "InstanceId"},
+	"AWS::EC2::Volume":                          {service: "ebs", field: "Volumes", idKey: "VolumeId"},
+	"AWS::EC2::EIP":                             {service: "addresses", field: "ElasticIPs", idKey: "PublicIp"},
+	"AWS::EC2::NatGateway":                      {service: "addresses", field: "NatGateways", idKey: "NatGatewayId"},
+	"AWS::EC2::NetworkInterface":                {service: "addresses", field: "NetworkInterfaces", idKey: "NetworkInterfaceId"},
+	"AWS::EC2::LaunchTemplate":                  {service: "autoscaling", field: "LaunchTemplates", idKey: "Template.LaunchTemplateId"},
+	"AWS::AutoScaling::AutoScalingGroup":        {service: "autoscaling", field: "Groups", idKey: "AutoScalingGroupName"},
+	"AWS::AutoScaling::LaunchConfiguration":     {service: "autoscaling", field: "LaunchConfigurations", idKey: "LaunchConfigurationName"},
+	"AWS::RDS::DBInstance":                      {service: "rds", field: "Instances", idKey: "DBInstanceIdentifier"},
+	"AWS::RDS::DBCluster":                       {service: "rds", field: "Clusters", idKey: "DBClusterIdentifier"},
+	"AWS::RDS::DBParameterGroup":                {service: "rds", field: "ParameterGroups", idKey: "DBParameterGroupName"},
+	"AWS::RDS::DBClusterParameterGroup":         {service: "rds", field: "ClusterParameterGroups", idKey: "DBClusterParameterGroupName"},
+	"AWS::RDS::DBSubnetGroup":                   {service: "rds", field: "SubnetGroups", idKey: "DBSubnetGroupName"},
+	"AWS::ElasticLoadBalancingV2::LoadBalancer": {service: "elb", field: "LoadBalancers", idKey: "LoadBalancerArn"},
+	"AWS::ElasticLoadBalancing::LoadBalancer":   {service: "elb", field: "ClassicLoadBalancers", idKey: "LoadBalancerName"},
+	"AWS::EKS::Cluster":                         {service: "eks", idKey: "Cluster.Name"},
+	"AWS::ECS::Cluster":                         {service: "ecs", field: "Clusters", idKey: "Cluster.ClusterName"},
+	"AWS::ECS::TaskDefinition":                  {service: "ecs", field: "TaskDefinitions", idKey: "TaskDefinitionArn"},
+	"AWS::DynamoDB::Table":                      {service: "dynamodb", idKey: "Table.TableName"},
+	"AWS::ElastiCache::ReplicationGroup":        {service: "elasticache", field: "ReplicationGroups", idKey: "ReplicationGroupId"},
+	"AWS::ElastiCache::CacheCluster":            {service: "elasticache", field: "CacheClusters", idKey: "CacheClusterId"},
+	"AWS::Redshift::Cluster":                    {service: "redshift", idKey: "ClusterIdentifier"},
+	"AWS::ECR::Repository":                      {service: "ecr", idKey: "Repository.RepositoryName"},
+	"AWS::SQS::Queue":                           {service: "sqs", idKey: "URL"},
+	"AWS::SNS::Topic":                           {service: "sns", idKey: "Arn"},
+	"AWS::Kinesis::Stream":                      {service: "kinesis", field: "Streams", idKey: "StreamName"},
+	"AWS::KinesisFirehose::DeliveryStream":      {service: "kinesis", field: "DeliveryStreams", idKey: "DeliveryStreamName"},
+	"AWS::Events::EventBus":                     {service: "eventbridge", idKey: "Bus.Name"},
+	"AWS::KMS::Key":                             {service: "kms", idKey: "Metadata.KeyId"},
+	"AWS::SecretsManager::Secret":               {service: "secretsmanager", idKey: "ARN"},
+	"AWS::CertificateManager::Certificate":      {service: "acm", idKey: "CertificateArn"},
+	"AWS::ApiGateway::RestApi":                  {service: "apigateway", field: "RestAPIs", idKey: "API.Id"},
+	"AWS::ApiGateway::DomainName":               {service: "apigateway", field: "RestDomainNames", idKey: "DomainName"},
+	"AWS::ApiGatewayV2::Api":                    {service: "apigateway", field: "HTTPAPIs", idKey: "API.ApiId"},
+	"AWS::ApiGatewayV2::DomainName":             {service: "apigateway", field: "HTTPDomainNames", idKey: "DomainName"},
+	"AWS::EFS::FileSystem":                      {service: "filesystems", field: "EFS", idKey: "FileSystem.FileSystemId"},
+	"AWS::FSx::FileSystem":                      {service: "filesystems", field: "FSx", idKey: "FileSystemId"},
+	"AWS::CloudFront::Distribution":             {service: "cloudfront", field: "Distributions", idKey: "Id", global: true},
+	"AWS::IAM::User":                            {service: "iam", field: "Users", idKey: "UserName", global: true},
+	"AWS::IAM::Group":                           {service: "iam", field: "Groups", idKey: "GroupName", global: true},
+	"AWS::IAM::Role":                            {service: "iam", field: "Roles", idKey: "RoleName", global: true},
+	"AWS::IAM::ManagedPolicy":                   {service: "iam", field: "Policies", idKey: "Arn", global: true},
+}
+
+// AnnotateAWSOwners returns the JSON document of an AWS inventory keyed by service, with its resources annotated
+// with the owners found in the inventory: EC2 instances managed by an Auto Scaling group get AutoScalingGroup,
+// resources managed by a CloudFormation stack get CloudFormationStack.
+// The inventory is returned as is when it holds no owners
+func AnnotateAWSOwners(inventory map[string]interface{}) (map[string]interface{}, error) {
+	_, autoscaling := inventory["autoscaling"]
+	_, cloudformation := inventory["cloudformation"]
+	if !autoscaling && !cloudformation {
+		return inventory, nil
+	}
+	data, err := json.Marshal(inventory)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	// Numbers are kept as is, large integers would lose precision as float64
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	for region, inv := range jsonObject(doc["autoscaling"]) {
+		annotateResources(inventoryResources(doc, "ec2", region, ""), "InstanceId", "AutoScalingGroup", jsonObject(jsonObject(inv)["InstanceGroups"]))
+	}
+	for region, inv := range jsonObject(doc["cloudformation"]) {
+		for resourceType, owned := range jsonObject(jsonObject(inv)["ResourceOwners"]) {
+			r, ok := stackResourceTypes[resourceType]
+			if !ok {
+				continue
+			}
+			resourceRegion := region
+			if r.global {
+				resourceRegion = ""
+			}
+			annotateResources(inventoryResources(doc, r.service, resourceRegion, r.field), r.idKey, "CloudFormationStack", jsonObject(owned))
+		}
+	}
+	return doc, nil
+}
+
+// inventoryResources returns the resources of a service in a region of doc, or of a global service if region
+// is empty, those listed under field of its inventory if not empty
+func inventoryResources(doc map[string]interface{}, service, region, field string) []interface{} {
+	resources := doc[service]
+	if region != "" {
+		resources = jsonObject(resources)[region]
+	}
+	if field != "" {
+		resources = jsonObject(resources)[field]
+	}
+	list, _ := resources.([]interface{})
+	return list
+}
+
+// annotateResources sets attribute on every resource whose idKey, a dot separated path, is one of the keys of
+// owners, to its owner
+func annotateResources(resources []interface{}, idKey, attribute string, owners map[string]interface{}) {
+	for _, r := range resources {
+		resource := jsonObject(r)
+		var value interface{} = resource
+		for _, key := range strings.Split(idKey, ".") {
+			value = jsonObject(value)[key]
+		}
+		id, _ := value.(string)
+		if owner, ok := owners[id]; ok && id != "" {
+			resource[attribute] = owner
+		}
+	}
+}
+
+// jsonObject returns v if it is a JSON object, nil otherwise
+func jsonObject(v interface{}) map[string]interface{} {
+	object, _ := v.(map[string]interface{})
+	return object
+}
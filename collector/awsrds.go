@@ -0,0 +1,102 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"strings"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// RDSInventory holds the RDS resources of a single region.
+// ClusterMembership maps a DB instance identifier to the cluster it belongs to
+type RDSInventory struct {
+	Instances              []*rds.DBInstance
+	Clusters               []*rds.DBCluster
+	GlobalClusters         []*rds.GlobalCluster
+	Snapshots              []*rds.DBSnapshot
+	ClusterSnapshots       []*rds.DBClusterSnapshot
+	ParameterGroups        []*rds.DBParameterGroup
+	ClusterParameterGroups []*rds.DBClusterParameterGroup
+	SubnetGroups           []*rds.DBSubnetGroup
+	ClusterMembership      map[string]*awslib.DBClusterMembership
+}
+
+// CollectRDSInventory returns a concurrently collected inventory of RDS instances, clusters, snapshots,
+// parameter groups and subnet groups for all the regions
+func (col AWSCollector) CollectRDSInventory() (map[string]*RDSInventory, error) {
+	chunks, err := col.collectPerRegion("RDS", func(sess *session.Session) (interface{}, error) {
+		inv, err := CollectRDSInventoryPerSession(sess)
+		if inv == nil {
+			return nil, err
+		}
+		return inv, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	inventory := make(map[string]*RDSInventory)
+	for region, chunk := range chunks {
+		inventory[region] = chunk.(*RDSInventory)
+	}
+	return inventory, nil
+}
+
+// CollectRDSInventoryPerSession returns an inventory of RDS instances, clusters, snapshots, parameter groups
+// and subnet groups for a given session
+func CollectRDSInventoryPerSession(sess *session.Session) (*RDSInventory, error) {
+	var inv RDSInventory
+	var err error
+	if inv.Instances, err = awslib.GetAllDBInstances(sess); err != nil {
+		return nil, err
+	}
+	if inv.Clusters, err = awslib.GetAllDBClusters(sess); err != nil {
+		return nil, err
+	}
+	globalClusters, err := awslib.GetAllGlobalClusters(sess)
+	if err != nil {
+		return nil, err
+	}
+	if inv.Snapshots, err = awslib.GetAllDBSnapshots(sess); err != nil {
+		return nil, err
+	}
+	if inv.ClusterSnapshots, err = awslib.GetAllDBClusterSnapshots(sess); err != nil {
+		return nil, err
+	}
+	if inv.ParameterGroups, err = awslib.GetAllDBParameterGroups(sess); err != nil {
+		return nil, err
+	}
+	if inv.ClusterParameterGroups, err = awslib.GetAllDBClusterParameterGroups(sess); err != nil {
+		return nil, err
+	}
+	if inv.SubnetGroups, err = awslib.GetAllDBSubnetGroups(sess); err != nil {
+		return nil, err
+	}
+	// Global clusters are visible from every region, only keep those with a member cluster here
+	region := aws.StringValue(sess.Config.Region)
+	for _, g := range globalClusters {
+		for _, m := range g.GlobalClusterMembers {
+			// arn:partition:rds:region:account:cluster:name
+			arn := strings.Split(aws.StringValue(m.DBClusterArn), ":")
+			if len(arn) > 3 && arn[3] == region {
+				inv.GlobalClusters = append(inv.GlobalClusters, g)
+				break
+			}
+		}
+	}
+	inv.ClusterMembership = awslib.GetDBClusterMembership(inv.Clusters)
+	return &inv, nil
+}
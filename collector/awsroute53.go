@@ -0,0 +1,61 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/adobe/cloudinventory/awslib"
+)
+
+// Route53Inventory holds the hosted zones of the account and the resolution of their records.
+// Dangling lists the records pointing to AWS resources or IPs which are no longer in the inventory
+type Route53Inventory struct {
+	Zones    []*awslib.HostedZone
+	Records  []*awslib.RecordLink
+	Dangling []*awslib.RecordLink
+}
+
+// CollectRoute53 returns the Route 53 inventory of the account with records resolved against index.
+// Records pointing to IPs are only flagged as dangling when withIPs is set, which requires the index
+// to hold the Elastic IPs and network interfaces of the account.
+// Route 53 is global, so unlike regional services the inventory is not keyed by region
+func (col AWSCollector) CollectRoute53(index awslib.ResourceIndex, withIPs bool) (*Route53Inventory, error) {
+	sess, err := col.globalSession()
+	if err != nil {
+		return nil, err
+	}
+	zones, err := awslib.GetAllHostedZones(sess)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Route53 Data: %v", err)
+	}
+	// Records pointing to an IP owned by AWS but by none of the inventoried resources are dangling
+	var awsRanges []*net.IPNet
+	if withIPs {
+		awsRanges, err = awslib.GetAWSIPRanges()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get the AWS IP ranges: %v", err)
+		}
+	}
+	inv := &Route53Inventory{
+		Zones:   zones,
+		Records: awslib.ResolveRecords(zones, index, awsRanges),
+	}
+	for _, r := range inv.Records {
+		if r.Status == awslib.RecordDangling {
+			inv.Dangling = append(inv.Dangling, r)
+		}
+	}
+	return inv, nil
+}
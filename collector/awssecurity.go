@@ -0,0 +1,78 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+// CollectKMS returns a concurrently collected KMS inventory for all the regions
+func (col AWSCollector) CollectKMS() (map[string][]*awslib.KMSKey, error) {
+	chunks, err := col.collectPerRegion("KMS", func(sess *session.Session) (interface{}, error) {
+		keys, err := awslib.GetAllKMSKeys(sess)
+		// Ignore regions with no keys
+		if keys == nil {
+			return nil, err
+		}
+		return keys, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string][]*awslib.KMSKey)
+	for region, chunk := range chunks {
+		keys[region] = chunk.([]*awslib.KMSKey)
+	}
+	return keys, nil
+}
+
+// CollectSecretsManager returns a concurrently collected inventory of secret metadata for all the regions
+func (col AWSCollector) CollectSecretsManager() (map[string][]*secretsmanager.SecretListEntry, error) {
+	chunks, err := col.collectPerRegion("SecretsManager", func(sess *session.Session) (interface{}, error) {
+		secrets, err := awslib.GetAllSecrets(sess)
+		if secrets == nil {
+			return nil, err
+		}
+		return secrets, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	secrets := make(map[string][]*secretsmanager.SecretListEntry)
+	for region, chunk := range chunks {
+		secrets[region] = chunk.([]*secretsmanager.SecretListEntry)
+	}
+	return secrets, nil
+}
+
+// CollectACM returns a concurrently collected ACM certificate inventory for all the regions
+func (col AWSCollector) CollectACM() (map[string][]*acm.CertificateDetail, error) {
+	chunks, err := col.collectPerRegion("ACM", func(sess *session.Session) (interface{}, error) {
+		certificates, err := awslib.GetAllCertificates(sess)
+		if certificates == nil {
+			return nil, err
+		}
+		return certificates, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	certificates := make(map[string][]*acm.CertificateDetail)
+	for region, chunk := range chunks {
+		certificates[region] = chunk.([]*acm.CertificateDetail)
+	}
+	return certificates, nil
+}
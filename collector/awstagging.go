@@ -0,0 +1,104 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// TaggedResource is a resource discovered through the Resource Groups Tagging API.
+// Collector names the dedicated collector (dump filter) gathering its details, it is empty when there is none
+type TaggedResource struct {
+	*awslib.TaggedResource
+	Collector string
+}
+
+// dedicatedCollectors maps "service:type", or "service" when every type is covered, to the collector gathering it
+var dedicatedCollectors = map[string]string{
+	"ec2:instance":                      "ec2",
+	"ec2:volume":                        "ebs",
+	"ec2:snapshot":                      "ebs",
+	"ec2:image":                         "ebs",
+	"ec2:elastic-ip":                    "addresses",
+	"ec2:network-interface":             "addresses",
+	"ec2:natgateway":                    "addresses",
+	"ec2:launch-template":               "autoscaling",
+	"rds:db":                            "rds",
+	"rds:cluster":                       "rds",
+	"rds:global-cluster":                "rds",
+	"rds:snapshot":                      "rds",
+	"rds:cluster-snapshot":              "rds",
+	"rds:pg":                            "rds",
+	"rds:cluster-pg":                    "rds",
+	"rds:subgrp":                        "rds",
+	"eks":                               "eks",
+	"ecs":                               "ecs",
+	"dynamodb":                          "dynamodb",
+	"elasticache":                       "elasticache",
+	"redshift:cluster":                  "redshift",
+	"iam":                               "iam",
+	"elasticloadbalancing:loadbalancer": "elb",
+	"route53:hostedzone":                "route53",
+	"autoscaling":                       "autoscaling",
+	"cloudformation:stack":              "cloudformation",
+	"sqs":                               "sqs",
+	"sns":                               "sns",
+	"kinesis":                           "kinesis",
+	"firehose":                          "kinesis",
+	"events":                            "eventbridge",
+	"cloudfront:distribution":           "cloudfront",
+	"apigateway":                        "apigateway",
+	"wafv2":                             "waf",
+	"kms:key":                           "kms",
+	"secretsmanager":                    "secretsmanager",
+	"acm":                               "acm",
+	"ecr":                               "ecr",
+	"elasticfilesystem:file-system":     "filesystems",
+	"fsx:file-system":                   "filesystems",
+}
+
+// DedicatedCollector returns the name of the collector gathering the details of the given resource type,
+// or an empty string if the resource is only known through the tagging API
+func DedicatedCollector(service, resourceType string) string {
+	if c, ok := dedicatedCollectors[service+":"+resourceType]; ok {
+		return c
+	}
+	return dedicatedCollectors[service]
+}
+
+// CollectTaggedResources returns a concurrently collected inventory of every resource known
+// to the Resource Groups Tagging API for all the regions
+func (col AWSCollector) CollectTaggedResources() (map[string][]*TaggedResource, error) {
+	chunks, err := col.collectPerRegion("Tagged Resources", func(sess *session.Session) (interface{}, error) {
+		resources, err := awslib.GetAllTaggedResources(sess)
+		// Ignore regions with no tagged resources
+		if resources == nil {
+			return nil, err
+		}
+		return resources, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	resources := make(map[string][]*TaggedResource)
+	for region, chunk := range chunks {
+		for _, r := range chunk.([]*awslib.TaggedResource) {
+			resources[region] = append(resources[region], &TaggedResource{
+				TaggedResource: r,
+				Collector:      DedicatedCollector(r.Service, r.ResourceType),
+			})
+		}
+	}
+	return resources, nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/adobe/cloudinventory/azurelib"
+)
+
+// NewAzureCollector returns an AzureCollector for every enabled subscription the client has access to.
+// Uses the service principal from the standard Azure environment variables if client is not specified
+func NewAzureCollector(client *azurelib.Client) (AzureCollector, error) {
+	col := AzureCollector{client: client}
+	if col.client == nil {
+		var err error
+		if col.client, err = azurelib.NewClientFromEnv(); err != nil {
+			return col, err
+		}
+	}
+	subscriptions, err := col.client.GetAllSubscriptions()
+	if err != nil {
+		return col, fmt.Errorf("Unable to list Azure Subscriptions: %v", err)
+	}
+	for _, s := range subscriptions {
+		// Resources of disabled subscriptions can no longer be listed
+		if s.State == "Disabled" {
+			continue
+		}
+		col.subscriptions = append(col.subscriptions, s.SubscriptionID)
+	}
+	if len(col.subscriptions) == 0 {
+		return col, fmt.Errorf("No enabled Azure Subscription available")
+	}
+	return col, nil
+}
+
+// NewAzureCollectorForSubscriptions returns an AzureCollector for the given subscriptions, or every enabled subscription if none are given.
+// Uses the service principal from the standard Azure environment variables if client is not specified
+func NewAzureCollectorForSubscriptions(client *azurelib.Client, subscriptions []string) (AzureCollector, error) {
+	if len(subscriptions) == 0 {
+		return NewAzureCollector(client)
+	}
+	col := AzureCollector{client: client, subscriptions: subscriptions}
+	if col.client == nil {
+		var err error
+		if col.client, err = azurelib.NewClientFromEnv(); err != nil {
+			return col, err
+		}
+	}
+	return col, nil
+}
+
+// AzureCollector is a concurrent inventory collection struct for Microsoft Azure.
+// Inventories are keyed by subscription ID, every resource carries its location
+type AzureCollector struct {
+	client        *azurelib.Client
+	subscriptions []string
+}
+
+// AzureSQLInventory holds the Azure SQL and PostgreSQL servers of a single subscription
+type AzureSQLInventory struct {
+	SQLServers        []*azurelib.SQLServerDatabases
+	PostgreSQLServers []*azurelib.PostgreSQLServer
+}
+
+// CollectVMs returns a concurrently collected virtual machine inventory for all the subscriptions
+func (col AzureCollector) CollectVMs() (map[string][]*azurelib.VM, error) {
+	chunks, err := col.collectPerSubscription("Azure VM", func(subscription string) (interface{}, error) {
+		vms, err := col.client.GetAllVMs(subscription)
+		// Ignore subscriptions with no virtual machines
+		if vms == nil {
+			return nil, err
+		}
+		return vms, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	vms := make(map[string][]*azurelib.VM)
+	for subscription, chunk := range chunks {
+		vms[subscription] = chunk.([]*azurelib.VM)
+	}
+	return vms, nil
+}
+
+// CollectDisks returns a concurrently collected managed disk inventory for all the subscriptions
+func (col AzureCollector) CollectDisks() (map[string][]*azurelib.Disk, error) {
+	chunks, err := col.collectPerSubscription("Azure Disk", func(subscription string) (interface{}, error) {
+		disks, err := col.client.GetAllDisks(subscription)
+		if disks == nil {
+			return nil, err
+		}
+		return disks, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	disks := make(map[string][]*azurelib.Disk)
+	for subscription, chunk := range chunks {
+		disks[subscription] = chunk.([]*azurelib.Disk)
+	}
+	return disks, nil
+}
+
+// CollectSQL returns a concurrently collected Azure SQL and PostgreSQL inventory for all the subscriptions
+func (col AzureCollector) CollectSQL() (map[string]*AzureSQLInventory, error) {
+	chunks, err := col.collectPerSubscription("Azure SQL", func(subscription string) (interface{}, error) {
+		sqlServers, err := col.client.GetAllSQLServers(subscription)
+		if err != nil {
+			return nil, err
+		}
+		pgServers, err := col.client.GetAllPostgreSQLServers(subscription)
+		if err != nil {
+			return nil, err
+		}
+		if len(sqlServers) == 0 && len(pgServers) == 0 {
+			return nil, nil
+		}
+		return &AzureSQLInventory{SQLServers: sqlServers, PostgreSQLServers: pgServers}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	inventory := make(map[string]*AzureSQLInventory)
+	for subscription, chunk := range chunks {
+		inventory[subscription] = chunk.(*AzureSQLInventory)
+	}
+	return inventory, nil
+}
+
+// collectPerSubscription concurrently runs collect against every subscription.
+// Subscriptions for which collect returns nil are left out of the result
+func (col AzureCollector) collectPerSubscription(service string, collect func(subscription string) (interface{}, error)) (map[string]interface{}, error) {
+	return collectConcurrently(service, col.subscriptions, collect)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adobe/cloudinventory/azurelib"
+)
+
+// TestAzureCollector collects from a local ARM stand-in holding two enabled subscriptions and a disabled one
+func TestAzureCollector(t *testing.T) {
+	responses := map[string]string{
+		"/subscriptions": `{"value":[{"subscriptionId":"sub-1","state":"Enabled"},{"subscriptionId":"sub-2","state":"Warned"},` +
+			`{"subscriptionId":"sub-3","state":"Disabled"}]}`,
+		"/subscriptions/sub-1/providers/Microsoft.Compute/virtualMachines":   `{"value":[{"id":"vm-1","name":"vm-1"}]}`,
+		"/subscriptions/sub-1/providers/Microsoft.Network/networkInterfaces": `{"value":[]}`,
+		"/subscriptions/sub-1/providers/Microsoft.Network/publicIPAddresses": `{"value":[]}`,
+		"/subscriptions/sub-2/providers/Microsoft.Compute/virtualMachines":   `{"value":[]}`,
+		"/subscriptions/sub-1/providers/Microsoft.Compute/disks":             `{"value":[{"id":"disk-1"}]}`,
+		"/subscriptions/sub-2/providers/Microsoft.Compute/disks":             `{"value":[{"id":"disk-2"},{"id":"disk-3"}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"code":"NotFound"}}`)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	col, err := NewAzureCollector(azurelib.NewClientWithHTTPClient(server.URL, server.Client()))
+	if err != nil {
+		t.Fatalf("Failed to create Azure collector: %v", err)
+	}
+	if len(col.subscriptions) != 2 {
+		t.Errorf("Expected the disabled subscription to be skipped, got %v", col.subscriptions)
+	}
+
+	vms, err := col.CollectVMs()
+	if err != nil {
+		t.Fatalf("Failed to collect VMs: %v", err)
+	}
+	if len(vms) != 1 || len(vms["sub-1"]) != 1 {
+		t.Errorf("Expected a single subscription with VMs, got %v", vms)
+	}
+	disks, err := col.CollectDisks()
+	if err != nil {
+		t.Fatalf("Failed to collect disks: %v", err)
+	}
+	if len(disks["sub-1"]) != 1 || len(disks["sub-2"]) != 2 {
+		t.Errorf("Unexpected disks %v", disks)
+	}
+	// SQL servers are not served by the stand-in
+	if _, err := col.CollectSQL(); err == nil {
+		t.Errorf("Expected SQL collection to fail")
+	}
+
+	col, err = NewAzureCollectorForSubscriptions(azurelib.NewClientWithHTTPClient(server.URL, server.Client()), []string{"sub-2"})
+	if err != nil {
+		t.Fatalf("Failed to create Azure collector: %v", err)
+	}
+	if disks, err := col.CollectDisks(); err != nil || len(disks) != 1 || len(disks["sub-2"]) != 2 {
+		t.Errorf("Expected only the given subscription to be collected, got %v: %v", disks, err)
+	}
+}
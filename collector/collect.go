@@ -0,0 +1,62 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+)
+
+// collectConcurrently runs collect for every key (a region, subscription or project) concurrently.
+// Keys for which collect returns nil are left out of the result
+func collectConcurrently(service string, keys []string, collect func(key string) (interface{}, error)) (map[string]interface{}, error) {
+	results := make(map[string]interface{})
+
+	// keyResult holds the inventory collected for a given key
+	type keyResult struct {
+		key    string
+		result interface{}
+	}
+
+	resultChan := make(chan keyResult, len(keys))
+	errChan := make(chan error, len(keys))
+	var wg sync.WaitGroup
+
+	for _, key := range keys {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			chunk, err := collect(key)
+			if err != nil {
+				errChan <- fmt.Errorf("Error while gathering %s: %v", key, err)
+				return
+			}
+			if chunk == nil {
+				return
+			}
+			resultChan <- keyResult{key, chunk}
+		}(key)
+	}
+	wg.Wait()
+	close(resultChan)
+	close(errChan)
+
+	if len(errChan) > 0 {
+		return nil, fmt.Errorf("Failed to gather %s Data: %v", service, <-errChan)
+	}
+
+	for chunk := range resultChan {
+		results[chunk.key] = chunk.result
+	}
+	return results, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/adobe/cloudinventory/dolib"
+)
+
+// NewDigitalOceanCollector returns a DigitalOceanCollector, using DIGITALOCEAN_TOKEN if client is not specified
+func NewDigitalOceanCollector(client *dolib.Client) (DigitalOceanCollector, error) {
+	col := DigitalOceanCollector{client: client}
+	if col.client == nil {
+		var err error
+		if col.client, err = dolib.NewClientFromEnv(); err != nil {
+			return col, err
+		}
+	}
+	return col, nil
+}
+
+// DigitalOceanCollector is an inventory collection struct for DigitalOcean droplets and managed databases
+type DigitalOceanCollector struct {
+	client *dolib.Client
+}
+
+// Name returns digitalocean
+func (col DigitalOceanCollector) Name() string {
+	return "digitalocean"
+}
+
+// Services returns the supported DigitalOcean services
+func (col DigitalOceanCollector) Services() []string {
+	return []string{"databases", "droplets"}
+}
+
+// Collect returns the droplets or the managed databases of the account
+func (col DigitalOceanCollector) Collect(service string) (interface{}, error) {
+	switch service {
+	case "droplets":
+		return col.client.GetAllDroplets()
+	case "databases":
+		return col.client.GetAllDatabases()
+	default:
+		return nil, fmt.Errorf("Unsupported DigitalOcean service %s", service)
+	}
+}
+
+// Hosts returns the droplets of the inventory as hosts
+func (col DigitalOceanCollector) Hosts(inventory map[string]interface{}) ([]*Host, error) {
+	droplets, ok := inventory["droplets"].([]*dolib.Droplet)
+	if !ok {
+		var err error
+		if droplets, err = col.client.GetAllDroplets(); err != nil {
+			return nil, err
+		}
+	}
+	var hosts []*Host
+	for _, d := range droplets {
+		hosts = append(hosts, &Host{
+			Provider:  col.Name(),
+			ID:        strconv.Itoa(d.ID),
+			Name:      d.Name,
+			Region:    d.Region.Slug,
+			Type:      d.SizeSlug,
+			Status:    d.Status,
+			PublicIP:  d.IPv4("public"),
+			PrivateIP: d.IPv4("private"),
+			Tags:      tagSet(d.Tags),
+		})
+	}
+	return hosts, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/adobe/cloudinventory/gcplib"
+)
+
+// NewGCPCollector returns a GCPCollector for the given projects, or every active project the client has access to if none are given.
+// Uses Application Default Credentials if client is not specified
+func NewGCPCollector(client *gcplib.Client, projects []string) (GCPCollector, error) {
+	col := GCPCollector{client: client, projects: projects}
+	if col.client == nil {
+		var err error
+		if col.client, err = gcplib.NewClient(context.Background(), ""); err != nil {
+			return col, err
+		}
+	}
+	if len(col.projects) > 0 {
+		return col, nil
+	}
+	active, err := col.client.GetAllProjects()
+	if err != nil {
+		return col, fmt.Errorf("Unable to list Google Cloud Projects: %v", err)
+	}
+	for _, p := range active {
+		col.projects = append(col.projects, p.ProjectID)
+	}
+	if len(col.projects) == 0 {
+		return col, fmt.Errorf("No active Google Cloud Project available")
+	}
+	return col, nil
+}
+
+// GCPCollector is a concurrent inventory collection struct for Google Cloud.
+// Inventories are keyed by project, then by zone or region like the AWS inventories
+type GCPCollector struct {
+	client   *gcplib.Client
+	projects []string
+}
+
+// CollectComputeInstances returns a concurrently collected Compute Engine inventory for all the projects.
+// Projects without the Compute Engine API enabled are left out
+func (col GCPCollector) CollectComputeInstances() (map[string]map[string][]*gcplib.Instance, error) {
+	chunks, err := col.collectPerProject("Compute Engine", func(project string) (interface{}, error) {
+		instances, err := col.client.GetAllInstances(project)
+		if err != nil {
+			if gcplib.IsServiceDisabled(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if len(instances) == 0 {
+			return nil, nil
+		}
+		return instances, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	instances := make(map[string]map[string][]*gcplib.Instance)
+	for project, chunk := range chunks {
+		instances[project] = chunk.(map[string][]*gcplib.Instance)
+	}
+	return instances, nil
+}
+
+// CollectSQLInstances returns a concurrently collected Cloud SQL inventory for all the projects.
+// Projects without the Cloud SQL Admin API enabled are left out
+func (col GCPCollector) CollectSQLInstances() (map[string]map[string][]*gcplib.SQLInstance, error) {
+	chunks, err := col.collectPerProject("Cloud SQL", func(project string) (interface{}, error) {
+		instances, err := col.client.GetAllSQLInstances(project)
+		if err != nil {
+			if gcplib.IsServiceDisabled(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if len(instances) == 0 {
+			return nil, nil
+		}
+		return instances, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	instances := make(map[string]map[string][]*gcplib.SQLInstance)
+	for project, chunk := range chunks {
+		instances[project] = chunk.(map[string][]*gcplib.SQLInstance)
+	}
+	return instances, nil
+}
+
+// collectPerProject concurrently runs collect against every project.
+// Projects for which collect returns nil are left out of the result
+func (col GCPCollector) collectPerProject(service string, collect func(project string) (interface{}, error)) (map[string]interface{}, error) {
+	return collectConcurrently(service, col.projects, collect)
+}
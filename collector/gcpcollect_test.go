@@ -0,0 +1,66 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adobe/cloudinventory/gcplib"
+)
+
+// TestGCPCollector collects from a local stand-in holding a project with instances and one with Compute Engine disabled
+func TestGCPCollector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/projects":
+			fmt.Fprint(w, `{"projects":[{"projectId":"one"},{"projectId":"two"}]}`)
+		case "/compute/v1/projects/one/aggregated/instances":
+			fmt.Fprint(w, `{"items":{"zones/us-central1-a":{"instances":[{"id":"1","name":"web"}]}}}`)
+		case "/compute/v1/projects/two/aggregated/instances":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error":{"code":403,"errors":[{"reason":"accessNotConfigured"}]}}`)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error":{"code":403,"status":"PERMISSION_DENIED","errors":[{"reason":"forbidden"}]}}`)
+		}
+	}))
+	defer server.Close()
+	client := gcplib.NewClientWithHTTPClient(server.URL, server.Client())
+
+	col, err := NewGCPCollector(client, nil)
+	if err != nil {
+		t.Fatalf("Failed to create GCP collector: %v", err)
+	}
+	if len(col.projects) != 2 {
+		t.Errorf("Expected projects to be listed, got %v", col.projects)
+	}
+	instances, err := col.CollectComputeInstances()
+	if err != nil {
+		t.Fatalf("Failed to collect instances: %v", err)
+	}
+	if len(instances) != 1 || len(instances["one"]["us-central1-a"]) != 1 {
+		t.Errorf("Unexpected instances %v", instances)
+	}
+	// Permission errors other than a disabled API are not ignored
+	if _, err := col.CollectSQLInstances(); err == nil {
+		t.Errorf("Expected SQL collection to fail")
+	}
+
+	col, err = NewGCPCollector(client, []string{"one"})
+	if err != nil || len(col.projects) != 1 {
+		t.Errorf("Expected only the given project to be collected, got %v: %v", col.projects, err)
+	}
+}
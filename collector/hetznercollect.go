@@ -0,0 +1,84 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/adobe/cloudinventory/hetznerlib"
+)
+
+// NewHetznerCollector returns a HetznerCollector, using HCLOUD_TOKEN if client is not specified.
+// Hetzner Cloud tokens are scoped to a single project
+func NewHetznerCollector(client *hetznerlib.Client) (HetznerCollector, error) {
+	col := HetznerCollector{client: client}
+	if col.client == nil {
+		var err error
+		if col.client, err = hetznerlib.NewClientFromEnv(); err != nil {
+			return col, err
+		}
+	}
+	return col, nil
+}
+
+// HetznerCollector is an inventory collection struct for Hetzner Cloud servers
+type HetznerCollector struct {
+	client *hetznerlib.Client
+}
+
+// Name returns hetzner
+func (col HetznerCollector) Name() string {
+	return "hetzner"
+}
+
+// Services returns the supported Hetzner Cloud services
+func (col HetznerCollector) Services() []string {
+	return []string{"servers"}
+}
+
+// Collect returns the servers of the project
+func (col HetznerCollector) Collect(service string) (interface{}, error) {
+	switch service {
+	case "servers":
+		return col.client.GetAllServers()
+	default:
+		return nil, fmt.Errorf("Unsupported Hetzner Cloud service %s", service)
+	}
+}
+
+// Hosts returns the servers of the inventory as hosts, their region being the location of the server
+func (col HetznerCollector) Hosts(inventory map[string]interface{}) ([]*Host, error) {
+	servers, ok := inventory["servers"].([]*hetznerlib.Server)
+	if !ok {
+		var err error
+		if servers, err = col.client.GetAllServers(); err != nil {
+			return nil, err
+		}
+	}
+	var hosts []*Host
+	for _, s := range servers {
+		hosts = append(hosts, &Host{
+			Provider:  col.Name(),
+			ID:        strconv.Itoa(s.ID),
+			Name:      s.Name,
+			Region:    s.Datacenter.Location.Name,
+			Type:      s.ServerType.Name,
+			Status:    s.Status,
+			PublicIP:  s.PublicIPv4(),
+			PrivateIP: s.PrivateIPv4(),
+			Tags:      s.Labels,
+		})
+	}
+	return hosts, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/adobe/cloudinventory/k8slib"
+	"k8s.io/client-go/kubernetes"
+)
+
+// NewKubernetesCollector returns a KubernetesCollector for the given contexts of kubeconfig, or its current context if none are given.
+// The standard loading rules (KUBECONFIG, ~/.kube/config) apply when kubeconfig is empty
+func NewKubernetesCollector(kubeconfig string, contexts []string) (KubernetesCollector, error) {
+	col := KubernetesCollector{clients: make(map[string]kubernetes.Interface)}
+	if len(contexts) == 0 {
+		current, err := k8slib.GetCurrentContext(kubeconfig)
+		if err != nil {
+			return col, err
+		}
+		client, err := k8slib.NewClient(kubeconfig, "")
+		if err != nil {
+			return col, err
+		}
+		if current == "" {
+			current = "in-cluster"
+		}
+		col.clients[current] = client
+		return col, nil
+	}
+	for _, context := range contexts {
+		client, err := k8slib.NewClient(kubeconfig, context)
+		if err != nil {
+			return col, err
+		}
+		col.clients[context] = client
+	}
+	return col, nil
+}
+
+// NewKubernetesCollectorWithClients returns a KubernetesCollector for the given clients keyed by context
+func NewKubernetesCollectorWithClients(clients map[string]kubernetes.Interface) KubernetesCollector {
+	return KubernetesCollector{clients: clients}
+}
+
+// KubernetesCollector is a concurrent inventory collection struct for Kubernetes clusters.
+// Inventories are keyed by kubeconfig context, "in-cluster" when running inside a cluster without a kubeconfig
+type KubernetesCollector struct {
+	clients map[string]kubernetes.Interface
+}
+
+// CollectClusters returns a concurrently collected inventory of every cluster
+func (col KubernetesCollector) CollectClusters() (map[string]*k8slib.ClusterInventory, error) {
+	var contexts []string
+	for context := range col.clients {
+		contexts = append(contexts, context)
+	}
+	sort.Strings(contexts)
+	chunks, err := collectConcurrently("Kubernetes", contexts, func(context string) (interface{}, error) {
+		inv, err := k8slib.GetClusterInventory(col.clients[context])
+		if err != nil {
+			return nil, fmt.Errorf("context %s: %v", context, err)
+		}
+		return inv, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	clusters := make(map[string]*k8slib.ClusterInventory)
+	for context, chunk := range chunks {
+		clusters[context] = chunk.(*k8slib.ClusterInventory)
+	}
+	return clusters, nil
+}
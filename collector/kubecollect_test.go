@@ -0,0 +1,37 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestKubernetesCollector collects two fake clusters keyed by context
+func TestKubernetesCollector(t *testing.T) {
+	col := NewKubernetesCollectorWithClients(map[string]kubernetes.Interface{
+		"prod": fake.NewSimpleClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}),
+		"dev":  fake.NewSimpleClientset(),
+	})
+	clusters, err := col.CollectClusters()
+	if err != nil {
+		t.Fatalf("Failed to collect clusters: %v", err)
+	}
+	if len(clusters) != 2 || len(clusters["prod"].Nodes) != 1 || len(clusters["dev"].Nodes) != 0 {
+		t.Errorf("Unexpected clusters %+v", clusters)
+	}
+}
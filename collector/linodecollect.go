@@ -0,0 +1,83 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/adobe/cloudinventory/linodelib"
+)
+
+// NewLinodeCollector returns a LinodeCollector, using LINODE_TOKEN if client is not specified
+func NewLinodeCollector(client *linodelib.Client) (LinodeCollector, error) {
+	col := LinodeCollector{client: client}
+	if col.client == nil {
+		var err error
+		if col.client, err = linodelib.NewClientFromEnv(); err != nil {
+			return col, err
+		}
+	}
+	return col, nil
+}
+
+// LinodeCollector is an inventory collection struct for Linode compute instances
+type LinodeCollector struct {
+	client *linodelib.Client
+}
+
+// Name returns linode
+func (col LinodeCollector) Name() string {
+	return "linode"
+}
+
+// Services returns the supported Linode services
+func (col LinodeCollector) Services() []string {
+	return []string{"instances"}
+}
+
+// Collect returns the compute instances of the account
+func (col LinodeCollector) Collect(service string) (interface{}, error) {
+	switch service {
+	case "instances":
+		return col.client.GetAllInstances()
+	default:
+		return nil, fmt.Errorf("Unsupported Linode service %s", service)
+	}
+}
+
+// Hosts returns the compute instances of the inventory as hosts
+func (col LinodeCollector) Hosts(inventory map[string]interface{}) ([]*Host, error) {
+	instances, ok := inventory["instances"].([]*linodelib.Instance)
+	if !ok {
+		var err error
+		if instances, err = col.client.GetAllInstances(); err != nil {
+			return nil, err
+		}
+	}
+	var hosts []*Host
+	for _, i := range instances {
+		hosts = append(hosts, &Host{
+			Provider:  col.Name(),
+			ID:        strconv.Itoa(i.ID),
+			Name:      i.Label,
+			Region:    i.Region,
+			Type:      i.Type,
+			Status:    i.Status,
+			PublicIP:  i.PublicIPv4(),
+			PrivateIP: i.PrivateIPv4(),
+			Tags:      tagSet(i.Tags),
+		})
+	}
+	return hosts, nil
+}
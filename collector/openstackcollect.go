@@ -0,0 +1,92 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/adobe/cloudinventory/openstacklib"
+)
+
+// NewOpenStackCollector returns an OpenStackCollector, authenticating as the cloud selected by OS_CLOUD in the standard
+// clouds.yaml locations if client is not specified
+func NewOpenStackCollector(client *openstacklib.Client) (OpenStackCollector, error) {
+	col := OpenStackCollector{client: client}
+	if col.client == nil {
+		cloud, err := openstacklib.LoadCloud("", "")
+		if err != nil {
+			return col, err
+		}
+		if col.client, err = openstacklib.NewClient(cloud); err != nil {
+			return col, err
+		}
+	}
+	return col, nil
+}
+
+// OpenStackCollector is an inventory collection struct for the Nova servers, Cinder volumes and Neutron ports of an OpenStack project
+type OpenStackCollector struct {
+	client *openstacklib.Client
+}
+
+// Name returns openstack
+func (col OpenStackCollector) Name() string {
+	return "openstack"
+}
+
+// Services returns the supported OpenStack services
+func (col OpenStackCollector) Services() []string {
+	return []string{"ports", "servers", "volumes"}
+}
+
+// Collect returns the servers, volumes or ports of the project
+func (col OpenStackCollector) Collect(service string) (interface{}, error) {
+	switch service {
+	case "servers":
+		return col.client.GetAllServers()
+	case "volumes":
+		return col.client.GetAllVolumes()
+	case "ports":
+		return col.client.GetAllPorts()
+	default:
+		return nil, fmt.Errorf("Unsupported OpenStack service %s", service)
+	}
+}
+
+// Hosts returns the servers of the inventory as hosts, their region being the region of the client, or of its
+// compute endpoint when clouds.yaml sets none.
+// Floating IPs are used as public IPs
+func (col OpenStackCollector) Hosts(inventory map[string]interface{}) ([]*Host, error) {
+	servers, ok := inventory["servers"].([]*openstacklib.Server)
+	if !ok {
+		var err error
+		if servers, err = col.client.GetAllServers(); err != nil {
+			return nil, err
+		}
+	}
+	var hosts []*Host
+	for _, s := range servers {
+		hosts = append(hosts, &Host{
+			Provider:  col.Name(),
+			ID:        s.ID,
+			Name:      s.Name,
+			Region:    col.client.Region(),
+			Type:      s.Flavor.OriginalName,
+			Status:    s.Status,
+			PublicIP:  s.IPv4("floating"),
+			PrivateIP: s.IPv4("fixed"),
+			Tags:      s.Metadata,
+		})
+	}
+	return hosts, nil
+}
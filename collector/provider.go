@@ -0,0 +1,54 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+// Host is a compute instance normalized across providers, so that inventories spanning
+// several clouds (e.g. Ansible inventories) can be built the same way for each of them
+type Host struct {
+	Provider  string
+	ID        string
+	Name      string
+	Region    string
+	Type      string
+	Status    string
+	PublicIP  string
+	PrivateIP string
+	Tags      map[string]string
+}
+
+// Provider is implemented by the collectors of providers serving every service from a single API endpoint.
+// Each service is collected whole, the hosts are derived from the collected inventory.
+// Providers holding a session also implement io.Closer, to be called once done with them
+type Provider interface {
+	// Name returns the name of the provider, as used by the dump command
+	Name() string
+	// Services returns the services the provider supports, in a stable order
+	Services() []string
+	// Collect returns the inventory of the given service
+	Collect(service string) (interface{}, error)
+	// Hosts returns the hosts found in an inventory built with Collect, keyed by service.
+	// The service holding the hosts is collected if it is missing
+	Hosts(inventory map[string]interface{}) ([]*Host, error)
+}
+
+// tagSet returns tags, which these providers store as plain strings, as a Host tag map with empty values
+func tagSet(tags []string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	set := make(map[string]string)
+	for _, t := range tags {
+		set[t] = ""
+	}
+	return set
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adobe/cloudinventory/dolib"
+	"github.com/adobe/cloudinventory/hetznerlib"
+	"github.com/adobe/cloudinventory/linodelib"
+	"github.com/adobe/cloudinventory/openstacklib"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+)
+
+// TestProviders collects the hosts of every provider from a local stand-in serving all of their APIs
+func TestProviders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/droplets":
+			fmt.Fprint(w, `{"droplets":[{"id":1,"name":"web","status":"active","region":{"slug":"nyc1"},"size_slug":"s-1vcpu-1gb",
+				"networks":{"v4":[{"ip_address":"203.0.113.1","type":"public"}]},"tags":["prod"]}]}`)
+		case "/v2/databases":
+			fmt.Fprint(w, `{"databases":[{"id":"db-1","name":"pg","engine":"pg","region":"nyc1"}]}`)
+		case "/v4/linode/instances":
+			fmt.Fprint(w, `{"data":[{"id":2,"label":"app","region":"us-east","type":"g6-nanode-1","ipv4":["203.0.113.2"]}],"page":1,"pages":1}`)
+		case "/v1/servers":
+			fmt.Fprint(w, `{"servers":[{"id":3,"name":"cache","labels":{"env":"prod"},"public_net":{"ipv4":{"ip":"203.0.113.3"}},
+				"datacenter":{"location":{"name":"fsn1"}}}],"meta":{"pagination":{"next_page":null}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	do, err := NewDigitalOceanCollector(dolib.NewClientWithHTTPClient(server.URL, server.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	linode, err := NewLinodeCollector(linodelib.NewClientWithHTTPClient(server.URL, server.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hetzner, err := NewHetznerCollector(hetznerlib.NewClientWithHTTPClient(server.URL, server.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]Host{
+		"digitalocean": {ID: "1", Name: "web", Region: "nyc1", PublicIP: "203.0.113.1"},
+		"linode":       {ID: "2", Name: "app", Region: "us-east", PublicIP: "203.0.113.2"},
+		"hetzner":      {ID: "3", Name: "cache", Region: "fsn1", PublicIP: "203.0.113.3"},
+	}
+	for _, p := range []Provider{do, linode, hetzner} {
+		inventory := make(map[string]interface{})
+		for _, service := range p.Services() {
+			if inventory[service], err = p.Collect(service); err != nil {
+				t.Fatalf("Failed to collect %s %s: %v", p.Name(), service, err)
+			}
+		}
+		if _, err := p.Collect("unknown"); err == nil {
+			t.Errorf("Expected an unknown %s service to fail", p.Name())
+		}
+		hosts, err := p.Hosts(inventory)
+		if err != nil {
+			t.Fatalf("Failed to get %s hosts: %v", p.Name(), err)
+		}
+		e := expected[p.Name()]
+		if len(hosts) != 1 || hosts[0].Provider != p.Name() || hosts[0].ID != e.ID || hosts[0].Name != e.Name ||
+			hosts[0].Region != e.Region || hosts[0].PublicIP != e.PublicIP {
+			t.Fatalf("Unexpected %s hosts %+v", p.Name(), hosts)
+		}
+		if len(hosts[0].Tags) != 1 && p.Name() != "linode" {
+			t.Errorf("Expected %s tags to be kept, got %v", p.Name(), hosts[0].Tags)
+		}
+	}
+
+	// Hosts are collected when missing from the inventory
+	if hosts, err := do.Hosts(map[string]interface{}{}); err != nil || len(hosts) != 1 {
+		t.Errorf("Expected droplets to be collected, got %v: %v", hosts, err)
+	}
+}
+
+// TestOpenStackCollector collects the servers of a local Keystone and Nova stand-in as hosts
+func TestOpenStackCollector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/auth/tokens":
+			w.Header().Set("X-Subject-Token", "test-token")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, strings.Replace(`{"token":{"catalog":[{"type":"compute",
+				"endpoints":[{"interface":"public","region":"RegionOne","url":"{{endpoint}}/compute"}]}]}}`, "{{endpoint}}", "http://"+r.Host, -1))
+		case "/compute/servers/detail":
+			fmt.Fprint(w, `{"servers":[{"id":"s-1","name":"web","status":"ACTIVE","flavor":{"original_name":"m1.small"},
+				"addresses":{"private":[{"addr":"10.0.0.5","version":4,"OS-EXT-IPS:type":"fixed"}]}}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	// Without a region_name, hosts take the region of the compute endpoint
+	cloud := &openstacklib.Cloud{}
+	cloud.Auth.AuthURL = server.URL
+	client, err := openstacklib.NewClientWithHTTPClient(cloud, server.Client())
+	if err != nil {
+		t.Fatalf("Failed to authenticate: %v", err)
+	}
+	col, err := NewOpenStackCollector(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts, err := col.Hosts(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Failed to get hosts: %v", err)
+	}
+	if len(hosts) != 1 || hosts[0].Region != "RegionOne" || hosts[0].Type != "m1.small" || hosts[0].PrivateIP != "10.0.0.5" {
+		t.Errorf("Unexpected hosts %+v", hosts)
+	}
+	// Volumes were not added to the catalog
+	if _, err := col.Collect("volumes"); err == nil {
+		t.Errorf("Expected volumes to fail without a block-storage endpoint")
+	}
+}
+
+// TestVSphereCollector collects the virtual machines of a simulated vCenter as hosts
+func TestVSphereCollector(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		col, err := NewVSphereCollector(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inventory := make(map[string]interface{})
+		for _, service := range col.Services() {
+			if inventory[service], err = col.Collect(service); err != nil {
+				t.Fatalf("Failed to collect %s: %v", service, err)
+			}
+		}
+		hosts, err := col.Hosts(inventory)
+		if err != nil {
+			t.Fatalf("Failed to get hosts: %v", err)
+		}
+		if len(hosts) != 4 || hosts[0].Provider != "vsphere" || hosts[0].Region != "DC0" || hosts[0].ID == "" {
+			t.Errorf("Unexpected hosts %+v", hosts)
+		}
+		if err := col.Close(); err != nil {
+			t.Fatalf("Failed to log out: %v", err)
+		}
+		if _, err := col.Collect("vms"); err == nil {
+			t.Errorf("Collecting should fail once logged out")
+		}
+	})
+}
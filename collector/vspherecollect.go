@@ -0,0 +1,95 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/adobe/cloudinventory/vspherelib"
+	"github.com/vmware/govmomi/vim25"
+)
+
+// NewVSphereCollector returns a VSphereCollector, logging in as configured by the GOVC_* environment variables if client is not specified
+func NewVSphereCollector(client *vim25.Client) (VSphereCollector, error) {
+	col := VSphereCollector{client: client}
+	if col.client == nil {
+		var err error
+		if col.client, err = vspherelib.NewClientFromEnv(context.Background()); err != nil {
+			return col, err
+		}
+	}
+	return col, nil
+}
+
+// VSphereCollector is an inventory collection struct for the virtual machines, hosts and datastores of a vCenter or ESXi host
+type VSphereCollector struct {
+	client *vim25.Client
+}
+
+// Name returns vsphere
+func (col VSphereCollector) Name() string {
+	return "vsphere"
+}
+
+// Services returns the supported vSphere services
+func (col VSphereCollector) Services() []string {
+	return []string{"datastores", "hosts", "vms"}
+}
+
+// Collect returns the virtual machines, hosts or datastores of every datacenter
+func (col VSphereCollector) Collect(service string) (interface{}, error) {
+	switch service {
+	case "vms":
+		return vspherelib.GetAllVMs(context.Background(), col.client)
+	case "hosts":
+		return vspherelib.GetAllHosts(context.Background(), col.client)
+	case "datastores":
+		return vspherelib.GetAllDatastores(context.Background(), col.client)
+	default:
+		return nil, fmt.Errorf("Unsupported vSphere service %s", service)
+	}
+}
+
+// Close logs out of the vSphere session of the collector, which cannot collect anymore afterwards
+func (col VSphereCollector) Close() error {
+	return vspherelib.Logout(context.Background(), col.client)
+}
+
+// Hosts returns the virtual machines of the inventory as hosts, their region being their datacenter.
+// Templates are left out, the IP reported by VMware Tools is used as private IP
+func (col VSphereCollector) Hosts(inventory map[string]interface{}) ([]*Host, error) {
+	vms, ok := inventory["vms"].([]*vspherelib.VirtualMachine)
+	if !ok {
+		var err error
+		if vms, err = vspherelib.GetAllVMs(context.Background(), col.client); err != nil {
+			return nil, err
+		}
+	}
+	var hosts []*Host
+	for _, vm := range vms {
+		if vm.Template {
+			continue
+		}
+		hosts = append(hosts, &Host{
+			Provider:  col.Name(),
+			ID:        vm.InstanceUUID,
+			Name:      vm.Name,
+			Region:    vm.Datacenter,
+			Type:      fmt.Sprintf("%d vCPU, %d MB", vm.NumCPU, vm.MemoryMB),
+			Status:    vm.PowerState,
+			PrivateIP: vm.IPAddress,
+		})
+	}
+	return hosts, nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package dolib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/adobe/cloudinventory/restlib"
+	"golang.org/x/oauth2"
+)
+
+// Endpoint is the DigitalOcean API endpoint
+const Endpoint = "https://api.digitalocean.com"
+
+// Client is a minimal DigitalOcean REST client, only supporting the list operations used for inventory
+type Client struct {
+	endpoint string
+	http     *http.Client
+}
+
+// NewClient returns a Client authenticating with the given personal access token
+func NewClient(token string) *Client {
+	return newClient(Endpoint, token)
+}
+
+// NewClientFromEnv returns a Client authenticating with the token set in DIGITALOCEAN_TOKEN
+func NewClientFromEnv() (*Client, error) {
+	token := os.Getenv("DIGITALOCEAN_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("Failed to get DigitalOcean Credentials, set DIGITALOCEAN_TOKEN")
+	}
+	return NewClient(token), nil
+}
+
+// NewClientWithHTTPClient returns a Client sending its requests to endpoint through httpClient,
+// which is expected to take care of authentication
+func NewClientWithHTTPClient(endpoint string, httpClient *http.Client) *Client {
+	return &Client{endpoint: strings.TrimSuffix(endpoint, "/"), http: httpClient}
+}
+
+func newClient(endpoint, token string) *Client {
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+	return NewClientWithHTTPClient(endpoint, oauth2.NewClient(context.Background(), ts))
+}
+
+// Error is returned for API requests which did not succeed
+type Error struct {
+	StatusCode int
+	ID         string
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("DigitalOcean request failed with status %d: %s %s", e.StatusCode, e.ID, e.Message)
+}
+
+// list calls the list operation at path, following the next page links until the key array
+// of every page was passed to appendPage
+func (c *Client) list(path, key string, appendPage func(items json.RawMessage) error) error {
+	next := fmt.Sprintf("%s%s?per_page=200", c.endpoint, path)
+	for next != "" {
+		var page map[string]json.RawMessage
+		if err := c.get(next, &page); err != nil {
+			return err
+		}
+		if items, ok := page[key]; ok && string(items) != "null" {
+			if err := appendPage(items); err != nil {
+				return err
+			}
+		}
+		next = ""
+		if links, ok := page["links"]; ok {
+			var l struct {
+				Pages struct {
+					Next string `json:"next"`
+				} `json:"pages"`
+			}
+			if err := json.Unmarshal(links, &l); err != nil {
+				return err
+			}
+			next = l.Pages.Next
+		}
+	}
+	return nil
+}
+
+// get decodes the JSON document at u into v, retrying rate limited requests
+func (c *Client) get(u string, v interface{}) error {
+	status, body, err := restlib.Get(c.http, u)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		var apiErr struct {
+			ID      string `json:"id"`
+			Message string `json:"message"`
+		}
+		json.Unmarshal(body, &apiErr)
+		return &Error{StatusCode: status, ID: apiErr.ID, Message: apiErr.Message}
+	}
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package dolib
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestAPI returns a local stand-in for the DigitalOcean API serving the given responses by path and page.
+// Every request has to carry the test token, {{endpoint}} in responses is replaced with the URL of the stand-in
+func newTestAPI(t *testing.T, responses map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"id":"unauthorized","message":"Unable to authenticate you"}`)
+			return
+		}
+		key := r.URL.Path
+		if page := r.URL.Query().Get("page"); page != "" {
+			key += "?page=" + page
+		}
+		body, ok := responses[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"id":"not_found","message":"The resource you were accessing could not be found."}`)
+			return
+		}
+		fmt.Fprint(w, strings.Replace(body, "{{endpoint}}", "http://"+r.Host, -1))
+	}))
+}
+
+// TestGetAllDroplets checks that the client authenticates and follows next page links
+func TestGetAllDroplets(t *testing.T) {
+	server := newTestAPI(t, map[string]string{
+		"/v2/droplets": `{"droplets":[{"id":1,"name":"web","region":{"slug":"nyc1"},"size_slug":"s-1vcpu-1gb",
+			"networks":{"v4":[{"ip_address":"10.10.0.2","type":"private"},{"ip_address":"203.0.113.2","type":"public"}]}}],
+			"links":{"pages":{"next":"{{endpoint}}/v2/droplets?page=2&per_page=200"}}}`,
+		"/v2/droplets?page=2": `{"droplets":[{"id":2,"name":"db","region":{"slug":"ams3"},"tags":["prod"]}],"links":{}}`,
+	})
+	defer server.Close()
+	droplets, err := newClient(server.URL, "test-token").GetAllDroplets()
+	if err != nil {
+		t.Fatalf("Failed to list droplets: %v", err)
+	}
+	if len(droplets) != 2 || droplets[1].Region.Slug != "ams3" || droplets[1].Tags[0] != "prod" {
+		t.Fatalf("Unexpected droplets %+v", droplets)
+	}
+	if droplets[0].IPv4("public") != "203.0.113.2" || droplets[0].IPv4("private") != "10.10.0.2" || droplets[1].IPv4("public") != "" {
+		t.Errorf("Unexpected addresses %+v", droplets[0].Networks)
+	}
+}
+
+// TestGetAllDatabases checks that database clusters are listed and errors surfaced
+func TestGetAllDatabases(t *testing.T) {
+	server := newTestAPI(t, map[string]string{
+		"/v2/databases": `{"databases":[{"id":"db-1","name":"pg","engine":"pg","version":"16","region":"nyc1",
+			"connection":{"host":"pg.db.ondigitalocean.com","port":25060,"user":"doadmin","password":"secret","ssl":true}}]}`,
+	})
+	defer server.Close()
+	databases, err := newClient(server.URL, "test-token").GetAllDatabases()
+	if err != nil {
+		t.Fatalf("Failed to list databases: %v", err)
+	}
+	if len(databases) != 1 || databases[0].Connection.Port != 25060 || databases[0].Engine != "pg" {
+		t.Errorf("Unexpected databases %+v", databases)
+	}
+
+	_, err = newClient(server.URL, "wrong").GetAllDatabases()
+	apiErr, ok := err.(*Error)
+	if !ok || apiErr.StatusCode != http.StatusUnauthorized || apiErr.ID != "unauthorized" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package dolib
+
+import (
+	"encoding/json"
+)
+
+// Droplet holds a DigitalOcean droplet
+type Droplet struct {
+	ID        int      `json:"id"`
+	Name      string   `json:"name"`
+	Memory    int      `json:"memory"`
+	VCPUs     int      `json:"vcpus"`
+	Disk      int      `json:"disk"`
+	Status    string   `json:"status"`
+	CreatedAt string   `json:"created_at"`
+	SizeSlug  string   `json:"size_slug"`
+	VPCUUID   string   `json:"vpc_uuid"`
+	Tags      []string `json:"tags"`
+	Region    struct {
+		Slug string `json:"slug"`
+		Name string `json:"name"`
+	} `json:"region"`
+	Image struct {
+		ID           int    `json:"id"`
+		Name         string `json:"name"`
+		Distribution string `json:"distribution"`
+	} `json:"image"`
+	Networks struct {
+		V4 []NetworkAddress `json:"v4"`
+		V6 []NetworkAddress `json:"v6"`
+	} `json:"networks"`
+}
+
+// NetworkAddress holds an address of a droplet, Type is either public or private
+type NetworkAddress struct {
+	IPAddress string `json:"ip_address"`
+	Type      string `json:"type"`
+}
+
+// IPv4 returns the first IPv4 address of the droplet with the given type, public or private
+func (d *Droplet) IPv4(addressType string) string {
+	for _, a := range d.Networks.V4 {
+		if a.Type == addressType {
+			return a.IPAddress
+		}
+	}
+	return ""
+}
+
+// Database holds a DigitalOcean managed database cluster
+type Database struct {
+	ID                 string              `json:"id"`
+	Name               string              `json:"name"`
+	Engine             string              `json:"engine"`
+	Version            string              `json:"version"`
+	NumNodes           int                 `json:"num_nodes"`
+	Size               string              `json:"size"`
+	Region             string              `json:"region"`
+	Status             string              `json:"status"`
+	CreatedAt          string              `json:"created_at"`
+	PrivateNetworkUUID string              `json:"private_network_uuid"`
+	DBNames            []string            `json:"db_names"`
+	Tags               []string            `json:"tags"`
+	Connection         *DatabaseConnection `json:"connection"`
+	PrivateConnection  *DatabaseConnection `json:"private_connection"`
+}
+
+// DatabaseConnection holds the endpoint of a database cluster, credentials are left out
+type DatabaseConnection struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	SSL  bool   `json:"ssl"`
+}
+
+// GetAllDroplets returns every droplet of the account
+func (c *Client) GetAllDroplets() ([]*Droplet, error) {
+	var allDroplets []*Droplet
+	err := c.list("/v2/droplets", "droplets", func(items json.RawMessage) error {
+		var page []*Droplet
+		if err := json.Unmarshal(items, &page); err != nil {
+			return err
+		}
+		allDroplets = append(allDroplets, page...)
+		return nil
+	})
+	return allDroplets, err
+}
+
+// GetAllDatabases returns every managed database cluster of the account
+func (c *Client) GetAllDatabases() ([]*Database, error) {
+	var allDatabases []*Database
+	err := c.list("/v2/databases", "databases", func(items json.RawMessage) error {
+		var page []*Database
+		if err := json.Unmarshal(items, &page); err != nil {
+			return err
+		}
+		allDatabases = append(allDatabases, page...)
+		return nil
+	})
+	return allDatabases, err
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package gcplib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/adobe/cloudinventory/restlib"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// scope is the OAuth2 scope requested for every API, access remains restricted by the IAM roles of the credentials
+const scope = "https://www.googleapis.com/auth/cloud-platform"
+
+// Client is a minimal Google Cloud REST client, only supporting the list operations used for inventory
+type Client struct {
+	computeEndpoint         string
+	sqlEndpoint             string
+	resourceManagerEndpoint string
+	http                    *http.Client
+}
+
+// NewClient returns a Client authenticating with the service account key in credentialsFile,
+// or with Application Default Credentials if credentialsFile is empty
+func NewClient(ctx context.Context, credentialsFile string) (*Client, error) {
+	var httpClient *http.Client
+	if credentialsFile == "" {
+		var err error
+		if httpClient, err = google.DefaultClient(ctx, scope); err != nil {
+			return nil, fmt.Errorf("Failed to get Google Cloud Application Default Credentials: %v", err)
+		}
+	} else {
+		data, err := ioutil.ReadFile(credentialsFile)
+		if err != nil {
+			return nil, err
+		}
+		creds, err := google.CredentialsFromJSON(ctx, data, scope)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid Google Cloud credentials %s: %v", credentialsFile, err)
+		}
+		httpClient = oauth2.NewClient(ctx, creds.TokenSource)
+	}
+	return &Client{
+		computeEndpoint:         "https://compute.googleapis.com",
+		sqlEndpoint:             "https://sqladmin.googleapis.com",
+		resourceManagerEndpoint: "https://cloudresourcemanager.googleapis.com",
+		http:                    httpClient,
+	}, nil
+}
+
+// NewClientWithHTTPClient returns a Client sending the requests of every API to endpoint through httpClient,
+// which is expected to take care of authentication
+func NewClientWithHTTPClient(endpoint string, httpClient *http.Client) *Client {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	return &Client{
+		computeEndpoint:         endpoint,
+		sqlEndpoint:             endpoint,
+		resourceManagerEndpoint: endpoint,
+		http:                    httpClient,
+	}
+}
+
+// Error is returned for Google Cloud requests which did not succeed
+type Error struct {
+	StatusCode int
+	Status     string
+	Message    string
+	Reasons    []string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Google Cloud request failed with status %d: %s %s", e.StatusCode, e.Status, e.Message)
+}
+
+// IsServiceDisabled reports whether err was caused by an API not being enabled for the project,
+// which for inventory purposes means the project holds no such resources
+func IsServiceDisabled(err error) bool {
+	gerr, ok := err.(*Error)
+	if !ok || gerr.StatusCode != http.StatusForbidden {
+		return false
+	}
+	for _, r := range gerr.Reasons {
+		if r == "accessNotConfigured" || r == "SERVICE_DISABLED" {
+			return true
+		}
+	}
+	return false
+}
+
+// list calls the list operation at u, following nextPageToken until every page was passed to appendPage
+func (c *Client) list(u string, appendPage func(page []byte) error) error {
+	pageToken := ""
+	for {
+		pageURL := u
+		if pageToken != "" {
+			separator := "?"
+			if strings.Contains(u, "?") {
+				separator = "&"
+			}
+			pageURL += separator + "pageToken=" + url.QueryEscape(pageToken)
+		}
+		body, err := c.get(pageURL)
+		if err != nil {
+			return err
+		}
+		if err := appendPage(body); err != nil {
+			return err
+		}
+		var page struct {
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		if page.NextPageToken == "" {
+			return nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// get returns the body of u, retrying rate limited requests
+func (c *Client) get(u string) ([]byte, error) {
+	status, body, err := restlib.Get(c.http, u)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, parseError(status, body)
+	}
+	return body, nil
+}
+
+// parseError builds an Error from a Google Cloud error response, which lists reasons either
+// in errors (older APIs such as Compute Engine) or in details (newer ones)
+func parseError(statusCode int, body []byte) *Error {
+	var gerr struct {
+		Error struct {
+			Message string `json:"message"`
+			Status  string `json:"status"`
+			Errors  []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+			Details []struct {
+				Reason string `json:"reason"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	json.Unmarshal(body, &gerr)
+	e := &Error{StatusCode: statusCode, Status: gerr.Error.Status, Message: gerr.Error.Message}
+	for _, r := range gerr.Error.Errors {
+		e.Reasons = append(e.Reasons, r.Reason)
+	}
+	for _, d := range gerr.Error.Details {
+		if d.Reason != "" {
+			e.Reasons = append(e.Reasons, d.Reason)
+		}
+	}
+	return e
+}
+
+// Project holds a Google Cloud project the credentials have access to
+type Project struct {
+	ProjectID      string            `json:"projectId"`
+	ProjectNumber  string            `json:"projectNumber"`
+	Name           string            `json:"name"`
+	LifecycleState string            `json:"lifecycleState"`
+	Labels         map[string]string `json:"labels,omitempty"`
+}
+
+// GetAllProjects returns every active project the credentials have access to
+func (c *Client) GetAllProjects() ([]*Project, error) {
+	var allProjects []*Project
+	u := c.resourceManagerEndpoint + "/v1/projects?filter=" + url.QueryEscape("lifecycleState:ACTIVE")
+	err := c.list(u, func(body []byte) error {
+		var page struct {
+			Projects []*Project `json:"projects"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		allProjects = append(allProjects, page.Projects...)
+		return nil
+	})
+	return allProjects, err
+}
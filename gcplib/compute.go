@@ -0,0 +1,80 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package gcplib
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Instance holds the Compute Engine representation of a VM instance.
+// Zone, MachineType and network references are full resource URLs
+type Instance struct {
+	ID                string            `json:"id"`
+	Name              string            `json:"name"`
+	Zone              string            `json:"zone"`
+	MachineType       string            `json:"machineType"`
+	Status            string            `json:"status"`
+	CreationTimestamp string            `json:"creationTimestamp"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Tags              *struct {
+		Items []string `json:"items"`
+	} `json:"tags,omitempty"`
+	NetworkInterfaces []struct {
+		Name          string `json:"name"`
+		Network       string `json:"network"`
+		Subnetwork    string `json:"subnetwork"`
+		NetworkIP     string `json:"networkIP"`
+		AccessConfigs []struct {
+			Name  string `json:"name"`
+			NatIP string `json:"natIP,omitempty"`
+		} `json:"accessConfigs,omitempty"`
+	} `json:"networkInterfaces"`
+	Disks []struct {
+		DeviceName string `json:"deviceName"`
+		Source     string `json:"source"`
+		Boot       bool   `json:"boot"`
+		DiskSizeGb string `json:"diskSizeGb"`
+	} `json:"disks"`
+	ServiceAccounts []struct {
+		Email string `json:"email"`
+	} `json:"serviceAccounts,omitempty"`
+}
+
+// GetAllInstances returns every Compute Engine instance of a project, keyed by zone.
+// A single aggregated list call covers every zone
+func (c *Client) GetAllInstances(project string) (map[string][]*Instance, error) {
+	allInstances := make(map[string][]*Instance)
+	u := fmt.Sprintf("%s/compute/v1/projects/%s/aggregated/instances?returnPartialSuccess=true", c.computeEndpoint, project)
+	err := c.list(u, func(body []byte) error {
+		var page struct {
+			Items map[string]struct {
+				Instances []*Instance `json:"instances"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		// Scopes are named zones/<zone>, zones without instances only carry a warning
+		for scope, items := range page.Items {
+			if len(items.Instances) == 0 {
+				continue
+			}
+			zone := strings.TrimPrefix(scope, "zones/")
+			allInstances[zone] = append(allInstances[zone], items.Instances...)
+		}
+		return nil
+	})
+	return allInstances, err
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package gcplib
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestServer returns a local stand-in for the Google Cloud APIs serving the given responses by path and page token
+func newTestServer(t *testing.T, responses map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Path
+		if token := r.URL.Query().Get("pageToken"); token != "" {
+			key += "?pageToken=" + token
+		}
+		body, ok := responses[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"error":{"code":404,"message":"%s","status":"NOT_FOUND"}}`, key)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+}
+
+// TestGetAllProjects checks that projects are listed across pages
+func TestGetAllProjects(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/v1/projects":              `{"projects":[{"projectId":"one","lifecycleState":"ACTIVE"}],"nextPageToken":"p2"}`,
+		"/v1/projects?pageToken=p2": `{"projects":[{"projectId":"two","lifecycleState":"ACTIVE"}]}`,
+	})
+	defer server.Close()
+	projects, err := NewClientWithHTTPClient(server.URL, server.Client()).GetAllProjects()
+	if err != nil {
+		t.Fatalf("Failed to list projects: %v", err)
+	}
+	if len(projects) != 2 || projects[0].ProjectID != "one" || projects[1].ProjectID != "two" {
+		t.Errorf("Unexpected projects %+v", projects)
+	}
+}
+
+// TestGetAllInstances checks that aggregated instances are keyed by zone, leaving out zones without instances
+func TestGetAllInstances(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/compute/v1/projects/one/aggregated/instances": `{"items":{
+			"zones/us-central1-a":{"instances":[{"id":"1","name":"web","status":"RUNNING",
+				"networkInterfaces":[{"networkIP":"10.128.0.2","accessConfigs":[{"natIP":"203.0.113.2"}]}]}]},
+			"zones/us-central1-b":{"warning":{"code":"NO_RESULTS_ON_PAGE"}}},"nextPageToken":"p2"}`,
+		"/compute/v1/projects/one/aggregated/instances?pageToken=p2": `{"items":{
+			"zones/us-central1-a":{"instances":[{"id":"2","name":"db"}]},
+			"zones/europe-west1-b":{"instances":[{"id":"3","name":"eu"}]}}}`,
+	})
+	defer server.Close()
+	instances, err := NewClientWithHTTPClient(server.URL, server.Client()).GetAllInstances("one")
+	if err != nil {
+		t.Fatalf("Failed to list instances: %v", err)
+	}
+	if len(instances) != 2 || len(instances["us-central1-a"]) != 2 || len(instances["europe-west1-b"]) != 1 {
+		t.Errorf("Unexpected instances per zone %v", instances)
+	}
+	web := instances["us-central1-a"][0]
+	if web.NetworkInterfaces[0].NetworkIP != "10.128.0.2" || web.NetworkInterfaces[0].AccessConfigs[0].NatIP != "203.0.113.2" {
+		t.Errorf("Instance addresses were not decoded: %+v", web)
+	}
+}
+
+// TestGetAllSQLInstances checks that Cloud SQL instances are keyed by region
+func TestGetAllSQLInstances(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/v1/projects/one/instances": `{"items":[{"name":"pg","region":"us-central1","databaseVersion":"POSTGRES_14",
+			"settings":{"tier":"db-custom-1-3840","dataDiskSizeGb":"10"}},{"name":"mysql","region":"europe-west1"}]}`,
+	})
+	defer server.Close()
+	instances, err := NewClientWithHTTPClient(server.URL, server.Client()).GetAllSQLInstances("one")
+	if err != nil {
+		t.Fatalf("Failed to list SQL instances: %v", err)
+	}
+	if len(instances["us-central1"]) != 1 || len(instances["europe-west1"]) != 1 || instances["us-central1"][0].Settings.Tier != "db-custom-1-3840" {
+		t.Errorf("Unexpected SQL instances %v", instances)
+	}
+}
+
+// TestIsServiceDisabled checks that disabled APIs are told apart from other permission errors
+func TestIsServiceDisabled(t *testing.T) {
+	for _, testCase := range []struct {
+		name     string
+		status   int
+		body     string
+		disabled bool
+	}{
+		{"compute", http.StatusForbidden, `{"error":{"code":403,"message":"Compute Engine API has not been used","errors":[{"reason":"accessNotConfigured"}]}}`, true},
+		{"sqladmin", http.StatusForbidden, `{"error":{"code":403,"status":"PERMISSION_DENIED","details":[{"reason":"SERVICE_DISABLED"}]}}`, true},
+		{"denied", http.StatusForbidden, `{"error":{"code":403,"status":"PERMISSION_DENIED","errors":[{"reason":"forbidden"}]}}`, false},
+		{"missing", http.StatusNotFound, `{"error":{"code":404,"status":"NOT_FOUND"}}`, false},
+	} {
+		if have := IsServiceDisabled(parseError(testCase.status, []byte(testCase.body))); have != testCase.disabled {
+			t.Errorf("%s\tWant:%t\tHave:%t", testCase.name, testCase.disabled, have)
+		}
+	}
+}
+
+// TestNewClientCredentials checks that invalid service account files are rejected
+func TestNewClientCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gcplib")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if _, err := NewClient(context.Background(), filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("Expected an error for a missing credentials file")
+	}
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := ioutil.WriteFile(invalid, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewClient(context.Background(), invalid); err == nil {
+		t.Errorf("Expected an error for an invalid credentials file")
+	}
+}
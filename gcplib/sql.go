@@ -0,0 +1,63 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package gcplib
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SQLInstance holds the Cloud SQL Admin representation of a Cloud SQL instance
+type SQLInstance struct {
+	Name            string `json:"name"`
+	ConnectionName  string `json:"connectionName"`
+	DatabaseVersion string `json:"databaseVersion"`
+	Region          string `json:"region"`
+	GceZone         string `json:"gceZone,omitempty"`
+	State           string `json:"state"`
+	InstanceType    string `json:"instanceType"`
+	Settings        struct {
+		Tier             string            `json:"tier"`
+		AvailabilityType string            `json:"availabilityType"`
+		DataDiskSizeGb   string            `json:"dataDiskSizeGb"`
+		DataDiskType     string            `json:"dataDiskType"`
+		UserLabels       map[string]string `json:"userLabels,omitempty"`
+		IPConfiguration  struct {
+			IPv4Enabled    bool   `json:"ipv4Enabled"`
+			PrivateNetwork string `json:"privateNetwork,omitempty"`
+		} `json:"ipConfiguration"`
+	} `json:"settings"`
+	IPAddresses []struct {
+		Type      string `json:"type"`
+		IPAddress string `json:"ipAddress"`
+	} `json:"ipAddresses,omitempty"`
+}
+
+// GetAllSQLInstances returns every Cloud SQL instance of a project, keyed by region
+func (c *Client) GetAllSQLInstances(project string) (map[string][]*SQLInstance, error) {
+	allInstances := make(map[string][]*SQLInstance)
+	u := fmt.Sprintf("%s/v1/projects/%s/instances", c.sqlEndpoint, project)
+	err := c.list(u, func(body []byte) error {
+		var page struct {
+			Items []*SQLInstance `json:"items"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, i := range page.Items {
+			allInstances[i.Region] = append(allInstances[i.Region], i)
+		}
+		return nil
+	})
+	return allInstances, err
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package hetznerlib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/adobe/cloudinventory/restlib"
+	"golang.org/x/oauth2"
+)
+
+// Endpoint is the Hetzner Cloud API endpoint
+const Endpoint = "https://api.hetzner.cloud"
+
+// Client is a minimal Hetzner Cloud REST client, only supporting the list operations used for inventory
+type Client struct {
+	endpoint string
+	http     *http.Client
+}
+
+// NewClient returns a Client authenticating with the given project API token
+func NewClient(token string) *Client {
+	return newClient(Endpoint, token)
+}
+
+// NewClientFromEnv returns a Client authenticating with the token set in HCLOUD_TOKEN
+func NewClientFromEnv() (*Client, error) {
+	token := os.Getenv("HCLOUD_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("Failed to get Hetzner Cloud Credentials, set HCLOUD_TOKEN")
+	}
+	return NewClient(token), nil
+}
+
+// NewClientWithHTTPClient returns a Client sending its requests to endpoint through httpClient,
+// which is expected to take care of authentication
+func NewClientWithHTTPClient(endpoint string, httpClient *http.Client) *Client {
+	return &Client{endpoint: strings.TrimSuffix(endpoint, "/"), http: httpClient}
+}
+
+func newClient(endpoint, token string) *Client {
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+	return NewClientWithHTTPClient(endpoint, oauth2.NewClient(context.Background(), ts))
+}
+
+// Error is returned for API requests which did not succeed
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Hetzner Cloud request failed with status %d: %s %s", e.StatusCode, e.Code, e.Message)
+}
+
+// list calls the list operation at path, requesting every page until the key array of each was passed to appendPage
+func (c *Client) list(path, key string, appendPage func(items json.RawMessage) error) error {
+	for page := 1; page != 0; {
+		var result map[string]json.RawMessage
+		if err := c.get(fmt.Sprintf("%s%s?page=%d&per_page=50", c.endpoint, path, page), &result); err != nil {
+			return err
+		}
+		if items, ok := result[key]; ok && string(items) != "null" {
+			if err := appendPage(items); err != nil {
+				return err
+			}
+		}
+		var meta struct {
+			Pagination struct {
+				NextPage int `json:"next_page"`
+			} `json:"pagination"`
+		}
+		if m, ok := result["meta"]; ok {
+			if err := json.Unmarshal(m, &meta); err != nil {
+				return err
+			}
+		}
+		// next_page is null on the last page
+		page = meta.Pagination.NextPage
+	}
+	return nil
+}
+
+// get decodes the JSON document at u into v, retrying rate limited requests
+func (c *Client) get(u string, v interface{}) error {
+	status, body, err := restlib.Get(c.http, u)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.Unmarshal(body, &apiErr)
+		return &Error{StatusCode: status, Code: apiErr.Error.Code, Message: apiErr.Error.Message}
+	}
+	return json.Unmarshal(body, v)
+}
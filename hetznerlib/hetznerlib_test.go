@@ -0,0 +1,58 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package hetznerlib
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestGetAllServers checks that the client authenticates, follows next_page and surfaces errors
+func TestGetAllServers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":{"code":"unauthorized","message":"unable to authenticate"}}`)
+			return
+		}
+		switch r.URL.Query().Get("page") {
+		case "1":
+			fmt.Fprint(w, `{"servers":[{"id":1,"name":"web","labels":{"env":"prod"},
+				"public_net":{"ipv4":{"ip":"203.0.113.30"}},"private_net":[{"network":4,"ip":"10.0.0.2"}],
+				"server_type":{"name":"cx22"},"datacenter":{"name":"fsn1-dc14","location":{"name":"fsn1"}}}],
+				"meta":{"pagination":{"page":1,"next_page":2,"last_page":2}}}`)
+		default:
+			fmt.Fprint(w, `{"servers":[{"id":2,"name":"private","public_net":{"ipv4":null},
+				"datacenter":{"location":{"name":"hel1"}}}],"meta":{"pagination":{"page":2,"next_page":null,"last_page":2}}}`)
+		}
+	}))
+	defer server.Close()
+	servers, err := newClient(server.URL, "test-token").GetAllServers()
+	if err != nil {
+		t.Fatalf("Failed to list servers: %v", err)
+	}
+	if len(servers) != 2 || servers[0].Labels["env"] != "prod" || servers[1].Datacenter.Location.Name != "hel1" {
+		t.Fatalf("Unexpected servers %+v", servers)
+	}
+	if servers[0].PublicIPv4() != "203.0.113.30" || servers[0].PrivateIPv4() != "10.0.0.2" || servers[1].PublicIPv4() != "" {
+		t.Errorf("Unexpected addresses %+v", servers[0].PublicNet)
+	}
+
+	_, err = newClient(server.URL, "wrong").GetAllServers()
+	apiErr, ok := err.(*Error)
+	if !ok || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Code != "unauthorized" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package hetznerlib
+
+import (
+	"encoding/json"
+)
+
+// Server holds a Hetzner Cloud server
+type Server struct {
+	ID        int               `json:"id"`
+	Name      string            `json:"name"`
+	Status    string            `json:"status"`
+	Created   string            `json:"created"`
+	Labels    map[string]string `json:"labels"`
+	PublicNet struct {
+		IPv4 *struct {
+			IP string `json:"ip"`
+		} `json:"ipv4"`
+		IPv6 *struct {
+			IP string `json:"ip"`
+		} `json:"ipv6"`
+	} `json:"public_net"`
+	PrivateNet []struct {
+		Network int    `json:"network"`
+		IP      string `json:"ip"`
+	} `json:"private_net"`
+	ServerType struct {
+		Name   string  `json:"name"`
+		Cores  int     `json:"cores"`
+		Memory float64 `json:"memory"`
+		Disk   int     `json:"disk"`
+	} `json:"server_type"`
+	Datacenter struct {
+		Name     string `json:"name"`
+		Location struct {
+			Name        string `json:"name"`
+			City        string `json:"city"`
+			Country     string `json:"country"`
+			NetworkZone string `json:"network_zone"`
+		} `json:"location"`
+	} `json:"datacenter"`
+	Image *struct {
+		Name     string `json:"name"`
+		OSFlavor string `json:"os_flavor"`
+	} `json:"image"`
+}
+
+// PublicIPv4 returns the public IPv4 address of the server, servers can be created without one
+func (s *Server) PublicIPv4() string {
+	if s.PublicNet.IPv4 == nil {
+		return ""
+	}
+	return s.PublicNet.IPv4.IP
+}
+
+// PrivateIPv4 returns the address of the server in its first private network
+func (s *Server) PrivateIPv4() string {
+	if len(s.PrivateNet) == 0 {
+		return ""
+	}
+	return s.PrivateNet[0].IP
+}
+
+// GetAllServers returns every server of the project the token belongs to
+func (c *Client) GetAllServers() ([]*Server, error) {
+	var allServers []*Server
+	err := c.list("/v1/servers", "servers", func(items json.RawMessage) error {
+		var page []*Server
+		if err := json.Unmarshal(items, &page); err != nil {
+			return err
+		}
+		allServers = append(allServers, page...)
+		return nil
+	})
+	return allServers, err
+}
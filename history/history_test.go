@@ -0,0 +1,223 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package history
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newTestStore returns a Store in a temporary directory and a function removing it
+func newTestStore(t *testing.T) (*Store, func()) {
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewStore(filepath.Join(dir, "snapshots")), func() { os.RemoveAll(dir) }
+}
+
+// TestStore checks that snapshots are listed chronologically and looked up by time
+func TestStore(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+	if snapshots, err := store.List(); err != nil || len(snapshots) != 0 {
+		t.Fatalf("A new store should be empty, got %v: %v", snapshots, err)
+	}
+	day := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	for _, s := range []struct {
+		source string
+		t      time.Time
+	}{
+		{"aws", day.Add(24 * time.Hour)},
+		{"aws", day},
+		{"azure", day.Add(time.Hour)},
+		{"aws-ec2", day.Add(48 * time.Hour)},
+	} {
+		if _, err := store.Save(s.source, s.t, []byte(`{"source":"`+s.source+`"}`)); err != nil {
+			t.Fatalf("Failed to save snapshot: %v", err)
+		}
+	}
+	if _, err := store.Save("../aws", day, []byte(`{}`)); err == nil {
+		t.Errorf("Sources outside of the store should be rejected")
+	}
+	if _, err := store.Save("aws", day, []byte(`{`)); err == nil {
+		t.Errorf("Invalid JSON should be rejected")
+	}
+	if err := ioutil.WriteFile(filepath.Join(store.dir, "notes.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots, err := store.List()
+	if err != nil {
+		t.Fatalf("Failed to list snapshots: %v", err)
+	}
+	var got []string
+	for _, s := range snapshots {
+		got = append(got, s.Time.Format(time.RFC3339)+" "+s.Source)
+	}
+	expected := []string{"2026-09-01T12:00:00Z aws", "2026-09-01T13:00:00Z azure", "2026-09-02T12:00:00Z aws", "2026-09-03T12:00:00Z aws-ec2"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Unexpected snapshots %v", got)
+	}
+
+	latest, err := store.Latest(day.Add(30 * time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to find snapshots: %v", err)
+	}
+	if len(latest) != 2 || latest[0].Source != "aws" || !latest[0].Time.Equal(day.Add(24*time.Hour)) || latest[1].Source != "azure" {
+		t.Fatalf("Unexpected latest snapshots %+v", latest)
+	}
+	doc, err := store.Load(latest[1])
+	if err != nil || !reflect.DeepEqual(doc, map[string]interface{}{"source": "azure"}) {
+		t.Errorf("Unexpected snapshot content %v: %v", doc, err)
+	}
+}
+
+// TestResources checks how resources are identified in inventories of different providers
+func TestResources(t *testing.T) {
+	var doc interface{}
+	err := json.Unmarshal([]byte(`{
+		"ec2": {"us-east-1": [{"InstanceId": "i-1", "ImageId": "ami-1", "NetworkInterfaces": [{"NetworkInterfaceId": "eni-1"}]}]},
+		"kms": {"us-east-1": [{"Aliases": ["alias/inventory"], "Metadata": {"KeyId": "k-1", "Arn": "arn:aws:kms:us-east-1:1:key/k-1"}}]},
+		"vms": {"sub": [{"VirtualMachine": {"id": "/subscriptions/sub/vm"}, "PublicIPs": [{"id": "/subscriptions/sub/ip"}]}]},
+		"droplets": [{"id": 42, "name": "web"}],
+		"clusters": {"prod": {"Nodes": [{"metadata": {"name": "node-1", "uid": "u-1"}, "spec": {"providerID": "aws:///us-east-1a/i-1"}}]}}
+	}`), &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resources := Resources(doc)
+	var ids []string
+	for id := range resources {
+		ids = append(ids, id)
+	}
+	for id, location := range map[string]string{
+		"i-1":                             "ec2/us-east-1",
+		"arn:aws:kms:us-east-1:1:key/k-1": "kms/us-east-1",
+		"/subscriptions/sub/vm":           "vms/sub",
+		"42":                              "droplets",
+		"u-1":                             "clusters/prod/Nodes",
+	} {
+		if r := resources[id]; len(r) != 1 || r[0].Location != location {
+			t.Errorf("Expected resource %s in %s, got %+v", id, location, r)
+		}
+	}
+	if len(resources) != 5 {
+		t.Errorf("Nested objects should not be resources of their own, got %v", ids)
+	}
+}
+
+// TestResourcesPrimaryKeys checks that AWS resources are identified by their own key, not by the resources they reference,
+// and that resources found at several locations are all kept
+func TestResourcesPrimaryKeys(t *testing.T) {
+	var doc interface{}
+	err := json.Unmarshal([]byte(`{
+		"addresses": {"us-east-1": {
+			"ElasticIPs": [{"AllocationId": "eipalloc-1", "InstanceId": "i-1", "PublicIp": "203.0.113.1"}],
+			"NetworkInterfaces": [{"NetworkInterfaceId": "eni-1", "Attachment": {"InstanceId": "i-1"}}]
+		}},
+		"ebs": {"us-east-1": {
+			"Volumes": [{"VolumeId": "vol-1", "SnapshotId": "snap-1"}],
+			"Snapshots": [{"SnapshotId": "snap-1", "VolumeId": "vol-1"}]
+		}},
+		"ec2": {"us-east-1": [{"InstanceId": "i-1", "ImageId": "ami-1"}]},
+		"tagged_resources": {"us-east-1": [{"ARN": "arn:aws:ec2:us-east-1:1:volume/vol-1"}]},
+		"rds": {
+			"us-east-1": {"Instances": [{"DBInstanceIdentifier": "db-1"}], "Snapshots": [{"DBSnapshotIdentifier": "snap-db-1", "DBInstanceIdentifier": "db-1"}]},
+			"eu-west-1": {"Instances": [{"DBInstanceIdentifier": "db-1"}]}
+		}
+	}`), &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resources := Resources(doc)
+	for id, locations := range map[string][]string{
+		"i-1":        {"ec2/us-east-1"},
+		"eipalloc-1": {"addresses/us-east-1/ElasticIPs"},
+		"eni-1":      {"addresses/us-east-1/NetworkInterfaces"},
+		"vol-1":      {"ebs/us-east-1/Volumes"},
+		"snap-1":     {"ebs/us-east-1/Snapshots"},
+		"snap-db-1":  {"rds/us-east-1/Snapshots"},
+		"db-1":       {"rds/eu-west-1/Instances", "rds/us-east-1/Instances"},
+	} {
+		var found []string
+		for _, r := range resources[id] {
+			found = append(found, r.Location)
+		}
+		if !reflect.DeepEqual(found, locations) {
+			t.Errorf("Expected resource %s in %v, got %v", id, locations, found)
+		}
+	}
+}
+
+// TestResourceLifecycle checks the events of a resource across snapshots of several sources
+func TestResourceLifecycle(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+	day := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	for i, s := range []struct {
+		source string
+		data   string
+	}{
+		{"aws", `{"ec2": {"us-east-1": [{"InstanceId": "i-1", "State": {"Name": "running"}, "Tags": []}]}}`},
+		{"aws", `{"ec2": {"us-east-1": [{"InstanceId": "i-1", "State": {"Name": "running"}, "Tags": []}]}}`},
+		// A filtered dump is compared to the previous dump of the same filter only
+		{"aws-rds", `{"rds": {"us-east-1": [{"DBInstanceIdentifier": "db-1"}]}}`},
+		{"aws", `{"ec2": {"us-east-1": [{"InstanceId": "i-1", "State": {"Name": "stopped"}, "Tags": [{"Key": "team", "Value": "a"}]}]}}`},
+		{"aws", `{"ec2": {"us-east-1": []}}`},
+		{"aws", `{"ec2": {"eu-west-1": [{"InstanceId": "i-1", "State": {"Name": "stopped"}}]}}`},
+	} {
+		if _, err := store.Save(s.source, day.Add(time.Duration(i)*24*time.Hour), []byte(s.data)); err != nil {
+			t.Fatalf("Failed to save snapshot: %v", err)
+		}
+	}
+
+	l, err := store.Resource("i-1")
+	if err != nil {
+		t.Fatalf("Failed to follow resource: %v", err)
+	}
+	if !l.FirstSeen.Equal(day) || !l.LastSeen.Equal(day.Add(5*24*time.Hour)) {
+		t.Errorf("Unexpected first and last seen %v %v", l.FirstSeen, l.LastSeen)
+	}
+	var kinds []string
+	for _, e := range l.Events {
+		kinds = append(kinds, e.Time.Format("2006-01-02")+" "+e.Kind+" "+e.Location)
+	}
+	expected := []string{
+		"2026-09-01 added ec2/us-east-1",
+		"2026-09-04 changed ec2/us-east-1",
+		"2026-09-05 removed ec2/us-east-1",
+		"2026-09-06 added ec2/eu-west-1",
+	}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Fatalf("Unexpected events %v", kinds)
+	}
+	changes := []Change{
+		{Attribute: "State.Name", Old: `"running"`, New: `"stopped"`},
+		{Attribute: "Tags", Old: `[]`},
+		{Attribute: "Tags[0].Key", New: `"team"`},
+		{Attribute: "Tags[0].Value", New: `"a"`},
+	}
+	if !reflect.DeepEqual(l.Events[1].Changes, changes) {
+		t.Errorf("Unexpected changes %+v", l.Events[1].Changes)
+	}
+
+	if l, err := store.Resource("i-2"); err != nil || l != nil {
+		t.Errorf("Unknown resources should have no lifecycle, got %+v: %v", l, err)
+	}
+}
@@ -0,0 +1,328 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// primaryKeys identify the resources found at known locations of a dump, location being a path.Match pattern.
+// Resources also reference others, e.g. EC2 snapshots their volume or Elastic IPs their instance, so the objects
+// of these locations are only identified by their own primary key. Nested fields are separated by dots
+var primaryKeys = []struct {
+	location string
+	key      string
+}{
+	{"ec2/*", "InstanceId"},
+	{"ebs/*/Volumes", "VolumeId"},
+	{"ebs/*/Snapshots", "SnapshotId"},
+	{"ebs/*/Images", "ImageId"},
+	{"addresses/*/ElasticIPs", "AllocationId"},
+	{"addresses/*/NetworkInterfaces", "NetworkInterfaceId"},
+	{"addresses/*/NatGateways", "NatGatewayId"},
+	{"rds/*/Instances", "DBInstanceIdentifier"},
+	{"rds/*/Clusters", "DBClusterIdentifier"},
+	{"rds/*/GlobalClusters", "GlobalClusterIdentifier"},
+	{"rds/*/Snapshots", "DBSnapshotIdentifier"},
+	{"rds/*/ClusterSnapshots", "DBClusterSnapshotIdentifier"},
+	{"rds/*/ParameterGroups", "DBParameterGroupName"},
+	{"rds/*/ClusterParameterGroups", "DBClusterParameterGroupName"},
+	{"rds/*/SubnetGroups", "DBSubnetGroupName"},
+	{"autoscaling/*/Groups", "AutoScalingGroupARN"},
+	{"autoscaling/*/LaunchConfigurations", "LaunchConfigurationARN"},
+	{"autoscaling/*/LaunchTemplates", "Template.LaunchTemplateId"},
+	{"cloudformation/*/Stacks", "Stack.StackId"},
+	{"elb/*/LoadBalancers", "LoadBalancerArn"},
+	{"elb/*/ClassicLoadBalancers", "LoadBalancerName"},
+	{"eks/*", "Cluster.Arn"},
+	{"ecs/*/Clusters", "Cluster.ClusterArn"},
+	{"ecs/*/TaskDefinitions", "TaskDefinitionArn"},
+	{"dynamodb/*", "Table.TableArn"},
+	{"elasticache/*/ReplicationGroups", "ARN"},
+	{"elasticache/*/CacheClusters", "ARN"},
+	{"redshift/*", "ClusterIdentifier"},
+	{"ecr/*", "Repository.RepositoryArn"},
+	{"sqs/*", "URL"},
+	{"sns/*", "Arn"},
+	{"kinesis/*/Streams", "StreamARN"},
+	{"kinesis/*/DeliveryStreams", "DeliveryStreamARN"},
+	{"eventbridge/*", "Bus.Arn"},
+	{"kms/*", "Metadata.Arn"},
+	{"secretsmanager/*", "ARN"},
+	{"acm/*", "CertificateArn"},
+	{"apigateway/*/RestAPIs", "API.Id"},
+	{"apigateway/*/HTTPAPIs", "API.ApiId"},
+	{"apigateway/*/RestDomainNames", "DomainName"},
+	{"apigateway/*/HTTPDomainNames", "DomainName"},
+	{"waf/*", "ACL.ARN"},
+	{"filesystems/*/EFS", "FileSystem.FileSystemArn"},
+	{"filesystems/*/FSx", "ResourceARN"},
+	{"filesystems/*/VPCs", "VpcId"},
+	{"filesystems/*/Subnets", "SubnetId"},
+	{"cloudfront/Distributions", "ARN"},
+	{"cloudfront/WebACLs", "ACL.ARN"},
+	{"iam/*", "Arn"},
+	{"route53/Zones", "Zone.Id"},
+}
+
+// idKeys are the fields identifying the resources found at other locations, the first one present in a JSON
+// object is its ID. The most specific fields come first
+var idKeys = []string{
+	// AWS
+	"InstanceId", "DBInstanceIdentifier", "DBClusterIdentifier", "DBSnapshotIdentifier", "SnapshotId", "ImageId", "VolumeId",
+	"NetworkInterfaceId", "AllocationId", "NatGatewayId", "FileSystemId", "LaunchTemplateId", "StackId",
+	"LoadBalancerArn", "AutoScalingGroupARN", "ServiceArn", "TaskDefinitionArn", "ContainerInstanceArn", "NodegroupArn",
+	"TableArn", "TopicArn", "SubscriptionArn", "StreamARN", "DeliveryStreamARN", "CertificateArn", "RepositoryArn",
+	"ReplicationGroupId", "CacheClusterId", "ClusterIdentifier", "LoadBalancerName", "Arn", "ARN",
+	// Azure, Google Cloud, DigitalOcean, Linode, Hetzner Cloud, OpenStack and the normalized hosts
+	"id", "Id", "ID",
+	// Kubernetes objects, identified through their metadata
+	"uid",
+	// vSphere
+	"InstanceUUID", "Name",
+}
+
+// wrapperKeys hold the object identifying the resource wrapping it, e.g. the metadata of Kubernetes objects
+// or the virtual machine of an Azure VM along with its interfaces
+var wrapperKeys = []string{"metadata", "Metadata", "VirtualMachine"}
+
+// Resource is an identified object of an inventory. Location is its path in the inventory, without list indexes
+type Resource struct {
+	ID         string
+	Location   string
+	Attributes interface{}
+}
+
+// Resources returns the resources of a decoded JSON inventory by ID, in key order. Objects nested in a resource
+// are part of its attributes rather than resources of their own. Several resources share an ID when the same
+// resource is found at several locations, e.g. in its own service and under tagged_resources
+func Resources(doc interface{}) map[string][]*Resource {
+	resources := make(map[string][]*Resource)
+	collectResources(doc, nil, resources)
+	return resources
+}
+
+func collectResources(v interface{}, location []string, resources map[string][]*Resource) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		loc := strings.Join(location, "/")
+		if id := resourceID(v, loc); id != "" {
+			resources[id] = append(resources[id], &Resource{ID: id, Location: loc, Attributes: v})
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			collectResources(v[k], append(location[:len(location):len(location)], k), resources)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectResources(item, location, resources)
+		}
+	}
+}
+
+// resourceID returns the ID of obj found at location, or of the object it wraps, empty if obj is not a resource.
+// Objects at a location with a primary key are identified by it only
+func resourceID(obj map[string]interface{}, location string) string {
+	for _, p := range primaryKeys {
+		if ok, _ := path.Match(p.location, location); ok {
+			var v interface{} = obj
+			for _, k := range strings.Split(p.key, ".") {
+				m, _ := v.(map[string]interface{})
+				v = m[k]
+			}
+			return scalarString(v)
+		}
+	}
+	for _, k := range idKeys {
+		if id := scalarString(obj[k]); id != "" {
+			return id
+		}
+	}
+	for _, k := range wrapperKeys {
+		if wrapped, ok := obj[k].(map[string]interface{}); ok {
+			for _, k := range idKeys {
+				if id := scalarString(wrapped[k]); id != "" {
+					return id
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// scalarString returns string and numeric JSON values as strings, empty for anything else
+func scalarString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// Event kinds of a resource lifecycle
+const (
+	EventAdded   = "added"
+	EventChanged = "changed"
+	EventRemoved = "removed"
+)
+
+// Change is an attribute of a resource changed between two snapshots, its values as JSON. Old or New are empty
+// for attributes which were added or removed
+type Change struct {
+	Attribute string
+	Old       string
+	New       string
+}
+
+// Event is a change of a resource found in a snapshot compared to the previous snapshot of the same source
+type Event struct {
+	Time     time.Time
+	Source   string
+	Location string
+	Kind     string
+	Changes  []Change
+}
+
+// Lifecycle is the history of a resource across the snapshots of a store
+type Lifecycle struct {
+	ID        string
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Events    []*Event
+}
+
+// Resource returns the lifecycle of the resource with the given ID, nil if no snapshot holds it.
+// Snapshots are compared to the previous one of the same source only, a dump filtered on a service
+// does not remove the resources of the other services. Resources sharing the ID are followed by location
+func (s *Store) Resource(id string) (*Lifecycle, error) {
+	snapshots, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	var l *Lifecycle
+	previous := make(map[string]map[string]*Resource)
+	for _, snap := range snapshots {
+		doc, err := s.Load(snap)
+		if err != nil {
+			return nil, err
+		}
+		current := resourcesByLocation(Resources(doc)[id])
+		before := previous[snap.Source]
+		previous[snap.Source] = current
+		for _, key := range locationKeys(before, current) {
+			r, b := current[key], before[key]
+			switch {
+			case r == nil:
+				l.Events = append(l.Events, &Event{Time: snap.Time, Source: snap.Source, Location: b.Location, Kind: EventRemoved})
+				continue
+			case l == nil:
+				l = &Lifecycle{ID: id, FirstSeen: snap.Time}
+			}
+			l.LastSeen = snap.Time
+			if b == nil {
+				l.Events = append(l.Events, &Event{Time: snap.Time, Source: snap.Source, Location: r.Location, Kind: EventAdded})
+			} else if changes := Diff(b.Attributes, r.Attributes); len(changes) > 0 {
+				l.Events = append(l.Events, &Event{Time: snap.Time, Source: snap.Source, Location: r.Location, Kind: EventChanged, Changes: changes})
+			}
+		}
+	}
+	return l, nil
+}
+
+// resourcesByLocation keys resources by their location, followed by their rank among the resources of that location
+func resourcesByLocation(resources []*Resource) map[string]*Resource {
+	byLocation := make(map[string]*Resource)
+	ranks := make(map[string]int)
+	for _, r := range resources {
+		byLocation[fmt.Sprintf("%s#%d", r.Location, ranks[r.Location])] = r
+		ranks[r.Location]++
+	}
+	return byLocation
+}
+
+// locationKeys returns the sorted keys of both before and after
+func locationKeys(before, after map[string]*Resource) []string {
+	var keys []string
+	for k := range before {
+		keys = append(keys, k)
+	}
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Diff returns the attributes changed between two versions of a resource, sorted by attribute.
+// Attributes are flattened to their path, e.g. State.Name or Tags[0].Value
+func Diff(old, new interface{}) []Change {
+	before, after := make(map[string]string), make(map[string]string)
+	flatten(old, "", before)
+	flatten(new, "", after)
+	var changes []Change
+	for attribute, value := range before {
+		if value != after[attribute] {
+			changes = append(changes, Change{Attribute: attribute, Old: value, New: after[attribute]})
+		}
+	}
+	for attribute, value := range after {
+		if _, ok := before[attribute]; !ok {
+			changes = append(changes, Change{Attribute: attribute, New: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Attribute < changes[j].Attribute })
+	return changes
+}
+
+// flatten sets the JSON value of every scalar, empty object or empty list of v in values, keyed by its path
+func flatten(v interface{}, path string, values map[string]string) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) > 0 {
+			for k, item := range v {
+				if path == "" {
+					flatten(item, k, values)
+				} else {
+					flatten(item, path+"."+k, values)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			for i, item := range v {
+				flatten(item, fmt.Sprintf("%s[%d]", path, i), values)
+			}
+			return
+		}
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		data = []byte(fmt.Sprint(v))
+	}
+	values[path] = string(data)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+// Package history keeps inventory dumps as timestamped snapshots in a directory, and follows resources across them
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// timeFormat names snapshot files so that they sort chronologically
+const timeFormat = "20060102T150405Z"
+
+// validSource restricts sources to names safe to use in file names, e.g. aws or aws-ec2
+var validSource = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Snapshot is an inventory dump saved in a Store. Source tells apart dumps of different scopes, e.g. aws and aws-ec2
+type Snapshot struct {
+	Time   time.Time
+	Source string
+	Path   string
+}
+
+// Store saves snapshots as <time>_<source>.json files of a directory
+type Store struct {
+	dir string
+}
+
+// NewStore returns the Store of dir, which is created on the first Save
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Save stores data, a JSON inventory of source, as the snapshot taken at t. A snapshot of the same source and second is replaced
+func (s *Store) Save(source string, t time.Time, data []byte) (*Snapshot, error) {
+	if !validSource.MatchString(source) {
+		return nil, fmt.Errorf("Invalid snapshot source %q", source)
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("Snapshot of %s is not valid JSON", source)
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, err
+	}
+	t = t.UTC().Truncate(time.Second)
+	snap := &Snapshot{Time: t, Source: source, Path: filepath.Join(s.dir, t.Format(timeFormat)+"_"+source+".json")}
+	// Writing to a temporary file first keeps readers from seeing partial snapshots
+	tmp := snap.Path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, snap.Path); err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+	return snap, nil
+}
+
+// List returns every snapshot of the store, oldest first. Other files of the directory are ignored
+func (s *Store) List() ([]*Snapshot, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var snapshots []*Snapshot
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimSuffix(name, ".json"), "_", 2)
+		if len(parts) != 2 || !validSource.MatchString(parts[1]) {
+			continue
+		}
+		t, err := time.Parse(timeFormat, parts[0])
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, &Snapshot{Time: t, Source: parts[1], Path: filepath.Join(s.dir, name)})
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		if !snapshots[i].Time.Equal(snapshots[j].Time) {
+			return snapshots[i].Time.Before(snapshots[j].Time)
+		}
+		return snapshots[i].Source < snapshots[j].Source
+	})
+	return snapshots, nil
+}
+
+// Latest returns the most recent snapshot of every source taken at or before t, sorted by source
+func (s *Store) Latest(t time.Time) ([]*Snapshot, error) {
+	snapshots, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[string]*Snapshot)
+	for _, snap := range snapshots {
+		if snap.Time.After(t) {
+			break
+		}
+		latest[snap.Source] = snap
+	}
+	var result []*Snapshot
+	for _, snap := range latest {
+		result = append(result, snap)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Source < result[j].Source })
+	return result, nil
+}
+
+// Load returns the inventory of a snapshot as decoded JSON
+func (s *Store) Load(snap *Snapshot) (interface{}, error) {
+	data, err := ioutil.ReadFile(snap.Path)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("Invalid snapshot %s: %v", snap.Path, err)
+	}
+	return doc, nil
+}
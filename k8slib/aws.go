@@ -0,0 +1,80 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package k8slib
+
+import (
+	"strings"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NodeInstances holds the cross reference of the nodes of a cluster to EC2 instances.
+// Instances maps node names to the instances found in the AWS inventory, Unmatched lists the nodes
+// backed by an EC2 instance missing from it, for example because it belongs to another account
+type NodeInstances struct {
+	Instances map[string]*awslib.ResourceRef
+	Unmatched []string
+}
+
+// ParseAWSProviderID returns the EC2 instance ID of a node providerID such as aws:///us-east-1a/i-0123456789abcdef0.
+// Returns false for nodes not backed by an EC2 instance, including Fargate nodes
+func ParseAWSProviderID(providerID string) (string, bool) {
+	if !strings.HasPrefix(providerID, "aws://") {
+		return "", false
+	}
+	id := providerID[strings.LastIndex(providerID, "/")+1:]
+	if !strings.HasPrefix(id, "i-") {
+		return "", false
+	}
+	return id, true
+}
+
+// LinkNodeInstances cross references the nodes of a cluster with an EC2 inventory keyed by region.
+// Returns nil if no node is backed by an EC2 instance
+func LinkNodeInstances(nodes []corev1.Node, instances map[string][]*ec2.Instance) *NodeInstances {
+	refs := make(map[string]*awslib.ResourceRef)
+	for region, i := range instances {
+		for _, instance := range i {
+			id := aws.StringValue(instance.InstanceId)
+			refs[id] = &awslib.ResourceRef{Type: "ec2:instance", ID: id, Region: region, VPC: aws.StringValue(instance.VpcId)}
+		}
+	}
+	var links *NodeInstances
+	for _, n := range nodes {
+		id, ok := ParseAWSProviderID(n.Spec.ProviderID)
+		if !ok {
+			continue
+		}
+		if links == nil {
+			links = &NodeInstances{Instances: make(map[string]*awslib.ResourceRef)}
+		}
+		ref, ok := refs[id]
+		if !ok {
+			links.Unmatched = append(links.Unmatched, n.Name)
+			continue
+		}
+		links.Instances[n.Name] = ref
+	}
+	return links
+}
+
+// InstanceType returns the instance type label of a node, set by every major cloud provider
+func InstanceType(node corev1.Node) string {
+	if t, ok := node.Labels[corev1.LabelInstanceTypeStable]; ok {
+		return t
+	}
+	return node.Labels[corev1.LabelInstanceType]
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package k8slib
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/pager"
+)
+
+// ClusterInventory holds the inventory of a single Kubernetes cluster.
+// Managed fields are stripped from every object to keep dumps readable
+type ClusterInventory struct {
+	Nodes         []corev1.Node
+	Namespaces    []corev1.Namespace
+	Deployments   []appsv1.Deployment
+	StatefulSets  []appsv1.StatefulSet
+	DaemonSets    []appsv1.DaemonSet
+	CronJobs      []batchv1.CronJob
+	LoadBalancers []corev1.Service
+	Ingresses     []networkingv1.Ingress
+}
+
+// NewClient returns a client for the given context of kubeconfig.
+// The standard loading rules (KUBECONFIG, ~/.kube/config) apply when kubeconfig is empty, the current context when context is empty
+func NewClient(kubeconfig, context string) (kubernetes.Interface, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: context}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load kubeconfig context %q: %v", context, err)
+	}
+	return kubernetes.NewForConfig(config)
+}
+
+// GetContexts returns the names of every context of kubeconfig, using the standard loading rules when kubeconfig is empty
+func GetContexts(kubeconfig string) ([]string, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+	config, err := rules.Load()
+	if err != nil {
+		return nil, err
+	}
+	var contexts []string
+	for name := range config.Contexts {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+	return contexts, nil
+}
+
+// GetCurrentContext returns the name of the current context of kubeconfig, using the standard loading rules when kubeconfig is empty.
+// Returns an empty name when there is no kubeconfig, for example when running inside a cluster
+func GetCurrentContext(kubeconfig string) (string, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+	config, err := rules.Load()
+	if err != nil {
+		return "", err
+	}
+	return config.CurrentContext, nil
+}
+
+// GetClusterInventory returns the nodes, namespaces, workloads, load balancer services and ingresses of a cluster
+func GetClusterInventory(client kubernetes.Interface) (*ClusterInventory, error) {
+	var inv ClusterInventory
+	err := listAll(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return client.CoreV1().Nodes().List(ctx, opts)
+	}, func(obj runtime.Object) {
+		inv.Nodes = append(inv.Nodes, *obj.(*corev1.Node))
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = listAll(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return client.CoreV1().Namespaces().List(ctx, opts)
+	}, func(obj runtime.Object) {
+		inv.Namespaces = append(inv.Namespaces, *obj.(*corev1.Namespace))
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = listAll(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return client.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, opts)
+	}, func(obj runtime.Object) {
+		inv.Deployments = append(inv.Deployments, *obj.(*appsv1.Deployment))
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = listAll(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return client.AppsV1().StatefulSets(metav1.NamespaceAll).List(ctx, opts)
+	}, func(obj runtime.Object) {
+		inv.StatefulSets = append(inv.StatefulSets, *obj.(*appsv1.StatefulSet))
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = listAll(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return client.AppsV1().DaemonSets(metav1.NamespaceAll).List(ctx, opts)
+	}, func(obj runtime.Object) {
+		inv.DaemonSets = append(inv.DaemonSets, *obj.(*appsv1.DaemonSet))
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = listAll(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return client.BatchV1().CronJobs(metav1.NamespaceAll).List(ctx, opts)
+	}, func(obj runtime.Object) {
+		inv.CronJobs = append(inv.CronJobs, *obj.(*batchv1.CronJob))
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = listAll(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return client.CoreV1().Services(metav1.NamespaceAll).List(ctx, opts)
+	}, func(obj runtime.Object) {
+		if s := obj.(*corev1.Service); s.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			inv.LoadBalancers = append(inv.LoadBalancers, *s)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = listAll(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return client.NetworkingV1().Ingresses(metav1.NamespaceAll).List(ctx, opts)
+	}, func(obj runtime.Object) {
+		inv.Ingresses = append(inv.Ingresses, *obj.(*networkingv1.Ingress))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// listAll pages through list, passing every item to appendItem once its managed fields are stripped
+func listAll(list func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error), appendItem func(obj runtime.Object)) error {
+	p := pager.New(pager.ListPageFunc(list))
+	return p.EachListItem(context.Background(), metav1.ListOptions{}, func(obj runtime.Object) error {
+		if accessor, ok := obj.(metav1.Object); ok {
+			accessor.SetManagedFields(nil)
+		}
+		appendItem(obj)
+		return nil
+	})
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package k8slib
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testNode(name, providerID string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelInstanceTypeStable: "m5.large"}},
+		Spec:       corev1.NodeSpec{ProviderID: providerID},
+	}
+}
+
+// TestGetClusterInventory checks that every kind is listed and only LoadBalancer services are kept
+func TestGetClusterInventory(t *testing.T) {
+	meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "default", ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}}}
+	}
+	client := fake.NewSimpleClientset(
+		testNode("node-1", "aws:///us-east-1a/i-0123456789abcdef0"),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&appsv1.Deployment{ObjectMeta: meta("web")},
+		&appsv1.StatefulSet{ObjectMeta: meta("db")},
+		&appsv1.DaemonSet{ObjectMeta: meta("agent")},
+		&batchv1.CronJob{ObjectMeta: meta("backup")},
+		&corev1.Service{ObjectMeta: meta("web"), Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer}},
+		&corev1.Service{ObjectMeta: meta("internal"), Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP}},
+		&networkingv1.Ingress{ObjectMeta: meta("web")},
+	)
+	inv, err := GetClusterInventory(client)
+	if err != nil {
+		t.Fatalf("Failed to get cluster inventory: %v", err)
+	}
+	if len(inv.Nodes) != 1 || len(inv.Namespaces) != 1 || len(inv.Deployments) != 1 || len(inv.StatefulSets) != 1 ||
+		len(inv.DaemonSets) != 1 || len(inv.CronJobs) != 1 || len(inv.Ingresses) != 1 {
+		t.Errorf("Unexpected inventory %+v", inv)
+	}
+	if len(inv.LoadBalancers) != 1 || inv.LoadBalancers[0].Name != "web" {
+		t.Errorf("Expected only the LoadBalancer service, got %+v", inv.LoadBalancers)
+	}
+	if inv.Deployments[0].ManagedFields != nil {
+		t.Errorf("Expected managed fields to be stripped")
+	}
+	if InstanceType(inv.Nodes[0]) != "m5.large" {
+		t.Errorf("Unexpected instance type %q", InstanceType(inv.Nodes[0]))
+	}
+}
+
+// TestParseAWSProviderID checks that only EC2 backed provider IDs are parsed
+func TestParseAWSProviderID(t *testing.T) {
+	cases := map[string]string{
+		"aws:///us-east-1a/i-0123456789abcdef0": "i-0123456789abcdef0",
+		"aws:///us-east-1a/fargate-ip-10-0-0-1": "",
+		"gce://project/us-central1-a/node-1":    "",
+		"":                                      "",
+	}
+	for providerID, expected := range cases {
+		id, ok := ParseAWSProviderID(providerID)
+		if id != expected || ok != (expected != "") {
+			t.Errorf("Unexpected ID %q for %q", id, providerID)
+		}
+	}
+}
+
+// TestLinkNodeInstances checks that nodes are linked to the region of their instance and unknown instances reported
+func TestLinkNodeInstances(t *testing.T) {
+	nodes := []corev1.Node{
+		*testNode("node-1", "aws:///us-east-1a/i-1"),
+		*testNode("node-2", "aws:///us-west-2a/i-2"),
+		*testNode("node-3", "kind://docker/kind/node-3"),
+	}
+	instances := map[string][]*ec2.Instance{"us-east-1": {{InstanceId: aws.String("i-1")}}}
+	links := LinkNodeInstances(nodes, instances)
+	if links == nil {
+		t.Fatalf("Expected nodes to be linked")
+	}
+	if ref := links.Instances["node-1"]; ref == nil || ref.ID != "i-1" || ref.Region != "us-east-1" {
+		t.Errorf("Unexpected link %+v", ref)
+	}
+	if len(links.Instances) != 1 || len(links.Unmatched) != 1 || links.Unmatched[0] != "node-2" {
+		t.Errorf("Unexpected links %+v", links)
+	}
+	if LinkNodeInstances(nodes[2:], instances) != nil {
+		t.Errorf("Expected no links without EC2 backed nodes")
+	}
+}
+
+// TestGetContexts checks that contexts are read from an explicit kubeconfig
+func TestGetContexts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config")
+	config := `apiVersion: v1
+kind: Config
+clusters:
+- name: one
+  cluster: {server: "https://one.example.com"}
+users:
+- name: admin
+  user: {token: secret}
+contexts:
+- name: prod
+  context: {cluster: one, user: admin}
+- name: dev
+  context: {cluster: one, user: admin}
+current-context: prod
+`
+	if err := ioutil.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	contexts, err := GetContexts(path)
+	if err != nil || len(contexts) != 2 || contexts[0] != "dev" {
+		t.Errorf("Unexpected contexts %v: %v", contexts, err)
+	}
+	if current, err := GetCurrentContext(path); err != nil || current != "prod" {
+		t.Errorf("Unexpected current context %q: %v", current, err)
+	}
+	if _, err := NewClient(path, "dev"); err != nil {
+		t.Errorf("Failed to create client: %v", err)
+	}
+	if _, err := NewClient(path, "missing"); err == nil {
+		t.Errorf("Expected an unknown context to fail")
+	}
+}
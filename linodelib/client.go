@@ -0,0 +1,112 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package linodelib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/adobe/cloudinventory/restlib"
+	"golang.org/x/oauth2"
+)
+
+// Endpoint is the Linode API endpoint
+const Endpoint = "https://api.linode.com"
+
+// Client is a minimal Linode REST client, only supporting the list operations used for inventory
+type Client struct {
+	endpoint string
+	http     *http.Client
+}
+
+// NewClient returns a Client authenticating with the given personal access token
+func NewClient(token string) *Client {
+	return newClient(Endpoint, token)
+}
+
+// NewClientFromEnv returns a Client authenticating with the token set in LINODE_TOKEN
+func NewClientFromEnv() (*Client, error) {
+	token := os.Getenv("LINODE_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("Failed to get Linode Credentials, set LINODE_TOKEN")
+	}
+	return NewClient(token), nil
+}
+
+// NewClientWithHTTPClient returns a Client sending its requests to endpoint through httpClient,
+// which is expected to take care of authentication
+func NewClientWithHTTPClient(endpoint string, httpClient *http.Client) *Client {
+	return &Client{endpoint: strings.TrimSuffix(endpoint, "/"), http: httpClient}
+}
+
+func newClient(endpoint, token string) *Client {
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+	return NewClientWithHTTPClient(endpoint, oauth2.NewClient(context.Background(), ts))
+}
+
+// Error is returned for API requests which did not succeed
+type Error struct {
+	StatusCode int
+	Reasons    []string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Linode request failed with status %d: %s", e.StatusCode, strings.Join(e.Reasons, ", "))
+}
+
+// list calls the list operation at path, requesting every page until the data of each was passed to appendPage
+func (c *Client) list(path string, appendPage func(data json.RawMessage) error) error {
+	for page, pages := 1, 1; page <= pages; page++ {
+		var result struct {
+			Data  json.RawMessage `json:"data"`
+			Page  int             `json:"page"`
+			Pages int             `json:"pages"`
+		}
+		if err := c.get(fmt.Sprintf("%s%s?page=%d&page_size=500", c.endpoint, path, page), &result); err != nil {
+			return err
+		}
+		if len(result.Data) > 0 {
+			if err := appendPage(result.Data); err != nil {
+				return err
+			}
+		}
+		pages = result.Pages
+	}
+	return nil
+}
+
+// get decodes the JSON document at u into v, retrying rate limited requests
+func (c *Client) get(u string, v interface{}) error {
+	status, body, err := restlib.Get(c.http, u)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		var apiErr struct {
+			Errors []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+		}
+		json.Unmarshal(body, &apiErr)
+		e := &Error{StatusCode: status}
+		for _, r := range apiErr.Errors {
+			e.Reasons = append(e.Reasons, r.Reason)
+		}
+		return e
+	}
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package linodelib
+
+import (
+	"encoding/json"
+	"net"
+)
+
+// Instance holds a Linode compute instance
+type Instance struct {
+	ID      int      `json:"id"`
+	Label   string   `json:"label"`
+	Region  string   `json:"region"`
+	Type    string   `json:"type"`
+	Status  string   `json:"status"`
+	Image   string   `json:"image"`
+	Group   string   `json:"group"`
+	Created string   `json:"created"`
+	IPv4    []string `json:"ipv4"`
+	IPv6    string   `json:"ipv6"`
+	Tags    []string `json:"tags"`
+	Specs   struct {
+		Disk   int `json:"disk"`
+		Memory int `json:"memory"`
+		VCPUs  int `json:"vcpus"`
+	} `json:"specs"`
+}
+
+// PublicIPv4 returns the first public IPv4 address of the instance
+func (i *Instance) PublicIPv4() string {
+	for _, ip := range i.IPv4 {
+		if parsed := net.ParseIP(ip); parsed != nil && !isPrivate(parsed) {
+			return ip
+		}
+	}
+	return ""
+}
+
+// PrivateIPv4 returns the first private IPv4 address of the instance
+func (i *Instance) PrivateIPv4() string {
+	for _, ip := range i.IPv4 {
+		if parsed := net.ParseIP(ip); parsed != nil && isPrivate(parsed) {
+			return ip
+		}
+	}
+	return ""
+}
+
+// isPrivate reports whether ip is in an RFC 1918 range, Linode allocates private addresses from 192.168.128.0/17
+func isPrivate(ip net.IP) bool {
+	for _, cidr := range []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"} {
+		_, block, _ := net.ParseCIDR(cidr)
+		if block.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAllInstances returns every compute instance of the account
+func (c *Client) GetAllInstances() ([]*Instance, error) {
+	var allInstances []*Instance
+	err := c.list("/v4/linode/instances", func(data json.RawMessage) error {
+		var page []*Instance
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		allInstances = append(allInstances, page...)
+		return nil
+	})
+	return allInstances, err
+}
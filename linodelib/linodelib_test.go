@@ -0,0 +1,60 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package linodelib
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestGetAllInstances checks that the client authenticates, requests every page and surfaces errors
+func TestGetAllInstances(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"errors":[{"reason":"Invalid Token"}]}`)
+			return
+		}
+		if r.URL.Path != "/v4/linode/instances" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[{"reason":"Not found"}]}`)
+			return
+		}
+		switch r.URL.Query().Get("page") {
+		case "1":
+			fmt.Fprint(w, `{"data":[{"id":1,"label":"web","region":"us-east","type":"g6-standard-1",
+				"ipv4":["192.168.139.10","203.0.113.10"],"tags":["prod"]}],"page":1,"pages":2,"results":2}`)
+		default:
+			fmt.Fprint(w, `{"data":[{"id":2,"label":"db","region":"eu-west","ipv4":["198.51.100.20"]}],"page":2,"pages":2,"results":2}`)
+		}
+	}))
+	defer server.Close()
+	instances, err := newClient(server.URL, "test-token").GetAllInstances()
+	if err != nil {
+		t.Fatalf("Failed to list instances: %v", err)
+	}
+	if len(instances) != 2 || instances[1].Region != "eu-west" {
+		t.Fatalf("Unexpected instances %+v", instances)
+	}
+	if instances[0].PublicIPv4() != "203.0.113.10" || instances[0].PrivateIPv4() != "192.168.139.10" || instances[1].PrivateIPv4() != "" {
+		t.Errorf("Unexpected addresses %v", instances[0].IPv4)
+	}
+
+	_, err = newClient(server.URL, "wrong").GetAllInstances()
+	apiErr, ok := err.(*Error)
+	if !ok || apiErr.StatusCode != http.StatusUnauthorized || len(apiErr.Reasons) != 1 {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package openstacklib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/adobe/cloudinventory/restlib"
+)
+
+// Client is a minimal OpenStack REST client authenticated against Keystone v3, only supporting the list operations used for inventory.
+// Tokens are not renewed, a client is meant to be used for a single inventory run
+type Client struct {
+	region  string
+	iface   string
+	token   string
+	catalog []catalogService
+	http    *http.Client
+}
+
+// DefaultRegion is the region of a client neither restricted to a region nor finding one in the service catalog
+const DefaultRegion = "default"
+
+// catalogService holds a service of the Keystone service catalog along with its endpoints
+type catalogService struct {
+	Type      string            `json:"type"`
+	Endpoints []catalogEndpoint `json:"endpoints"`
+}
+
+// catalogEndpoint holds an endpoint of a service of the Keystone service catalog
+type catalogEndpoint struct {
+	Interface string `json:"interface"`
+	Region    string `json:"region"`
+	RegionID  string `json:"region_id"`
+	URL       string `json:"url"`
+}
+
+// NewClient returns a Client authenticated with the credentials of cloud
+func NewClient(cloud *Cloud) (*Client, error) {
+	return NewClientWithHTTPClient(cloud, http.DefaultClient)
+}
+
+// NewClientWithHTTPClient returns a Client authenticated with the credentials of cloud, sending its requests through httpClient
+func NewClientWithHTTPClient(cloud *Cloud, httpClient *http.Client) (*Client, error) {
+	c := &Client{region: cloud.RegionName, iface: cloud.Interface, http: httpClient}
+	if c.iface == "" {
+		c.iface = "public"
+	}
+	body, err := json.Marshal(authRequest(cloud.Auth))
+	if err != nil {
+		return nil, err
+	}
+	authURL := strings.TrimSuffix(cloud.Auth.AuthURL, "/")
+	if !strings.HasSuffix(authURL, "/v3") {
+		authURL += "/v3"
+	}
+	resp, err := c.http.Post(authURL+"/auth/tokens", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return nil, parseError(resp.StatusCode, data)
+	}
+	var token struct {
+		Token struct {
+			Catalog []catalogService `json:"catalog"`
+		} `json:"token"`
+	}
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+	c.token = resp.Header.Get("X-Subject-Token")
+	c.catalog = token.Token.Catalog
+	return c, nil
+}
+
+// authRequest returns the Keystone v3 token request for the given credentials, scoped to the project if one is given.
+// Domains default to the Default domain
+func authRequest(auth CloudAuth) map[string]interface{} {
+	if auth.ApplicationCredentialID != "" {
+		return map[string]interface{}{"auth": map[string]interface{}{"identity": map[string]interface{}{
+			"methods": []string{"application_credential"},
+			"application_credential": map[string]string{
+				"id":     auth.ApplicationCredentialID,
+				"secret": auth.ApplicationCredentialSecret,
+			},
+		}}}
+	}
+	user := map[string]interface{}{"password": auth.Password}
+	if auth.UserID != "" {
+		user["id"] = auth.UserID
+	} else {
+		user["name"] = auth.Username
+		user["domain"] = domain(auth.UserDomainID, auth.UserDomainName, auth)
+	}
+	request := map[string]interface{}{"identity": map[string]interface{}{
+		"methods":  []string{"password"},
+		"password": map[string]interface{}{"user": user},
+	}}
+	if auth.ProjectID != "" {
+		request["scope"] = map[string]interface{}{"project": map[string]interface{}{"id": auth.ProjectID}}
+	} else if auth.ProjectName != "" {
+		request["scope"] = map[string]interface{}{"project": map[string]interface{}{
+			"name":   auth.ProjectName,
+			"domain": domain(auth.ProjectDomainID, auth.ProjectDomainName, auth),
+		}}
+	}
+	return map[string]interface{}{"auth": request}
+}
+
+// domain returns the Keystone domain reference for the given ID or name, falling back to the cloud wide domain then Default
+func domain(id, name string, auth CloudAuth) map[string]string {
+	switch {
+	case id != "":
+		return map[string]string{"id": id}
+	case name != "":
+		return map[string]string{"name": name}
+	case auth.DomainID != "":
+		return map[string]string{"id": auth.DomainID}
+	case auth.DomainName != "":
+		return map[string]string{"name": auth.DomainName}
+	default:
+		return map[string]string{"name": "Default"}
+	}
+}
+
+// Region returns the region the client is restricted to. Clients using any region of the catalog return
+// the region of its compute endpoint, DefaultRegion if it has none
+func (c *Client) Region() string {
+	if c.region != "" {
+		return c.region
+	}
+	if e, err := c.catalogEndpoint("compute"); err == nil {
+		if e.RegionID != "" {
+			return e.RegionID
+		}
+		if e.Region != "" {
+			return e.Region
+		}
+	}
+	return DefaultRegion
+}
+
+// endpoint returns the URL of the first service of the catalog matching one of types, for the interface and region of the client
+func (c *Client) endpoint(types ...string) (string, error) {
+	e, err := c.catalogEndpoint(types...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(e.URL, "/"), nil
+}
+
+// catalogEndpoint returns the endpoint of the first service of the catalog matching one of types, for the interface
+// and region of the client
+func (c *Client) catalogEndpoint(types ...string) (*catalogEndpoint, error) {
+	for _, t := range types {
+		for _, s := range c.catalog {
+			if s.Type != t {
+				continue
+			}
+			for i, e := range s.Endpoints {
+				if e.Interface != c.iface {
+					continue
+				}
+				if c.region != "" && e.Region != c.region && e.RegionID != c.region {
+					continue
+				}
+				return &s.Endpoints[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("No %s %s endpoint found in the service catalog", c.iface, strings.Join(types, "/"))
+}
+
+// Error is returned for OpenStack requests which did not succeed
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("OpenStack request failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+// parseError returns the Error of a failed request.
+// Every service wraps its error in a differently named object (error, itemNotFound, NeutronError...) holding a message
+func parseError(statusCode int, body []byte) error {
+	var wrapped map[string]json.RawMessage
+	json.Unmarshal(body, &wrapped)
+	for _, w := range wrapped {
+		var e struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(w, &e) == nil && e.Message != "" {
+			return &Error{StatusCode: statusCode, Message: e.Message}
+		}
+	}
+	return &Error{StatusCode: statusCode, Message: http.StatusText(statusCode)}
+}
+
+// list calls the list operation at path of the first service of the catalog matching one of types,
+// following the next links until the key array of every page was passed to appendPage
+func (c *Client) list(types []string, path, key string, appendPage func(items json.RawMessage) error) error {
+	endpoint, err := c.endpoint(types...)
+	if err != nil {
+		return err
+	}
+	next := endpoint + path
+	for next != "" {
+		var page map[string]json.RawMessage
+		if err := c.get(next, &page); err != nil {
+			return err
+		}
+		if items, ok := page[key]; ok && string(items) != "null" {
+			if err := appendPage(items); err != nil {
+				return err
+			}
+		}
+		next = ""
+		if l, ok := page[key+"_links"]; ok {
+			var links []struct {
+				Href string `json:"href"`
+				Rel  string `json:"rel"`
+			}
+			if err := json.Unmarshal(l, &links); err != nil {
+				return err
+			}
+			for _, link := range links {
+				if link.Rel == "next" {
+					next = link.Href
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// get decodes the JSON document at u into v, retrying rate limited requests
+func (c *Client) get(u string, v interface{}) error {
+	status, body, err := restlib.Do(c.http, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("X-Auth-Token", c.token)
+		req.Header.Set("Accept", "application/json")
+		// Nova only returns the flavor details of servers from microversion 2.47, other services ignore it
+		req.Header.Set("OpenStack-API-Version", "compute 2.47")
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return parseError(status, body)
+	}
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package openstacklib
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Cloud holds the settings of a cloud of clouds.yaml, only password and application credential auth are supported
+type Cloud struct {
+	Auth       CloudAuth `json:"auth"`
+	RegionName string    `json:"region_name"`
+	// Interface is the endpoint interface of the service catalog to use, public if empty
+	Interface string `json:"interface"`
+}
+
+// CloudAuth holds the Keystone credentials of a cloud
+type CloudAuth struct {
+	AuthURL                     string `json:"auth_url"`
+	Username                    string `json:"username"`
+	UserID                      string `json:"user_id"`
+	Password                    string `json:"password"`
+	ProjectName                 string `json:"project_name"`
+	ProjectID                   string `json:"project_id"`
+	DomainName                  string `json:"domain_name"`
+	DomainID                    string `json:"domain_id"`
+	UserDomainName              string `json:"user_domain_name"`
+	UserDomainID                string `json:"user_domain_id"`
+	ProjectDomainName           string `json:"project_domain_name"`
+	ProjectDomainID             string `json:"project_domain_id"`
+	ApplicationCredentialID     string `json:"application_credential_id"`
+	ApplicationCredentialSecret string `json:"application_credential_secret"`
+}
+
+// LoadCloud returns the cloud with the given name from the clouds.yaml at path.
+// The standard locations (OS_CLIENT_CONFIG_FILE, ./clouds.yaml, ~/.config/openstack/clouds.yaml, /etc/openstack/clouds.yaml)
+// are searched when path is empty, OS_CLOUD is used when name is empty, or the only cloud of the file
+func LoadCloud(path, name string) (*Cloud, error) {
+	if path == "" {
+		var err error
+		if path, err = findCloudsFile(); err != nil {
+			return nil, err
+		}
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var clouds struct {
+		Clouds map[string]*Cloud `json:"clouds"`
+	}
+	if err := yaml.Unmarshal(data, &clouds); err != nil {
+		return nil, fmt.Errorf("Failed to parse %s: %v", path, err)
+	}
+	if name == "" {
+		name = os.Getenv("OS_CLOUD")
+	}
+	if name == "" && len(clouds.Clouds) == 1 {
+		for n := range clouds.Clouds {
+			name = n
+		}
+	}
+	cloud, ok := clouds.Clouds[name]
+	if !ok {
+		var names []string
+		for n := range clouds.Clouds {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("Cloud %q not found in %s, select one of %v", name, path, names)
+	}
+	return cloud, nil
+}
+
+// findCloudsFile returns the first clouds.yaml found in the standard locations
+func findCloudsFile() (string, error) {
+	candidates := []string{os.Getenv("OS_CLIENT_CONFIG_FILE"), "clouds.yaml"}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, ".config", "openstack", "clouds.yaml"))
+	}
+	candidates = append(candidates, "/etc/openstack/clouds.yaml")
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+		if _, err := os.Stat(c); err == nil {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("No clouds.yaml found")
+}
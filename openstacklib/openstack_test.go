@@ -0,0 +1,184 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package openstacklib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestCloud returns a local stand-in for Keystone and the Nova, Cinder and Neutron endpoints of its catalog.
+// Tokens are only issued to the admin user of the Default domain, {{endpoint}} in responses is replaced with the URL of the stand-in
+func newTestCloud(t *testing.T, responses map[string]string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/identity/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Auth struct {
+				Identity struct {
+					Password struct {
+						User struct {
+							Name     string            `json:"name"`
+							Password string            `json:"password"`
+							Domain   map[string]string `json:"domain"`
+						} `json:"user"`
+					} `json:"password"`
+				} `json:"identity"`
+			} `json:"auth"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		user := req.Auth.Identity.Password.User
+		if user.Name != "admin" || user.Password != "secret" || user.Domain["name"] != "Default" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":{"code":401,"message":"The request you have made requires authentication.","title":"Unauthorized"}}`)
+			return
+		}
+		w.Header().Set("X-Subject-Token", "test-token")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, strings.Replace(`{"token":{"catalog":[
+			{"type":"compute","endpoints":[{"interface":"public","region":"RegionTwo","url":"{{endpoint}}/wrong"},
+				{"interface":"public","region":"RegionOne","url":"{{endpoint}}/compute/v2.1"}]},
+			{"type":"volumev3","endpoints":[{"interface":"public","region":"RegionOne","url":"{{endpoint}}/volume/v3/project"}]},
+			{"type":"network","endpoints":[{"interface":"public","region":"RegionOne","url":"{{endpoint}}/network/"}]}]}}`,
+			"{{endpoint}}", "http://"+r.Host, -1))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		key := r.URL.Path
+		if marker := r.URL.Query().Get("marker"); marker != "" {
+			key += "?marker=" + marker
+		}
+		body, ok := responses[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"itemNotFound":{"code":404,"message":"%s not found"}}`, key)
+			return
+		}
+		fmt.Fprint(w, strings.Replace(body, "{{endpoint}}", "http://"+r.Host, -1))
+	})
+	return httptest.NewServer(mux)
+}
+
+// writeCloudsFile writes a clouds.yaml holding a single cloud authenticating against server
+func writeCloudsFile(t *testing.T, dir string, server *httptest.Server) string {
+	path := filepath.Join(dir, "clouds.yaml")
+	clouds := fmt.Sprintf(`clouds:
+  test:
+    auth:
+      auth_url: %s/identity
+      username: admin
+      password: secret
+      project_name: demo
+    region_name: RegionOne
+`, server.URL)
+	if err := ioutil.WriteFile(path, []byte(clouds), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestOpenStackClient checks that the client authenticates against Keystone, picks the endpoints of its region and follows next links
+func TestOpenStackClient(t *testing.T) {
+	server := newTestCloud(t, map[string]string{
+		"/compute/v2.1/servers/detail": `{"servers":[{"id":"s-1","name":"web","flavor":{"original_name":"m1.small"},
+			"addresses":{"private":[{"addr":"10.0.0.5","version":4,"OS-EXT-IPS:type":"fixed"},
+				{"addr":"203.0.113.5","version":4,"OS-EXT-IPS:type":"floating"}]}}],
+			"servers_links":[{"rel":"next","href":"{{endpoint}}/compute/v2.1/servers/detail?marker=s-1"}]}`,
+		"/compute/v2.1/servers/detail?marker=s-1": `{"servers":[{"id":"s-2","name":"db","image":""}]}`,
+		"/volume/v3/project/volumes/detail":       `{"volumes":[{"id":"v-1","size":10,"attachments":[{"server_id":"s-1","device":"/dev/vdb"}]}]}`,
+		"/network/v2.0/ports":                     `{"ports":[{"id":"p-1","device_id":"s-1","fixed_ips":[{"subnet_id":"sn-1","ip_address":"10.0.0.5"}]}]}`,
+	})
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "openstack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cloud, err := LoadCloud(writeCloudsFile(t, dir, server), "")
+	if err != nil {
+		t.Fatalf("Failed to load cloud: %v", err)
+	}
+	if _, err := LoadCloud(filepath.Join(dir, "clouds.yaml"), "missing"); err == nil {
+		t.Errorf("Expected an unknown cloud to fail")
+	}
+	client, err := NewClient(cloud)
+	if err != nil {
+		t.Fatalf("Failed to authenticate: %v", err)
+	}
+
+	servers, err := client.GetAllServers()
+	if err != nil {
+		t.Fatalf("Failed to list servers: %v", err)
+	}
+	if len(servers) != 2 || servers[0].Flavor.OriginalName != "m1.small" {
+		t.Fatalf("Unexpected servers %+v", servers)
+	}
+	if servers[0].IPv4("floating") != "203.0.113.5" || servers[0].IPv4("fixed") != "10.0.0.5" || servers[1].IPv4("fixed") != "" {
+		t.Errorf("Unexpected addresses %+v", servers[0].Addresses)
+	}
+	volumes, err := client.GetAllVolumes()
+	if err != nil || len(volumes) != 1 || volumes[0].Attachments[0].ServerID != "s-1" {
+		t.Errorf("Unexpected volumes %+v: %v", volumes, err)
+	}
+	ports, err := client.GetAllPorts()
+	if err != nil || len(ports) != 1 || ports[0].FixedIPs[0].IPAddress != "10.0.0.5" {
+		t.Errorf("Unexpected ports %+v: %v", ports, err)
+	}
+
+	cloud.Auth.Password = "wrong"
+	_, err = NewClient(cloud)
+	osErr, ok := err.(*Error)
+	if !ok || osErr.StatusCode != http.StatusUnauthorized || !strings.Contains(osErr.Message, "requires authentication") {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+// TestAuthRequest checks that application credentials and domains are passed to Keystone
+func TestAuthRequest(t *testing.T) {
+	req, _ := json.Marshal(authRequest(CloudAuth{ApplicationCredentialID: "id", ApplicationCredentialSecret: "secret"}))
+	if !strings.Contains(string(req), `"methods":["application_credential"]`) || strings.Contains(string(req), "scope") {
+		t.Errorf("Unexpected application credential request %s", req)
+	}
+	req, _ = json.Marshal(authRequest(CloudAuth{Username: "u", Password: "p", ProjectID: "p-1", DomainID: "d-1"}))
+	if !strings.Contains(string(req), `"domain":{"id":"d-1"}`) || !strings.Contains(string(req), `"project":{"id":"p-1"}`) {
+		t.Errorf("Unexpected password request %s", req)
+	}
+}
+
+// TestRegion checks that clients without a region take the one of their compute endpoint, DefaultRegion without any
+func TestRegion(t *testing.T) {
+	client := &Client{iface: "public", catalog: []catalogService{{Type: "compute", Endpoints: []catalogEndpoint{
+		{Interface: "internal", RegionID: "RegionTwo"},
+		{Interface: "public", RegionID: "RegionOne"},
+	}}}}
+	if region := client.Region(); region != "RegionOne" {
+		t.Errorf("Expected the region of the public compute endpoint, got %q", region)
+	}
+	client.region = "RegionThree"
+	if region := client.Region(); region != "RegionThree" {
+		t.Errorf("Expected the region of the client, got %q", region)
+	}
+	client = &Client{iface: "public", catalog: []catalogService{{Type: "compute", Endpoints: []catalogEndpoint{{Interface: "public"}}}}}
+	if region := client.Region(); region != DefaultRegion {
+		t.Errorf("Expected %q, got %q", DefaultRegion, region)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package openstacklib
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Server holds a Nova server
+type Server struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	Status           string `json:"status"`
+	TenantID         string `json:"tenant_id"`
+	Created          string `json:"created"`
+	AvailabilityZone string `json:"OS-EXT-AZ:availability_zone"`
+	Flavor           struct {
+		OriginalName string `json:"original_name"`
+		VCPUs        int    `json:"vcpus"`
+		RAM          int    `json:"ram"`
+		Disk         int    `json:"disk"`
+	} `json:"flavor"`
+	// Image is an empty string for servers booted from a volume
+	Image     json.RawMessage            `json:"image"`
+	Addresses map[string][]ServerAddress `json:"addresses"`
+	Metadata  map[string]string          `json:"metadata"`
+}
+
+// ServerAddress holds an address of a server on one of its networks, Type is either fixed or floating
+type ServerAddress struct {
+	Addr    string `json:"addr"`
+	Version int    `json:"version"`
+	Type    string `json:"OS-EXT-IPS:type"`
+	MACAddr string `json:"OS-EXT-IPS-MAC:mac_addr"`
+}
+
+// IPv4 returns the first IPv4 address of the server with the given type, fixed or floating, networks are walked by name
+func (s *Server) IPv4(addressType string) string {
+	var networks []string
+	for n := range s.Addresses {
+		networks = append(networks, n)
+	}
+	sort.Strings(networks)
+	for _, n := range networks {
+		for _, a := range s.Addresses[n] {
+			if a.Version == 4 && a.Type == addressType {
+				return a.Addr
+			}
+		}
+	}
+	return ""
+}
+
+// Volume holds a Cinder volume
+type Volume struct {
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	Status           string            `json:"status"`
+	Size             int               `json:"size"`
+	VolumeType       string            `json:"volume_type"`
+	AvailabilityZone string            `json:"availability_zone"`
+	Bootable         string            `json:"bootable"`
+	Encrypted        bool              `json:"encrypted"`
+	CreatedAt        string            `json:"created_at"`
+	Metadata         map[string]string `json:"metadata"`
+	Attachments      []struct {
+		ServerID     string `json:"server_id"`
+		AttachmentID string `json:"attachment_id"`
+		Device       string `json:"device"`
+	} `json:"attachments"`
+}
+
+// Port holds a Neutron port
+type Port struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Status         string   `json:"status"`
+	ProjectID      string   `json:"project_id"`
+	NetworkID      string   `json:"network_id"`
+	MACAddress     string   `json:"mac_address"`
+	DeviceID       string   `json:"device_id"`
+	DeviceOwner    string   `json:"device_owner"`
+	SecurityGroups []string `json:"security_groups"`
+	FixedIPs       []struct {
+		SubnetID  string `json:"subnet_id"`
+		IPAddress string `json:"ip_address"`
+	} `json:"fixed_ips"`
+}
+
+// GetAllServers returns every Nova server of the project
+func (c *Client) GetAllServers() ([]*Server, error) {
+	var allServers []*Server
+	err := c.list([]string{"compute"}, "/servers/detail", "servers", func(items json.RawMessage) error {
+		var page []*Server
+		if err := json.Unmarshal(items, &page); err != nil {
+			return err
+		}
+		allServers = append(allServers, page...)
+		return nil
+	})
+	return allServers, err
+}
+
+// GetAllVolumes returns every Cinder volume of the project
+func (c *Client) GetAllVolumes() ([]*Volume, error) {
+	var allVolumes []*Volume
+	err := c.list([]string{"block-storage", "volumev3"}, "/volumes/detail", "volumes", func(items json.RawMessage) error {
+		var page []*Volume
+		if err := json.Unmarshal(items, &page); err != nil {
+			return err
+		}
+		allVolumes = append(allVolumes, page...)
+		return nil
+	})
+	return allVolumes, err
+}
+
+// GetAllPorts returns every Neutron port of the project
+func (c *Client) GetAllPorts() ([]*Port, error) {
+	var allPorts []*Port
+	err := c.list([]string{"network"}, "/v2.0/ports", "ports", func(items json.RawMessage) error {
+		var page []*Port
+		if err := json.Unmarshal(items, &page); err != nil {
+			return err
+		}
+		allPorts = append(allPorts, page...)
+		return nil
+	})
+	return allPorts, err
+}
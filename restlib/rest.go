@@ -0,0 +1,86 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+// Package restlib holds the HTTP helpers shared by the REST clients of the non AWS providers
+package restlib
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jpillora/backoff"
+)
+
+// MaxRetries is the number of times a throttled request is retried before its response is returned as is
+var MaxRetries = 6
+
+// maxWait caps the wait before retrying a throttled request, whatever the headers of the response ask for
+const maxWait = 30 * time.Second
+
+// Do sends the request built by newRequest through client, retrying it up to MaxRetries times while it is
+// throttled (HTTP 429). Waits follow an exponential backoff, or the Retry-After (seconds) and RateLimit-Reset
+// (epoch) headers when present. Returns the status code and body of the last response, leaving the handling
+// of errors, including a request still throttled, to the caller
+func Do(client *http.Client, newRequest func() (*http.Request, error)) (int, []byte, error) {
+	b := &backoff.Backoff{
+		Min:    time.Second,
+		Max:    maxWait,
+		Factor: 2,
+		Jitter: false,
+	}
+	for {
+		req, err := newRequest()
+		if err != nil {
+			return 0, nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, nil, err
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return 0, nil, err
+		}
+		if resp.StatusCode != http.StatusTooManyRequests || int(b.Attempt()) >= MaxRetries {
+			return resp.StatusCode, body, nil
+		}
+		time.Sleep(retryWait(resp.Header, b.Duration()))
+	}
+}
+
+// Get is Do for a plain GET of u
+func Get(client *http.Client, u string) (int, []byte, error) {
+	return Do(client, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, u, nil)
+	})
+}
+
+// retryWait returns how long to wait before retrying a throttled response with the given headers,
+// wait if they do not tell
+func retryWait(header http.Header, wait time.Duration) time.Duration {
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if reset, err := strconv.ParseInt(header.Get("RateLimit-Reset"), 10, 64); err == nil {
+		// The rate limit resets at the given epoch
+		wait = time.Until(time.Unix(reset, 0))
+	}
+	if wait < 0 {
+		return 0
+	}
+	if wait > maxWait {
+		return maxWait
+	}
+	return wait
+}
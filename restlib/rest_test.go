@@ -0,0 +1,65 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package restlib
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestGet checks that throttled requests are retried, and given up on after MaxRetries retries
+func TestGet(t *testing.T) {
+	var calls, throttle int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= throttle {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	throttle = 2
+	status, body, err := Get(server.Client(), server.URL)
+	if err != nil || status != http.StatusOK || string(body) != "ok" || calls != 3 {
+		t.Errorf("Throttled request was not retried: %d %q %v after %d calls", status, body, err, calls)
+	}
+
+	calls, throttle = 0, MaxRetries+10
+	status, _, err = Get(server.Client(), server.URL)
+	if err != nil || status != http.StatusTooManyRequests || calls != MaxRetries+1 {
+		t.Errorf("Expected a throttled response after %d calls, got %d after %d calls (%v)", MaxRetries+1, status, calls, err)
+	}
+}
+
+// TestRetryWait checks that the wait asked for by a response is honoured within bounds
+func TestRetryWait(t *testing.T) {
+	for _, testCase := range []struct {
+		header http.Header
+		want   time.Duration
+	}{
+		{http.Header{}, 2 * time.Second},
+		{http.Header{"Retry-After": {"5"}}, 5 * time.Second},
+		{http.Header{"Retry-After": {"3600"}}, maxWait},
+		{http.Header{"Ratelimit-Reset": {fmt.Sprint(time.Now().Add(-time.Minute).Unix())}}, 0},
+	} {
+		if wait := retryWait(testCase.header, 2*time.Second); wait != testCase.want {
+			t.Errorf("%v\tWant:%v\tHave:%v", testCase.header, testCase.want, wait)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package sink
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/adobe/cloudinventory/azurelib"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// azureStorageVersion is the Blob service REST API version, OAuth2 tokens need 2017-11-09 or later
+const azureStorageVersion = "2021-08-06"
+
+// azureBlob writes to an Azure Storage block blob
+type azureBlob struct {
+	endpoint  string
+	http      *http.Client
+	sasToken  string
+	account   string
+	container string
+	blob      string
+}
+
+// newAzureBlob returns the Sink of azblob://<account>/<container>/<blob>. It authenticates with the SAS token set in
+// AZURE_STORAGE_SAS_TOKEN, or as the service principal set in AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET
+func newAzureBlob(u *url.URL, opts Options) (Sink, error) {
+	account, path, err := objectPath(u)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("Invalid output location %s, expected azblob://<account>/<container>/<blob>", u)
+	}
+	b := &azureBlob{
+		endpoint:  fmt.Sprintf("https://%s.blob.core.windows.net", account),
+		account:   account,
+		container: parts[0],
+		blob:      parts[1],
+	}
+	if b.sasToken = strings.TrimPrefix(os.Getenv("AZURE_STORAGE_SAS_TOKEN"), "?"); b.sasToken != "" {
+		b.http = http.DefaultClient
+		return b, nil
+	}
+	tenantID, clientID, clientSecret := os.Getenv("AZURE_TENANT_ID"), os.Getenv("AZURE_CLIENT_ID"), os.Getenv("AZURE_CLIENT_SECRET")
+	if tenantID == "" || clientID == "" || clientSecret == "" {
+		return nil, fmt.Errorf("Failed to get Azure Storage Credentials, set AZURE_STORAGE_SAS_TOKEN or AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET")
+	}
+	config := clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     fmt.Sprintf("%s/%s/oauth2/v2.0/token", azurelib.LoginEndpoint, tenantID),
+		Scopes:       []string{"https://storage.azure.com/.default"},
+	}
+	b.http = config.Client(context.Background())
+	return b, nil
+}
+
+func (b *azureBlob) Write(data []byte) error {
+	u := fmt.Sprintf("%s/%s/%s", b.endpoint, url.PathEscape(b.container), escapeBlobName(b.blob))
+	if b.sasToken != "" {
+		u += "?" + b.sasToken
+	}
+	header := http.Header{}
+	header.Set("x-ms-blob-type", "BlockBlob")
+	header.Set("x-ms-version", azureStorageVersion)
+	return upload(b.http, http.MethodPut, u, header, data)
+}
+
+func (b *azureBlob) String() string {
+	return fmt.Sprintf("azblob://%s/%s/%s", b.account, b.container, b.blob)
+}
+
+// escapeBlobName escapes every segment of a blob name, keeping its virtual directories
+func escapeBlobName(name string) string {
+	segments := strings.Split(name, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package sink
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/oauth2/google"
+)
+
+// gcsScope only allows reading and writing objects, access remains restricted by the IAM roles of the credentials
+const gcsScope = "https://www.googleapis.com/auth/devstorage.read_write"
+
+// gcsObject writes to a Google Cloud Storage object with Application Default Credentials,
+// GOOGLE_APPLICATION_CREDENTIALS pointing to a service account key if set
+type gcsObject struct {
+	endpoint string
+	http     *http.Client
+	bucket   string
+	object   string
+}
+
+// newGCS returns the Sink of gs://<bucket>/<object>
+func newGCS(u *url.URL, opts Options) (Sink, error) {
+	bucket, object, err := objectPath(u)
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := google.DefaultClient(context.Background(), gcsScope)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get Google Cloud Application Default Credentials: %v", err)
+	}
+	return &gcsObject{endpoint: "https://storage.googleapis.com", http: httpClient, bucket: bucket, object: object}, nil
+}
+
+func (o *gcsObject) Write(data []byte) error {
+	u := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=media&name=%s", o.endpoint, url.PathEscape(o.bucket), url.QueryEscape(o.object))
+	return upload(o.http, http.MethodPost, u, nil, data)
+}
+
+func (o *gcsObject) String() string {
+	return fmt.Sprintf("gs://%s/%s", o.bucket, o.object)
+}
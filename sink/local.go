@@ -0,0 +1,44 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package sink
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// file writes to a local file, creating its missing parent directories
+type file struct {
+	path string
+}
+
+func newFile(u *url.URL, opts Options) (Sink, error) {
+	if u.Path == "" {
+		return nil, fmt.Errorf("Invalid output location %s, expected file:///<path>", u)
+	}
+	return file{path: filepath.FromSlash(u.Path)}, nil
+}
+
+func (f file) Write(data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(f.path, data, 0644)
+}
+
+func (f file) String() string {
+	return f.path
+}
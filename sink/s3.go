@@ -0,0 +1,91 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// s3Object writes to an S3 object, authenticating with the default AWS credential chain
+type s3Object struct {
+	client *s3.S3
+	bucket string
+	key    string
+	opts   Options
+}
+
+// newS3 returns the Sink of s3://<bucket>/<key>. The bucket region is looked up unless an S3 compatible endpoint is used
+func newS3(u *url.URL, opts Options) (Sink, error) {
+	bucket, key, err := objectPath(u)
+	if err != nil {
+		return nil, err
+	}
+	switch opts.ServerSideEncryption {
+	case "", s3.ServerSideEncryptionAes256:
+		if opts.KMSKeyID != "" {
+			return nil, fmt.Errorf("A KMS key can only be used with %s server side encryption", s3.ServerSideEncryptionAwsKms)
+		}
+	case s3.ServerSideEncryptionAwsKms:
+	default:
+		return nil, fmt.Errorf("Invalid server side encryption %s, select %s/%s", opts.ServerSideEncryption, s3.ServerSideEncryptionAes256, s3.ServerSideEncryptionAwsKms)
+	}
+
+	config := aws.NewConfig()
+	if opts.S3Endpoint != "" {
+		config = config.WithEndpoint(opts.S3Endpoint).WithS3ForcePathStyle(true)
+	}
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to build AWS Session: %v", err)
+	}
+	if aws.StringValue(sess.Config.Region) == "" {
+		sess.Config.Region = aws.String("us-east-1")
+	}
+	if opts.S3Endpoint == "" {
+		region, err := s3manager.GetBucketRegion(context.Background(), sess, bucket, aws.StringValue(sess.Config.Region))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to find the region of bucket %s: %v", bucket, err)
+		}
+		sess.Config.Region = aws.String(region)
+	}
+	return &s3Object{client: s3.New(sess), bucket: bucket, key: key, opts: opts}, nil
+}
+
+func (o *s3Object) Write(data []byte) error {
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(o.bucket),
+		Key:         aws.String(o.key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	}
+	if o.opts.ServerSideEncryption != "" {
+		input.ServerSideEncryption = aws.String(o.opts.ServerSideEncryption)
+	}
+	if o.opts.KMSKeyID != "" {
+		input.SSEKMSKeyId = aws.String(o.opts.KMSKeyID)
+	}
+	_, err := o.client.PutObject(input)
+	return err
+}
+
+func (o *s3Object) String() string {
+	return fmt.Sprintf("s3://%s/%s", o.bucket, o.key)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+// Package sink writes inventory documents to local files or object storage, picked by the scheme of their location
+package sink
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Sink writes a complete document to a single destination, replacing any previous content
+type Sink interface {
+	Write(data []byte) error
+	// String returns the location written to
+	String() string
+}
+
+// Options hold the settings of the destinations supporting them, other destinations ignore them
+type Options struct {
+	// ServerSideEncryption is the server side encryption of S3 objects, AES256 or aws:kms. Bucket defaults apply if empty
+	ServerSideEncryption string
+	// KMSKeyID is the KMS key encrypting S3 objects with aws:kms, the AWS managed key if empty
+	KMSKeyID string
+	// S3Endpoint replaces the AWS endpoint for S3 compatible stores, buckets are then addressed by path
+	S3Endpoint string
+}
+
+// Factory returns the Sink writing to u
+type Factory func(u *url.URL, opts Options) (Sink, error)
+
+var factories = map[string]Factory{
+	"file":   newFile,
+	"s3":     newS3,
+	"gs":     newGCS,
+	"azblob": newAzureBlob,
+}
+
+// Register makes the destinations with the given URL scheme available to New, replacing any previous Factory for it
+func Register(scheme string, factory Factory) {
+	factories[strings.ToLower(scheme)] = factory
+}
+
+// New returns the Sink for location, a URL whose scheme was registered or a local file path
+func New(location string, opts Options) (Sink, error) {
+	// Plain paths, including Windows ones such as C:\inventory.json, are local files
+	if !strings.Contains(location, "://") {
+		return file{path: location}, nil
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid output location %s: %v", location, err)
+	}
+	factory, ok := factories[strings.ToLower(u.Scheme)]
+	if !ok {
+		return nil, fmt.Errorf("Unsupported output location %s, no sink for the %s scheme", location, u.Scheme)
+	}
+	return factory(u, opts)
+}
+
+// Expand returns location with {{date}} replaced with the UTC date of t as 2006-01-02,
+// and {{time}} with its UTC time of day as 150405
+func Expand(location string, t time.Time) (string, error) {
+	t = t.UTC()
+	tmpl, err := template.New("location").Funcs(template.FuncMap{
+		"date": func() string { return t.Format("2006-01-02") },
+		"time": func() string { return t.Format("150405") },
+	}).Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("Invalid output location %s: %v", location, err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, nil); err != nil {
+		return "", fmt.Errorf("Invalid output location %s: %v", location, err)
+	}
+	return b.String(), nil
+}
+
+// objectPath splits the host and path of u into a bucket and an object name, both of which are required
+func objectPath(u *url.URL) (string, string, error) {
+	bucket, object := u.Host, strings.TrimPrefix(u.Path, "/")
+	if bucket == "" || object == "" || strings.HasSuffix(object, "/") {
+		return "", "", fmt.Errorf("Invalid output location %s, expected %s://<bucket>/<object>", u, u.Scheme)
+	}
+	return bucket, object, nil
+}
+
+// upload sends data to u with method through client, failing unless the response is successful
+func upload(client *http.Client, method, u string, header http.Header, data []byte) error {
+	req, err := http.NewRequest(method, u, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("Upload failed with status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package sink
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testUpload is a request received by a storage stand-in
+type testUpload struct {
+	method string
+	uri    string
+	header http.Header
+	body   string
+}
+
+// newTestStore returns a local stand-in for an object store, recording every request and answering with status
+func newTestStore(t *testing.T, status int, uploads *[]testUpload) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Failed to read upload: %v", err)
+		}
+		mu.Lock()
+		*uploads = append(*uploads, testUpload{method: r.Method, uri: r.URL.RequestURI(), header: r.Header, body: string(body)})
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+}
+
+// TestExpand checks the date placeholders of locations
+func TestExpand(t *testing.T) {
+	now := time.Date(2026, 9, 1, 23, 4, 5, 0, time.FixedZone("PDT", -7*3600))
+	location, err := Expand("s3://bucket/{{date}}/inventory-{{time}}.json", now)
+	if err != nil {
+		t.Fatalf("Failed to expand location: %v", err)
+	}
+	if location != "s3://bucket/2026-09-02/inventory-060405.json" {
+		t.Errorf("Unexpected location %s", location)
+	}
+	if _, err := Expand("inventory-{{hour}}.json", now); err == nil {
+		t.Errorf("Unknown placeholders should be rejected")
+	}
+}
+
+// TestNew checks that locations are dispatched by scheme and validated
+func TestNew(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "2026-09-01", "inventory.json")
+	s, err := New(path, Options{})
+	if err != nil {
+		t.Fatalf("Failed to create local sink: %v", err)
+	}
+	if err := s.Write([]byte(`{}`)); err != nil {
+		t.Fatalf("Failed to write local file: %v", err)
+	}
+	if data, err := ioutil.ReadFile(path); err != nil || string(data) != `{}` {
+		t.Errorf("Unexpected file content %q: %v", data, err)
+	}
+
+	t.Setenv("AZURE_STORAGE_SAS_TOKEN", "?sv=2021-08-06&sig=test")
+	if s, err := New("azblob://account/container/dir/inventory.json", Options{}); err != nil || s.String() != "azblob://account/container/dir/inventory.json" {
+		t.Errorf("Unexpected Azure Blob sink %v: %v", s, err)
+	}
+	for _, location := range []string{
+		"ftp://host/inventory.json",
+		"s3://bucket",
+		"s3://bucket/prefix/",
+		"azblob://account/container",
+		"file://",
+	} {
+		if _, err := New(location, Options{}); err == nil {
+			t.Errorf("Location %s should be rejected", location)
+		}
+	}
+	for _, opts := range []Options{{ServerSideEncryption: "aws:kms256"}, {ServerSideEncryption: "AES256", KMSKeyID: "alias/inventory"}} {
+		if _, err := New("s3://bucket/inventory.json", opts); err == nil {
+			t.Errorf("Options %+v should be rejected", opts)
+		}
+	}
+}
+
+// TestS3 checks that objects are written with the requested server side encryption to an S3 compatible store
+func TestS3(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_REGION", "eu-west-1")
+	var uploads []testUpload
+	server := newTestStore(t, http.StatusOK, &uploads)
+	defer server.Close()
+
+	s, err := New("s3://inventory/daily/2026-09-01.json", Options{
+		ServerSideEncryption: "aws:kms",
+		KMSKeyID:             "alias/inventory",
+		S3Endpoint:           server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create S3 sink: %v", err)
+	}
+	if s.String() != "s3://inventory/daily/2026-09-01.json" {
+		t.Errorf("Unexpected location %s", s)
+	}
+	if err := s.Write([]byte(`{"ec2":{}}`)); err != nil {
+		t.Fatalf("Failed to write S3 object: %v", err)
+	}
+	if len(uploads) != 1 {
+		t.Fatalf("Expected a single upload, got %+v", uploads)
+	}
+	u := uploads[0]
+	if u.method != http.MethodPut || u.uri != "/inventory/daily/2026-09-01.json" || u.body != `{"ec2":{}}` {
+		t.Errorf("Unexpected upload %s %s %s", u.method, u.uri, u.body)
+	}
+	if u.header.Get("X-Amz-Server-Side-Encryption") != "aws:kms" || u.header.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id") != "alias/inventory" {
+		t.Errorf("Unexpected encryption headers %v", u.header)
+	}
+	if !strings.Contains(u.header.Get("Authorization"), "/eu-west-1/s3/") {
+		t.Errorf("Request not signed for the configured region: %s", u.header.Get("Authorization"))
+	}
+
+	s, err = New("s3://inventory/latest.json", Options{S3Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create S3 sink: %v", err)
+	}
+	if err := s.Write([]byte(`{}`)); err != nil {
+		t.Fatalf("Failed to write S3 object: %v", err)
+	}
+	if len(uploads) != 2 || uploads[1].header.Get("X-Amz-Server-Side-Encryption") != "" {
+		t.Errorf("Bucket default encryption should apply without options: %+v", uploads)
+	}
+}
+
+// TestGCSAndAzureBlob checks the uploads of the REST based sinks and that failures are surfaced
+func TestGCSAndAzureBlob(t *testing.T) {
+	var uploads []testUpload
+	server := newTestStore(t, http.StatusCreated, &uploads)
+	defer server.Close()
+
+	gcs := &gcsObject{endpoint: server.URL, http: server.Client(), bucket: "inventory", object: "daily/2026 09 01.json"}
+	if err := gcs.Write([]byte(`{}`)); err != nil {
+		t.Fatalf("Failed to write GCS object: %v", err)
+	}
+	blob := &azureBlob{endpoint: server.URL, http: server.Client(), sasToken: "sig=test", container: "inventory", blob: "daily/2026 09 01.json"}
+	if err := blob.Write([]byte(`{}`)); err != nil {
+		t.Fatalf("Failed to write blob: %v", err)
+	}
+	if len(uploads) != 2 {
+		t.Fatalf("Expected two uploads, got %+v", uploads)
+	}
+	if u := uploads[0]; u.method != http.MethodPost || u.uri != "/upload/storage/v1/b/inventory/o?uploadType=media&name=daily%2F2026+09+01.json" {
+		t.Errorf("Unexpected GCS upload %s %s", u.method, u.uri)
+	}
+	u := uploads[1]
+	if u.method != http.MethodPut || u.uri != "/inventory/daily/2026%2009%2001.json?sig=test" || u.header.Get("X-Ms-Blob-Type") != "BlockBlob" {
+		t.Errorf("Unexpected blob upload %s %s %v", u.method, u.uri, u.header)
+	}
+
+	failing := newTestStore(t, http.StatusForbidden, &uploads)
+	defer failing.Close()
+	gcs.endpoint = failing.URL
+	if err := gcs.Write([]byte(`{}`)); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected the upload to fail with 403, got %v", err)
+	}
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package vspherelib
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// VirtualMachine holds the summary of a vSphere virtual machine, its host and datastores resolved to their names
+type VirtualMachine struct {
+	Datacenter    string
+	Name          string
+	InstanceUUID  string
+	UUID          string
+	Template      bool
+	PowerState    string
+	GuestFullName string
+	NumCPU        int32
+	MemoryMB      int32
+	IPAddress     string
+	HostName      string
+	Host          string
+	Datastores    []string
+	Annotation    string
+}
+
+// HostSystem holds the summary of an ESXi host
+type HostSystem struct {
+	Datacenter      string
+	Name            string
+	Product         string
+	Vendor          string
+	Model           string
+	CPUModel        string
+	NumCPUCores     int16
+	MemoryBytes     int64
+	ConnectionState string
+	PowerState      string
+	InMaintenance   bool
+}
+
+// Datastore holds the summary of a datastore
+type Datastore struct {
+	Datacenter      string
+	Name            string
+	Type            string
+	URL             string
+	Capacity        int64
+	FreeSpace       int64
+	Accessible      bool
+	MaintenanceMode string
+}
+
+// NewClient returns a client logged into the vCenter or ESXi SDK at rawURL.
+// Credentials can be given as part of rawURL, username and password override them when set
+func NewClient(ctx context.Context, rawURL, username, password string, insecure bool) (*vim25.Client, error) {
+	u, err := soap.ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, fmt.Errorf("No vSphere URL given")
+	}
+	if username != "" {
+		u.User = url.UserPassword(username, password)
+	}
+	c, err := govmomi.NewClient(ctx, u, insecure)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client, nil
+}
+
+// Logout ends the session of a client returned by NewClient, the client is unusable afterwards
+func Logout(ctx context.Context, c *vim25.Client) error {
+	return session.NewManager(c).Logout(ctx)
+}
+
+// NewClientFromEnv returns a client configured by the GOVC_URL, GOVC_USERNAME, GOVC_PASSWORD and GOVC_INSECURE
+// environment variables also used by govc
+func NewClientFromEnv(ctx context.Context) (*vim25.Client, error) {
+	rawURL := os.Getenv("GOVC_URL")
+	if rawURL == "" {
+		return nil, fmt.Errorf("Failed to get vSphere Credentials, set GOVC_URL, GOVC_USERNAME and GOVC_PASSWORD")
+	}
+	insecure, _ := strconv.ParseBool(os.Getenv("GOVC_INSECURE"))
+	return NewClient(ctx, rawURL, os.Getenv("GOVC_USERNAME"), os.Getenv("GOVC_PASSWORD"), insecure)
+}
+
+// GetAllVMs returns every virtual machine and template of every datacenter
+func GetAllVMs(ctx context.Context, c *vim25.Client) ([]*VirtualMachine, error) {
+	hosts, err := referenceNames(ctx, c, "HostSystem")
+	if err != nil {
+		return nil, err
+	}
+	datastores, err := referenceNames(ctx, c, "Datastore")
+	if err != nil {
+		return nil, err
+	}
+	var allVMs []*VirtualMachine
+	err = eachDatacenter(ctx, c, "VirtualMachine", func(dc string, v *view.ContainerView) error {
+		var vms []mo.VirtualMachine
+		if err := v.Retrieve(ctx, []string{"VirtualMachine"}, []string{"summary", "datastore"}, &vms); err != nil {
+			return err
+		}
+		for _, vm := range vms {
+			s := vm.Summary
+			machine := &VirtualMachine{
+				Datacenter:    dc,
+				Name:          s.Config.Name,
+				InstanceUUID:  s.Config.InstanceUuid,
+				UUID:          s.Config.Uuid,
+				Template:      s.Config.Template,
+				PowerState:    string(s.Runtime.PowerState),
+				GuestFullName: s.Config.GuestFullName,
+				NumCPU:        s.Config.NumCpu,
+				MemoryMB:      s.Config.MemorySizeMB,
+				Annotation:    s.Config.Annotation,
+			}
+			if s.Guest != nil {
+				machine.IPAddress = s.Guest.IpAddress
+				machine.HostName = s.Guest.HostName
+			}
+			if s.Runtime.Host != nil {
+				machine.Host = hosts[*s.Runtime.Host]
+			}
+			for _, ds := range vm.Datastore {
+				machine.Datastores = append(machine.Datastores, datastores[ds])
+			}
+			allVMs = append(allVMs, machine)
+		}
+		return nil
+	})
+	return allVMs, err
+}
+
+// GetAllHosts returns every ESXi host of every datacenter
+func GetAllHosts(ctx context.Context, c *vim25.Client) ([]*HostSystem, error) {
+	var allHosts []*HostSystem
+	err := eachDatacenter(ctx, c, "HostSystem", func(dc string, v *view.ContainerView) error {
+		var hosts []mo.HostSystem
+		if err := v.Retrieve(ctx, []string{"HostSystem"}, []string{"summary"}, &hosts); err != nil {
+			return err
+		}
+		for _, h := range hosts {
+			s := h.Summary
+			host := &HostSystem{
+				Datacenter:      dc,
+				Name:            s.Config.Name,
+				ConnectionState: string(s.Runtime.ConnectionState),
+				PowerState:      string(s.Runtime.PowerState),
+				InMaintenance:   s.Runtime.InMaintenanceMode,
+			}
+			if s.Config.Product != nil {
+				host.Product = s.Config.Product.FullName
+			}
+			if s.Hardware != nil {
+				host.Vendor = s.Hardware.Vendor
+				host.Model = s.Hardware.Model
+				host.CPUModel = s.Hardware.CpuModel
+				host.NumCPUCores = s.Hardware.NumCpuCores
+				host.MemoryBytes = s.Hardware.MemorySize
+			}
+			allHosts = append(allHosts, host)
+		}
+		return nil
+	})
+	return allHosts, err
+}
+
+// GetAllDatastores returns every datastore of every datacenter
+func GetAllDatastores(ctx context.Context, c *vim25.Client) ([]*Datastore, error) {
+	var allDatastores []*Datastore
+	err := eachDatacenter(ctx, c, "Datastore", func(dc string, v *view.ContainerView) error {
+		var datastores []mo.Datastore
+		if err := v.Retrieve(ctx, []string{"Datastore"}, []string{"summary"}, &datastores); err != nil {
+			return err
+		}
+		for _, d := range datastores {
+			s := d.Summary
+			allDatastores = append(allDatastores, &Datastore{
+				Datacenter:      dc,
+				Name:            s.Name,
+				Type:            s.Type,
+				URL:             s.Url,
+				Capacity:        s.Capacity,
+				FreeSpace:       s.FreeSpace,
+				Accessible:      s.Accessible,
+				MaintenanceMode: s.MaintenanceMode,
+			})
+		}
+		return nil
+	})
+	return allDatastores, err
+}
+
+// eachDatacenter runs retrieve with a container view of the objects of the given kind of every datacenter
+func eachDatacenter(ctx context.Context, c *vim25.Client, kind string, retrieve func(dc string, v *view.ContainerView) error) error {
+	datacenters, err := find.NewFinder(c).DatacenterList(ctx, "*")
+	if err != nil {
+		return err
+	}
+	m := view.NewManager(c)
+	for _, dc := range datacenters {
+		v, err := m.CreateContainerView(ctx, dc.Reference(), []string{kind}, true)
+		if err != nil {
+			return err
+		}
+		err = retrieve(dc.Name(), v)
+		v.Destroy(ctx)
+		if err != nil {
+			return fmt.Errorf("Datacenter %s: %v", dc.Name(), err)
+		}
+	}
+	return nil
+}
+
+// referenceNames returns the names of every object of the given kind, HostSystem or Datastore, by reference
+func referenceNames(ctx context.Context, c *vim25.Client, kind string) (map[types.ManagedObjectReference]string, error) {
+	v, err := view.NewManager(c).CreateContainerView(ctx, c.ServiceContent.RootFolder, []string{kind}, true)
+	if err != nil {
+		return nil, err
+	}
+	defer v.Destroy(ctx)
+	names := make(map[types.ManagedObjectReference]string)
+	switch kind {
+	case "HostSystem":
+		var hosts []mo.HostSystem
+		if err := v.Retrieve(ctx, []string{kind}, []string{"name"}, &hosts); err != nil {
+			return nil, err
+		}
+		for _, h := range hosts {
+			names[h.Self] = h.Name
+		}
+	case "Datastore":
+		var datastores []mo.Datastore
+		if err := v.Retrieve(ctx, []string{kind}, []string{"name"}, &datastores); err != nil {
+			return nil, err
+		}
+		for _, d := range datastores {
+			names[d.Self] = d.Name
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported kind %s", kind)
+	}
+	return names, nil
+}
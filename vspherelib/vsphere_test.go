@@ -0,0 +1,64 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package vspherelib
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+)
+
+// TestInventory lists the VMs, hosts and datastores of a simulated vCenter with two datacenters
+func TestInventory(t *testing.T) {
+	model := simulator.VPX()
+	model.Datacenter = 2
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vms, err := GetAllVMs(ctx, c)
+		if err != nil {
+			t.Fatalf("Failed to list VMs: %v", err)
+		}
+		// Each datacenter holds 2 VMs on its standalone host and 2 in its cluster
+		if len(vms) != 8 {
+			t.Fatalf("Expected 8 VMs, got %d", len(vms))
+		}
+		datacenters := make(map[string]bool)
+		for _, vm := range vms {
+			datacenters[vm.Datacenter] = true
+			if vm.Name == "" || vm.InstanceUUID == "" || vm.Host == "" || len(vm.Datastores) == 0 || vm.Datastores[0] == "" {
+				t.Errorf("VM is missing details %+v", vm)
+			}
+		}
+		if len(datacenters) != 2 {
+			t.Errorf("Expected VMs across 2 datacenters, got %v", datacenters)
+		}
+
+		hosts, err := GetAllHosts(ctx, c)
+		if err != nil {
+			t.Fatalf("Failed to list hosts: %v", err)
+		}
+		// Each datacenter holds a standalone host and a cluster of 3
+		if len(hosts) != 8 || hosts[0].Name == "" || hosts[0].ConnectionState != "connected" || hosts[0].NumCPUCores == 0 {
+			t.Errorf("Unexpected hosts %+v", hosts)
+		}
+
+		datastores, err := GetAllDatastores(ctx, c)
+		if err != nil {
+			t.Fatalf("Failed to list datastores: %v", err)
+		}
+		if len(datastores) != 2 || !datastores[0].Accessible || datastores[0].Capacity == 0 {
+			t.Errorf("Unexpected datastores %+v", datastores)
+		}
+	}, model)
+}